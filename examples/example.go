@@ -248,12 +248,15 @@ func ExampleCustomProvider() {
 
 // CustomLogger 自定义日志实现
 type CustomLogger struct {
+	LandcLogFace.LoggerInfoMixin
 	name string
 }
 
 // NewCustomLogger 创建自定义日志实例
 func NewCustomLogger(name string) *CustomLogger {
-	return &CustomLogger{name: name}
+	c := &CustomLogger{name: name}
+	c.LoggerInfo = LandcLogFace.LoggerInfo{Name: name, Provider: "custom", Level: LandcLogFace.InfoLevel}
+	return c
 }
 
 // SetLevel 设置日志级别
@@ -326,6 +329,36 @@ func (c *CustomLogger) Panicf(format string, args ...interface{}) {
 	fmt.Printf("[CUSTOM] [PANIC] [%s] "+format+"\n", append([]interface{}{c.name}, args...)...)
 }
 
+// DebugCtx 使用ctx中提取的字段输出调试级日志
+func (c *CustomLogger) DebugCtx(ctx context.Context, msg string, fields ...LandcLogFace.Field) {
+	fmt.Printf("[CUSTOM] [DEBUG] [%s] %s\n", c.name, msg)
+}
+
+// InfoCtx 使用ctx中提取的字段输出信息级日志
+func (c *CustomLogger) InfoCtx(ctx context.Context, msg string, fields ...LandcLogFace.Field) {
+	fmt.Printf("[CUSTOM] [INFO] [%s] %s\n", c.name, msg)
+}
+
+// WarnCtx 使用ctx中提取的字段输出警告级日志
+func (c *CustomLogger) WarnCtx(ctx context.Context, msg string, fields ...LandcLogFace.Field) {
+	fmt.Printf("[CUSTOM] [WARN] [%s] %s\n", c.name, msg)
+}
+
+// ErrorCtx 使用ctx中提取的字段输出错误级日志
+func (c *CustomLogger) ErrorCtx(ctx context.Context, msg string, fields ...LandcLogFace.Field) {
+	fmt.Printf("[CUSTOM] [ERROR] [%s] %s\n", c.name, msg)
+}
+
+// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+func (c *CustomLogger) FatalCtx(ctx context.Context, msg string, fields ...LandcLogFace.Field) {
+	fmt.Printf("[CUSTOM] [FATAL] [%s] %s\n", c.name, msg)
+}
+
+// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+func (c *CustomLogger) PanicCtx(ctx context.Context, msg string, fields ...LandcLogFace.Field) {
+	fmt.Printf("[CUSTOM] [PANIC] [%s] %s\n", c.name, msg)
+}
+
 // WithFields 添加字段到日志
 func (c *CustomLogger) WithFields(fields ...LandcLogFace.Field) LandcLogFace.Logger {
 	return c
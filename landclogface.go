@@ -1,12 +1,20 @@
 package LandcLogFace
 
 import (
+	"context"
+	"io"
+	"log"
+	"reflect"
+	"regexp"
 	"time"
 
 	"github.com/LandcLi/LandcLogFace/pkg/adapters"
 	"github.com/LandcLi/LandcLogFace/pkg/logger"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // 导入核心包
@@ -28,9 +36,131 @@ type Option = logger.Option
 // LoggerOptions 日志配置选项
 type LoggerOptions = logger.LoggerOptions
 
+// LoggerInfo 日志实例的有效配置信息
+type LoggerInfo = logger.LoggerInfo
+
+// LoggerInfoMixin Describe方法的默认实现，自定义日志实例可嵌入并填充LoggerInfo
+type LoggerInfoMixin = logger.LoggerInfoMixin
+
+// LoggerStats 日志输出统计
+type LoggerStats = logger.LoggerStats
+
+// StatsReporter 支持输出统计的日志实例
+type StatsReporter = logger.StatsReporter
+
+// Cloner 支持以新名称复制配置的日志实例
+type Cloner = logger.Cloner
+
+// LevelEnabler 支持按级别检查是否启用的日志实例
+type LevelEnabler = logger.LevelEnabler
+
+// TimerStarter 支持重置uptime字段起始时间的日志实例
+type TimerStarter = logger.TimerStarter
+
+// SamplingResetter 支持清空限流状态的日志实例
+type SamplingResetter = logger.SamplingResetter
+
+// OutputSettable 支持在运行时替换输出目标的日志实例
+type OutputSettable = logger.OutputSettable
+
+// Clock 时钟接口，用于获取日志时间
+type Clock = logger.Clock
+
+// ContextExtractor 从上下文中提取日志字段
+type ContextExtractor = logger.ContextExtractor
+
+// Entry 一条日志记录
+type Entry = logger.Entry
+
+// JournalLogger 转发日志并将日志记录追加到文件的包装器
+type JournalLogger = logger.JournalLogger
+
+// SynchronizedWriter 串行化写入的输出，保证并发输出的日志行不互相穿插
+type SynchronizedWriter = logger.SynchronizedWriter
+
+// AuditLogger 输出合规审计日志的包装器
+type AuditLogger = logger.AuditLogger
+
+// FuncLogger 将日志分发给回调函数的日志实例
+type FuncLogger = logger.FuncLogger
+
+// EmitFunc 接收一条日志的回调函数
+type EmitFunc = logger.EmitFunc
+
+// PipeWriter 将日志记录以JSON行写入管道的写入器
+type PipeWriter = logger.PipeWriter
+
+// Stopwatch 记录多个命名检查点的计时器
+type Stopwatch = logger.Stopwatch
+
+// PipeReader 从管道读取日志记录并重新输出的读取器
+type PipeReader = logger.PipeReader
+
+// SeverityCodeMap 日志级别到数值级别的映射
+type SeverityCodeMap = logger.SeverityCodeMap
+
+// LevelSampling 按概率采样的配置
+type LevelSampling = logger.LevelSampling
+
+// RedactPattern 脱敏规则
+type RedactPattern = logger.RedactPattern
+
+// FieldRateLimit 按字段值限流的规则
+type FieldRateLimit = logger.FieldRateLimit
+
+// BuildInfo 程序的构建信息
+type BuildInfo = logger.BuildInfo
+
+// LevelEncoder 日志级别编码函数
+type LevelEncoder = logger.LevelEncoder
+
+// StackPredicate 条件堆栈判断函数
+type StackPredicate = logger.StackPredicate
+
+// EntryHook 日志钩子，在每条日志的字段合并处理完成后、输出之前调用
+type EntryHook = logger.EntryHook
+
+// LevelValue 实现flag.Value的日志级别
+type LevelValue = logger.LevelValue
+
+// LevelColorMap 日志级别到ANSI颜色的映射
+type LevelColorMap = logger.LevelColorMap
+
+// ErrorFormatter 错误字段格式化函数
+type ErrorFormatter = logger.ErrorFormatter
+
+// MarshalFunc 字段值转换函数
+type MarshalFunc = logger.MarshalFunc
+
+// MarshalerMap 按字段值类型索引的转换函数
+type MarshalerMap = logger.MarshalerMap
+
+// ReservedKeyPolicy 字段键与保留键冲突时的处理策略
+type ReservedKeyPolicy = logger.ReservedKeyPolicy
+
+// 导出保留键冲突策略常量
+const (
+	ReservedKeyRename    ReservedKeyPolicy = logger.ReservedKeyRename
+	ReservedKeyOverwrite ReservedKeyPolicy = logger.ReservedKeyOverwrite
+	ReservedKeyWarn      ReservedKeyPolicy = logger.ReservedKeyWarn
+)
+
 // LogConfig 统一的日志配置类
 type LogConfig = logger.LogConfig
 
+// MissingProviderPolicy 请求的日志提供者未注册时的处理策略
+type MissingProviderPolicy = logger.MissingProviderPolicy
+
+// 导出缺失提供者处理策略常量
+const (
+	MissingProviderWarn   MissingProviderPolicy = logger.MissingProviderWarn
+	MissingProviderError  MissingProviderPolicy = logger.MissingProviderError
+	MissingProviderSilent MissingProviderPolicy = logger.MissingProviderSilent
+)
+
+// ErrProviderUnavailable 请求的日志提供者未注册
+var ErrProviderUnavailable = logger.ErrProviderUnavailable
+
 // 导出日志级别常量
 const (
 	DebugLevel LogLevel = logger.DebugLevel
@@ -39,10 +169,38 @@ const (
 	ErrorLevel LogLevel = logger.ErrorLevel
 	FatalLevel LogLevel = logger.FatalLevel
 	PanicLevel LogLevel = logger.PanicLevel
+
+	// AuditLevel 审计日志使用的固定级别（错误级）
+	AuditLevel LogLevel = logger.AuditLevel
 )
 
 // 导出核心函数
 
+// NewStopwatch 创建从当前时间开始计时的计时器，clock为nil时使用系统时钟
+func NewStopwatch(clock Clock) *Stopwatch {
+	return logger.NewStopwatch(clock)
+}
+
+// ParseLevel 解析日志级别名称，不区分大小写
+func ParseLevel(s string) (LogLevel, error) {
+	return logger.ParseLevel(s)
+}
+
+// ParseLevelOrDefault 解析日志级别名称，为空或无法解析时返回def
+func ParseLevelOrDefault(s string, def LogLevel) LogLevel {
+	return logger.ParseLevelOrDefault(s, def)
+}
+
+// WithTemporaryLevel 以level级别运行fn，fn收到从l派生的日志实例，l本身的级别不受影响
+func WithTemporaryLevel(l Logger, level LogLevel, fn func(Logger)) {
+	logger.WithTemporaryLevel(l, level, fn)
+}
+
+// LevelFlag 在flag.CommandLine上定义日志级别参数，返回保存解析结果的指针
+func LevelFlag(name string, value LogLevel, usage string) *LogLevel {
+	return logger.LevelFlag(name, value, usage)
+}
+
 // GetLogFactory 获取全局日志工厂实例
 func GetLogFactory() *logger.LogFactory {
 	return logger.GetLogFactory()
@@ -53,16 +211,21 @@ func GetLogger() Logger {
 	return logger.GetLogger()
 }
 
-// GetLoggerWithName 获取指定名称的日志实例
+// GetLoggerWithName 获取指定名称的日志实例，相同名称的重复请求返回同一个实例
 func GetLoggerWithName(name string) Logger {
 	return logger.GetLoggerWithName(name)
 }
 
-// GetLoggerWithProvider 获取指定提供者的日志实例
+// GetLoggerWithProvider 获取指定提供者的日志实例，相同名称和提供者的重复请求返回同一个实例
 func GetLoggerWithProvider(name string, provider string) Logger {
 	return logger.GetLoggerWithProvider(name, provider)
 }
 
+// ClearLoggerCache 清空日志实例缓存，之后的请求会重新创建日志实例
+func ClearLoggerCache() {
+	logger.ClearLoggerCache()
+}
+
 // GetLoggerWithConfig 根据配置获取日志实例
 func GetLoggerWithConfig(name string, config map[string]interface{}) Logger {
 	return logger.GetLoggerWithConfig(name, config)
@@ -73,11 +236,151 @@ func GetLoggerWithLogConfig(config *LogConfig) Logger {
 	return logger.GetLoggerWithLogConfig(config)
 }
 
+// SetBuildInfo 设置全局构建信息，开启WithBuildInfoFields后新创建的日志实例会附加对应字段
+func SetBuildInfo(version, commit, buildTime string) {
+	logger.SetBuildInfo(version, commit, buildTime)
+}
+
+// GetBuildInfo 获取全局构建信息
+func GetBuildInfo() BuildInfo {
+	return logger.GetBuildInfo()
+}
+
 // SetGlobalLogger 设置全局日志实例
 func SetGlobalLogger(log Logger) {
 	logger.SetGlobalLogger(log)
 }
 
+// Group 创建嵌套字段，JSON中输出为嵌套对象，文本中输出为点分隔的键
+func Group(key string, fields ...Field) Field {
+	return logger.Group(key, fields...)
+}
+
+// Hashed 创建以稳定哈希代替原值输出的字段，用于假名化邮箱、IP等标识，相同的值得到相同的哈希
+func Hashed(key string, value interface{}) Field {
+	return logger.Hashed(key, value)
+}
+
+// NewFuncLogger 创建将日志分发给emit的日志实例，可用于桥接任意第三方日志库
+func NewFuncLogger(name string, level LogLevel, emit EmitFunc, opts ...Option) *FuncLogger {
+	return logger.NewFuncLogger(name, level, emit, opts...)
+}
+
+// NewLoggerFromZap 包装已有的zap日志实例，输出仍由该实例负责，门面负责级别过滤和字段处理
+func NewLoggerFromZap(name string, l *zap.Logger, level LogLevel, opts ...Option) Logger {
+	return logger.NewLoggerFromZap(name, l, level, opts...)
+}
+
+// NewLoggerFromLogrus 包装已有的logrus日志实例，输出仍由该实例负责，门面级别取自该实例的级别
+func NewLoggerFromLogrus(name string, l *logrus.Logger, opts ...Option) Logger {
+	return logger.NewLoggerFromLogrus(name, l, opts...)
+}
+
+// NewJournalLogger 创建日志记录包装器，日志转发给base，记录追加写入path
+func NewJournalLogger(base Logger, path string) (*JournalLogger, error) {
+	return logger.NewJournalLogger(base, path)
+}
+
+// NewSynchronizedWriter 创建串行化写入w的输出，每次Write作为一次完整的写入交给w
+func NewSynchronizedWriter(w io.Writer) *SynchronizedWriter {
+	return logger.NewSynchronizedWriter(w)
+}
+
+// NewAuditLogger 创建包装inner的审计日志实例，审计日志固定为AuditLevel级别并带有audit=true标记，不受采样和限流影响
+func NewAuditLogger(inner Logger) *AuditLogger {
+	return logger.NewAuditLogger(inner)
+}
+
+// ReadJournal 读取日志记录文件中的全部记录，文件末尾不完整的记录会被忽略
+func ReadJournal(path string) ([]Entry, error) {
+	return logger.ReadJournal(path)
+}
+
+// NewPipeWriter 创建将日志记录以JSON行写入w的管道日志写入器
+func NewPipeWriter(w io.Writer) *PipeWriter {
+	return logger.NewPipeWriter(w)
+}
+
+// NewPipeReader 创建从r读取日志记录并通过l重新输出的管道日志读取器
+func NewPipeReader(r io.Reader, l Logger) *PipeReader {
+	return logger.NewPipeReader(r, l)
+}
+
+// StdlibWriter 创建将每次写入转换为level级别日志的io.Writer
+func StdlibWriter(l Logger, level LogLevel) io.Writer {
+	return logger.StdlibWriter(l, level)
+}
+
+// StdlibLogger 创建输出到日志门面的标准库*log.Logger
+func StdlibLogger(l Logger, level LogLevel) *log.Logger {
+	return logger.StdlibLogger(l, level)
+}
+
+// LeveledWriter 创建按行首的严重级别标记（如ERROR:、[WARN]）选择日志级别的io.Writer，没有标记的行以Info级别输出
+func LeveledWriter(l Logger) io.Writer {
+	return logger.LeveledWriter(l)
+}
+
+// StructFields 通过反射将结构体的导出字段展开为 prefix.FieldName 形式的字段
+func StructFields(prefix string, v interface{}) []Field {
+	return logger.StructFields(prefix, v)
+}
+
+// ContextWithFields 将字段附加到上下文，通过WithContext或*Ctx方法输出日志时自动提取
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	return logger.ContextWithFields(ctx, fields...)
+}
+
+// FieldsFromContext 获取通过ContextWithFields附加到上下文的字段
+func FieldsFromContext(ctx context.Context) []Field {
+	return logger.FieldsFromContext(ctx)
+}
+
+// ContextWithOperation 将操作名称附加到上下文，使用该上下文输出的日志自动添加operation字段
+func ContextWithOperation(ctx context.Context, name string) context.Context {
+	return logger.ContextWithOperation(ctx, name)
+}
+
+// OperationFromContext 获取通过ContextWithOperation附加到上下文的操作名称
+func OperationFromContext(ctx context.Context) (string, bool) {
+	return logger.OperationFromContext(ctx)
+}
+
+// ContextWithTenant 将租户ID附加到上下文，通过WithContext或*Ctx方法输出的日志自动添加tenant_id字段
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return logger.ContextWithTenant(ctx, tenantID)
+}
+
+// TenantFromContext 获取通过ContextWithTenant附加到上下文的租户ID
+func TenantFromContext(ctx context.Context) (string, bool) {
+	return logger.TenantFromContext(ctx)
+}
+
+// ContextWithUser 将用户ID附加到上下文，通过WithContext或*Ctx方法输出的日志自动添加user_id字段
+func ContextWithUser(ctx context.Context, userID string) context.Context {
+	return logger.ContextWithUser(ctx, userID)
+}
+
+// UserFromContext 获取通过ContextWithUser附加到上下文的用户ID
+func UserFromContext(ctx context.Context) (string, bool) {
+	return logger.UserFromContext(ctx)
+}
+
+// ContextWithLevel 将日志级别覆盖附加到上下文，只有低于日志实例自身级别时才生效
+func ContextWithLevel(ctx context.Context, level LogLevel) context.Context {
+	return logger.ContextWithLevel(ctx, level)
+}
+
+// LevelFromContext 获取通过ContextWithLevel附加到上下文的日志级别
+func LevelFromContext(ctx context.Context) (LogLevel, bool) {
+	return logger.LevelFromContext(ctx)
+}
+
+// SetErrorOutput 设置日志门面自身警告和错误的输出目标
+func SetErrorOutput(w io.Writer) {
+	logger.SetErrorOutput(w)
+}
+
 // NewLogConfig 创建默认的日志配置
 func NewLogConfig() *LogConfig {
 	return logger.NewLogConfig()
@@ -100,6 +403,31 @@ func WithOutputPath(path string) Option {
 	return logger.WithOutputPath(path)
 }
 
+// WithOutputPaths 设置多个日志输出路径，日志同时写入所有路径，支持stdout和stderr
+func WithOutputPaths(paths ...string) Option {
+	return logger.WithOutputPaths(paths...)
+}
+
+// WithWriter 设置自定义输出目标，未设置WithOutputPaths时代替OutputPath，否则同时写入
+func WithWriter(w io.Writer) Option {
+	return logger.WithWriter(w)
+}
+
+// WithLogrusSortKeys 设置logrus JSON格式下是否对嵌套字段同样按键排序并去重，只对logrus日志生效
+func WithLogrusSortKeys(enabled bool) Option {
+	return logger.WithLogrusSortKeys(enabled)
+}
+
+// WithZapWriteSyncer 设置zap日志直接使用的zapcore.WriteSyncer，优先于OutputPath等输出配置，只对zap日志生效
+func WithZapWriteSyncer(ws zapcore.WriteSyncer) Option {
+	return logger.WithZapWriteSyncer(ws)
+}
+
+// WithDisableStacktrace 设置是否禁止zap在日志中附加stacktrace字段，门面自身的stack字段不受影响
+func WithDisableStacktrace(disabled bool) Option {
+	return logger.WithDisableStacktrace(disabled)
+}
+
 // WithConfig 设置额外配置
 func WithConfig(config map[string]interface{}) Option {
 	return logger.WithConfig(config)
@@ -130,6 +458,326 @@ func WithMaxMessageSize(size int) Option {
 	return logger.WithMaxMessageSize(size)
 }
 
+// WithContextExtractor 添加上下文字段提取器，可多次调用添加多个
+func WithContextExtractor(extractor ContextExtractor) Option {
+	return logger.WithContextExtractor(extractor)
+}
+
+// WithContextKeys 按键从上下文中取值作为字段输出，字段名为键的字符串形式，上下文中不存在的键不输出
+func WithContextKeys(keys ...interface{}) Option {
+	return logger.WithContextKeys(keys...)
+}
+
+// WithContextErrField 设置日志使用的上下文已取消或超时时是否添加ctx_err字段
+func WithContextErrField(enabled bool) Option {
+	return logger.WithContextErrField(enabled)
+}
+
+// WithAlwaysLogAbove 设置不受采样和限流影响的最低级别，默认为ErrorLevel
+func WithAlwaysLogAbove(level LogLevel) Option {
+	return logger.WithAlwaysLogAbove(level)
+}
+
+// WithHostname 设置是否为每条日志添加主机名字段host
+func WithHostname(enabled bool) Option {
+	return logger.WithHostname(enabled)
+}
+
+// WithPID 设置是否为每条日志添加进程ID字段pid
+func WithPID(enabled bool) Option {
+	return logger.WithPID(enabled)
+}
+
+// WithFlattenFields 设置JSON格式下自定义字段是否与msg平铺在顶层，为false时嵌套在WithFieldsKey指定的键下
+func WithFlattenFields(flatten bool) Option {
+	return logger.WithFlattenFields(flatten)
+}
+
+// WithFieldsKey 设置嵌套自定义字段使用的键名，默认为fields
+func WithFieldsKey(key string) Option {
+	return logger.WithFieldsKey(key)
+}
+
+// WithUptimeField 设置是否为每条日志添加自日志实例创建（或调用StartTimer）以来经过时长的uptime字段
+func WithUptimeField(enabled bool) Option {
+	return logger.WithUptimeField(enabled)
+}
+
+// WithSamplingSummary 开启采样汇总，Sync时以level级别输出自上次汇总以来被采样丢弃和限流抑制的条数
+func WithSamplingSummary(level LogLevel) Option {
+	return logger.WithSamplingSummary(level)
+}
+
+// WithFieldRateLimit 按字段值限流，携带相同key值的日志每秒最多输出maxPerSecond条
+func WithFieldRateLimit(key string, maxPerSecond int) Option {
+	return logger.WithFieldRateLimit(key, maxPerSecond)
+}
+
+// WithBuildInfoFields 设置是否为每条日志添加version、commit和build_time字段
+func WithBuildInfoFields(enabled bool) Option {
+	return logger.WithBuildInfoFields(enabled)
+}
+
+// WithProcessInfo 同时设置是否添加host和pid字段
+func WithProcessInfo(enabled bool) Option {
+	return logger.WithProcessInfo(enabled)
+}
+
+// WithFatalHooks 添加致命级日志退出程序前执行的钩子，钩子在日志刷新后按注册顺序执行
+func WithFatalHooks(hooks ...func()) Option {
+	return logger.WithFatalHooks(hooks...)
+}
+
+// WithExitFunc 设置致命级日志使用的退出函数，默认为os.Exit
+func WithExitFunc(exit func(code int)) Option {
+	return logger.WithExitFunc(exit)
+}
+
+// WithProbabilisticLevel 设置level及以下级别的日志按probability的概率输出
+func WithProbabilisticLevel(level LogLevel, probability float64) Option {
+	return logger.WithProbabilisticLevel(level, probability)
+}
+
+// WithCallerFields 设置是否以caller.file、caller.line和caller.func三个独立字段输出调用位置
+func WithCallerFields(enabled bool) Option {
+	return logger.WithCallerFields(enabled)
+}
+
+// WithWarnStackDepth 为警告级日志添加包含调用位置开始的depth个栈帧的stack字段
+func WithWarnStackDepth(depth int) Option {
+	return logger.WithWarnStackDepth(depth)
+}
+
+// WithConditionalStacktrace 设置只在predicate返回true时为日志添加调用方的stacktrace字段
+func WithConditionalStacktrace(predicate func(level LogLevel, msg string, fields []Field) bool) Option {
+	return logger.WithConditionalStacktrace(predicate)
+}
+
+// WithEntryHook 添加日志钩子，如将日志同时记录为追踪的span事件，可多次调用添加多个
+func WithEntryHook(hook EntryHook) Option {
+	return logger.WithEntryHook(hook)
+}
+
+// WithRuntimeStats 设置是否为警告及以上级别的日志添加goroutines、heap_alloc和num_gc字段
+func WithRuntimeStats(enabled bool) Option {
+	return logger.WithRuntimeStats(enabled)
+}
+
+// WithSanitizeNewlines 设置文本格式下是否转义消息和字段值中的换行符，默认开启
+func WithSanitizeNewlines(enabled bool) Option {
+	return logger.WithSanitizeNewlines(enabled)
+}
+
+// WithTrailingNewline 设置每条日志是否以换行符结尾，默认开启，输出目标自行分隔记录时可以关闭
+func WithTrailingNewline(enabled bool) Option {
+	return logger.WithTrailingNewline(enabled)
+}
+
+// WithComposeCompositesAsJSON 设置文本和logfmt格式下是否将切片、数组和map字段值输出为紧凑JSON
+func WithComposeCompositesAsJSON(enabled bool) Option {
+	return logger.WithComposeCompositesAsJSON(enabled)
+}
+
+// WithFloatPrecision 设置文本和logfmt格式下浮点数字段保留的小数位数
+func WithFloatPrecision(digits int) Option {
+	return logger.WithFloatPrecision(digits)
+}
+
+// WithTimeFormat 设置文本和logfmt格式下时间戳和time.Time字段值的格式，JSON格式中的time.Time字段值始终为RFC3339格式
+func WithTimeFormat(layout string) Option {
+	return logger.WithTimeFormat(layout)
+}
+
+// WithBytesEncoding 设置文本和logfmt格式下[]byte字段值的编码方式（base64/hex）
+func WithBytesEncoding(encoding string) Option {
+	return logger.WithBytesEncoding(encoding)
+}
+
+// WithCloudEventsSource 设置cloudevents格式输出的事件来源
+func WithCloudEventsSource(source string) Option {
+	return logger.WithCloudEventsSource(source)
+}
+
+// WithCloudEventsType 设置cloudevents格式输出的事件类型
+func WithCloudEventsType(eventType string) Option {
+	return logger.WithCloudEventsType(eventType)
+}
+
+// WithGCPProjectID 设置gcp格式输出使用的GCP项目ID，用于生成完整的追踪名称
+func WithGCPProjectID(projectID string) Option {
+	return logger.WithGCPProjectID(projectID)
+}
+
+// WithSingleLineStacktrace 设置是否将stack和stacktrace字段中的堆栈合并为一行
+func WithSingleLineStacktrace(enabled bool) Option {
+	return logger.WithSingleLineStacktrace(enabled)
+}
+
+// WithHeartbeat 每隔interval输出一条带有递增heartbeat字段的信息级心跳日志，日志实例Close时停止
+func WithHeartbeat(interval time.Duration, msg string) Option {
+	return logger.WithHeartbeat(interval, msg)
+}
+
+// WithCrashDumpPath 设置崩溃转储文件路径，Panic在触发panic之前将日志记录和goroutine堆栈写入该文件
+func WithCrashDumpPath(path string) Option {
+	return logger.WithCrashDumpPath(path)
+}
+
+// WithRedactPattern 添加脱敏规则，对日志消息和字符串字段值中匹配re的内容进行替换
+func WithRedactPattern(re *regexp.Regexp, replacement string) Option {
+	return logger.WithRedactPattern(re, replacement)
+}
+
+// WithDurationUnit 设置time.Duration字段的输出单位，键名添加单位后缀，如latency_ms
+func WithDurationUnit(unit time.Duration) Option {
+	return logger.WithDurationUnit(unit)
+}
+
+// WithLevelEncoder 设置结构化输出中日志级别的表示方式，如GCP的severity或syslog数值级别
+func WithLevelEncoder(encoder LevelEncoder) Option {
+	return logger.WithLevelEncoder(encoder)
+}
+
+// WithNumericSeverity 设置JSON格式下是否添加数值级别的severity_code字段，默认使用syslog级别
+func WithNumericSeverity(enabled bool) Option {
+	return logger.WithNumericSeverity(enabled)
+}
+
+// WithSeverityCodes 按级别覆盖severity_code字段的数值
+func WithSeverityCodes(codes map[LogLevel]int) Option {
+	return logger.WithSeverityCodes(codes)
+}
+
+// WithErrorFormatter 设置错误字段的格式化方式，作用于WithError和值为error的字段
+func WithErrorFormatter(formatter ErrorFormatter) Option {
+	return logger.WithErrorFormatter(formatter)
+}
+
+// WithMarshaler 为类型t注册转换函数，该类型的字段值在编码前由marshal转换
+func WithMarshaler(t reflect.Type, marshal MarshalFunc) Option {
+	return logger.WithMarshaler(t, marshal)
+}
+
+// WithDevelopment 设置是否为开发模式，开发模式下会检查字段键与保留键的冲突
+func WithDevelopment(development bool) Option {
+	return logger.WithDevelopment(development)
+}
+
+// WithFieldTrace 设置是否记录每个字段的添加位置，同一个键被设置多次时输出field_trace诊断字段，只在开发模式下生效
+func WithFieldTrace(enabled bool) Option {
+	return logger.WithFieldTrace(enabled)
+}
+
+// WithReservedKeyPolicy 设置字段键与保留键冲突时的处理策略
+func WithReservedKeyPolicy(policy ReservedKeyPolicy) Option {
+	return logger.WithReservedKeyPolicy(policy)
+}
+
+// WithColor 设置文本格式是否使用ANSI颜色输出级别
+func WithColor(color bool) Option {
+	return logger.WithColor(color)
+}
+
+// WithLevelColors 按级别覆盖文本格式输出级别使用的ANSI颜色，只在WithColor(true)时生效
+func WithLevelColors(colors map[LogLevel]string) Option {
+	return logger.WithLevelColors(colors)
+}
+
+// WithEnvironment 设置运行环境（"dev"或"prod"），auto提供者据此选择日志后端
+func WithEnvironment(env string) Option {
+	return logger.WithEnvironment(env)
+}
+
+// WithTimeKey 设置结构化输出中时间的键名，默认为time
+func WithTimeKey(key string) Option {
+	return logger.WithTimeKey(key)
+}
+
+// WithLevelKey 设置结构化输出中级别的键名，默认为level
+func WithLevelKey(key string) Option {
+	return logger.WithLevelKey(key)
+}
+
+// WithNameKey 设置结构化输出中日志名称的键名，默认为logger
+func WithNameKey(key string) Option {
+	return logger.WithNameKey(key)
+}
+
+// WithMessageKey 设置结构化输出中消息的键名，默认为msg
+func WithMessageKey(key string) Option {
+	return logger.WithMessageKey(key)
+}
+
+// WithClock 设置获取日志时间的时钟，测试中可注入固定时钟使输出时间确定
+func WithClock(clock Clock) Option {
+	return logger.WithClock(clock)
+}
+
+// WithMaxFieldBytes 设置单个字段值渲染后的最大字节数，超出部分被截断
+func WithMaxFieldBytes(n int) Option {
+	return logger.WithMaxFieldBytes(n)
+}
+
+// WithMaxFields 设置单条日志合并后最多保留的字段数，超出时保留前n个字段并添加fields_truncated字段
+func WithMaxFields(n int) Option {
+	return logger.WithMaxFields(n)
+}
+
+// WithConstFields 设置常量字段，每条日志都会输出且位于其他字段之前
+func WithConstFields(fields ...Field) Option {
+	return logger.WithConstFields(fields...)
+}
+
+// WithDedupeFields 设置是否对同名字段去重，后出现的值覆盖先出现的值
+func WithDedupeFields(dedupe bool) Option {
+	return logger.WithDedupeFields(dedupe)
+}
+
+// WithOmitNilFields 设置是否去除值为nil的字段（包括nil指针、nil map等）
+func WithOmitNilFields(enabled bool) Option {
+	return logger.WithOmitNilFields(enabled)
+}
+
+// WithFieldAllowlist 设置允许输出的字段键，键不在列表中的字段被丢弃
+func WithFieldAllowlist(keys ...string) Option {
+	return logger.WithFieldAllowlist(keys...)
+}
+
+// WithCompressedOutput 设置是否以gzip边写边压缩日志文件
+func WithCompressedOutput(compressed bool) Option {
+	return logger.WithCompressedOutput(compressed)
+}
+
+// WithGoroutineID 设置是否为每条日志添加当前goroutine的ID（goid字段）
+func WithGoroutineID(enabled bool) Option {
+	return logger.WithGoroutineID(enabled)
+}
+
+// WithSequence 设置是否为每条日志添加递增的序号（seq字段）
+func WithSequence(enabled bool) Option {
+	return logger.WithSequence(enabled)
+}
+
+// WithGlobalSequence 设置是否为每条日志添加进程内所有日志实例共享的递增序号（gseq字段）
+func WithGlobalSequence(enabled bool) Option {
+	return logger.WithGlobalSequence(enabled)
+}
+
+// WithBufferedWriterSize 设置输出缓冲区大小（字节），缓冲的数据在Sync时写入输出
+func WithBufferedWriterSize(size int) Option {
+	return logger.WithBufferedWriterSize(size)
+}
+
+// WithOutputCircuitBreaker 为输出目标添加熔断，连续threshold次写入失败后在cooldown内改为写入备用输出
+func WithOutputCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return logger.WithOutputCircuitBreaker(threshold, cooldown)
+}
+
+// WithCircuitBreakerFallback 设置熔断期间使用的备用输出目标，默认为标准错误输出
+func WithCircuitBreakerFallback(w io.Writer) Option {
+	return logger.WithCircuitBreakerFallback(w)
+}
+
 // 导出全局日志函数
 
 // Debug 全局调试级日志
@@ -192,11 +840,84 @@ func Panicf(format string, args ...interface{}) {
 	logger.Panicf(format, args...)
 }
 
+// LogfIfEnabled 全局日志启用level级别时格式化并输出日志，未启用时不会格式化消息
+func LogfIfEnabled(level LogLevel, format string, args ...interface{}) {
+	logger.LogfIfEnabled(level, format, args...)
+}
+
+// IsDebugEnabled 检查全局日志的调试级别是否启用
+func IsDebugEnabled() bool {
+	return logger.IsDebugEnabled()
+}
+
+// IsInfoEnabled 检查全局日志的信息级别是否启用
+func IsInfoEnabled() bool {
+	return logger.IsInfoEnabled()
+}
+
+// IsWarnEnabled 检查全局日志的警告级别是否启用
+func IsWarnEnabled() bool {
+	return logger.IsWarnEnabled()
+}
+
+// IsErrorEnabled 检查全局日志的错误级别是否启用
+func IsErrorEnabled() bool {
+	return logger.IsErrorEnabled()
+}
+
+// IsFatalEnabled 检查全局日志的致命级别是否启用
+func IsFatalEnabled() bool {
+	return logger.IsFatalEnabled()
+}
+
+// IsPanicEnabled 检查全局日志的恐慌级别是否启用
+func IsPanicEnabled() bool {
+	return logger.IsPanicEnabled()
+}
+
 // 导出适配器函数
 
+// GinOption gin日志适配器配置选项
+type GinOption = adapters.GinOption
+
 // NewGinLogger 创建一个新的gin日志适配器
-func NewGinLogger(log Logger) *adapters.GinLogger {
-	return adapters.NewGinLogger(log)
+func NewGinLogger(log Logger, opts ...GinOption) *adapters.GinLogger {
+	return adapters.NewGinLogger(log, opts...)
+}
+
+// WithGinRequestIDHeader 设置请求ID使用的请求头和响应头名称，默认为X-Request-ID
+func WithGinRequestIDHeader(header string) GinOption {
+	return adapters.WithGinRequestIDHeader(header)
+}
+
+// WithGinTraceIDHeader 设置追踪ID使用的请求头名称，默认为X-Trace-ID
+func WithGinTraceIDHeader(header string) GinOption {
+	return adapters.WithGinTraceIDHeader(header)
+}
+
+// WithGinLogHeaders 设置gin访问日志中以headers字段记录的请求头和响应头，敏感头的值会被脱敏
+func WithGinLogHeaders(headers ...string) GinOption {
+	return adapters.WithGinLogHeaders(headers...)
+}
+
+// WithGinRedactHeaders 设置需要脱敏的请求头和响应头，替换默认的Authorization、Cookie和Set-Cookie
+func WithGinRedactHeaders(headers ...string) GinOption {
+	return adapters.WithGinRedactHeaders(headers...)
+}
+
+// WithGinLogQuery 在gin访问日志中以query字段记录查询参数，敏感参数的值会被脱敏，最多记录maxParams个参数
+func WithGinLogQuery(maxParams int) GinOption {
+	return adapters.WithGinLogQuery(maxParams)
+}
+
+// WithGinRedactQuery 设置需要脱敏的查询参数，替换默认的token和api_key
+func WithGinRedactQuery(params ...string) GinOption {
+	return adapters.WithGinRedactQuery(params...)
+}
+
+// GinRequestLogger 获取gin日志中间件为当前请求创建的带有request_id字段的日志实例
+func GinRequestLogger(c *gin.Context) Logger {
+	return adapters.GinRequestLogger(c)
 }
 
 // NewGFLogger 创建一个新的goframe日志适配器
@@ -205,8 +926,8 @@ func NewGFLogger(log Logger) *adapters.GFLogger {
 }
 
 // UseWithGin 将日志适配器应用到gin引擎
-func UseWithGin(r *gin.Engine, log Logger) {
-	adapters.UseWithGin(r, log)
+func UseWithGin(r *gin.Engine, log Logger, opts ...GinOption) {
+	adapters.UseWithGin(r, log, opts...)
 }
 
 // UseWithGF 将日志适配器应用到goframe
@@ -0,0 +1,266 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+)
+
+// CloudWatch Logs PutLogEvents的批次限制
+const (
+	cloudWatchMaxBatchBytes  = 1048576 // 单批最大字节数，每条日志按消息长度加26字节计算
+	cloudWatchMaxBatchEvents = 10000   // 单批最大日志条数
+	cloudWatchEventOverhead  = 26      // 每条日志的额外字节数
+	cloudWatchMaxAttempts    = 3       // 流不存在或序列号失效时的最大尝试次数
+)
+
+// CloudWatchLogsAPI CloudWatchWriter使用的CloudWatch Logs客户端接口，*cloudwatchlogs.Client实现了该接口
+type CloudWatchLogsAPI interface {
+	PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+	CreateLogStream(ctx context.Context, params *cloudwatchlogs.CreateLogStreamInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error)
+}
+
+// CloudWatchWriter 将日志批量写入AWS CloudWatch Logs日志组和日志流的输出目标
+type CloudWatchWriter struct {
+	group         string
+	stream        string
+	client        CloudWatchLogsAPI
+	flushInterval time.Duration
+	timeout       time.Duration
+
+	mu      sync.Mutex
+	events  []types.InputLogEvent
+	bytes   int
+	dropped uint64
+
+	putMu         sync.Mutex // 串行推送，保证序列号按顺序使用
+	sequenceToken *string
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// CloudWatchOption CloudWatchWriter配置选项
+type CloudWatchOption func(*CloudWatchWriter)
+
+// WithCloudWatchFlushInterval 设置定时推送间隔
+func WithCloudWatchFlushInterval(interval time.Duration) CloudWatchOption {
+	return func(w *CloudWatchWriter) {
+		w.flushInterval = interval
+	}
+}
+
+// WithCloudWatchTimeout 设置单次请求的超时时间
+func WithCloudWatchTimeout(timeout time.Duration) CloudWatchOption {
+	return func(w *CloudWatchWriter) {
+		w.timeout = timeout
+	}
+}
+
+// WithCloudWatchClient 设置使用的CloudWatch Logs客户端，替换根据aws.Config创建的客户端
+func WithCloudWatchClient(client CloudWatchLogsAPI) CloudWatchOption {
+	return func(w *CloudWatchWriter) {
+		w.client = client
+	}
+}
+
+// 确保CloudWatchWriter实现了WriteSyncer和io.Closer接口
+var (
+	_ logger.WriteSyncer = (*CloudWatchWriter)(nil)
+	_ io.Closer          = (*CloudWatchWriter)(nil)
+)
+
+// NewCloudWatchWriter 创建CloudWatch Logs输出目标，日志流不存在时在首次推送时自动创建，
+// 日志组需要预先存在
+func NewCloudWatchWriter(group, stream string, cfg aws.Config, opts ...CloudWatchOption) *CloudWatchWriter {
+	w := &CloudWatchWriter{
+		group:         group,
+		stream:        stream,
+		flushInterval: 5 * time.Second,
+		timeout:       10 * time.Second,
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+	if w.client == nil {
+		w.client = cloudwatchlogs.NewFromConfig(cfg)
+	}
+
+	if w.flushInterval > 0 {
+		w.wg.Add(1)
+		go w.flushLoop()
+	}
+
+	return w
+}
+
+// Write 将一行日志加入待推送批次，待推送的日志达到单批限制时立即推送
+func (w *CloudWatchWriter) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+	if line == "" {
+		// CloudWatch Logs不接受空消息
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	// 在锁内取时间，保证同一批次内的日志按时间排序
+	w.events = append(w.events, types.InputLogEvent{
+		Message:   aws.String(line),
+		Timestamp: aws.Int64(time.Now().UnixMilli()),
+	})
+	w.bytes += len(line) + cloudWatchEventOverhead
+	full := len(w.events) >= cloudWatchMaxBatchEvents || w.bytes >= cloudWatchMaxBatchBytes
+	w.mu.Unlock()
+
+	if full {
+		if err := w.Sync(); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Sync 立即推送所有待推送的日志，超过单批限制时拆分为多批
+func (w *CloudWatchWriter) Sync() error {
+	w.mu.Lock()
+	events := w.events
+	w.events = nil
+	w.bytes = 0
+	w.mu.Unlock()
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	w.putMu.Lock()
+	defer w.putMu.Unlock()
+
+	var errs []error
+	for _, batch := range splitCloudWatchBatches(events) {
+		if err := w.put(batch); err != nil {
+			atomic.AddUint64(&w.dropped, uint64(len(batch)))
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 停止定时推送并推送剩余的日志
+func (w *CloudWatchWriter) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+	return w.Sync()
+}
+
+// Dropped 返回因推送失败而丢弃的日志条数
+func (w *CloudWatchWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// flushLoop 定时推送日志
+func (w *CloudWatchWriter) flushLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.Sync()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// splitCloudWatchBatches 按单批条数和字节数限制拆分日志
+func splitCloudWatchBatches(events []types.InputLogEvent) [][]types.InputLogEvent {
+	var batches [][]types.InputLogEvent
+	start, size := 0, 0
+	for i, event := range events {
+		eventSize := len(aws.ToString(event.Message)) + cloudWatchEventOverhead
+		if i > start && (i-start >= cloudWatchMaxBatchEvents || size+eventSize > cloudWatchMaxBatchBytes) {
+			batches = append(batches, events[start:i])
+			start, size = i, 0
+		}
+		size += eventSize
+	}
+	return append(batches, events[start:])
+}
+
+// put 推送一批日志，日志流不存在时创建日志流，序列号失效时使用服务端返回的序列号重试
+func (w *CloudWatchWriter) put(batch []types.InputLogEvent) error {
+	var err error
+	for attempt := 0; attempt < cloudWatchMaxAttempts; attempt++ {
+		var out *cloudwatchlogs.PutLogEventsOutput
+		out, err = w.putLogEvents(batch)
+		if err == nil {
+			w.sequenceToken = out.NextSequenceToken
+			return nil
+		}
+
+		var notFound *types.ResourceNotFoundException
+		var invalidToken *types.InvalidSequenceTokenException
+		var accepted *types.DataAlreadyAcceptedException
+		switch {
+		case errors.As(err, &notFound):
+			if err := w.createStream(); err != nil {
+				return err
+			}
+			w.sequenceToken = nil
+		case errors.As(err, &invalidToken):
+			w.sequenceToken = invalidToken.ExpectedSequenceToken
+		case errors.As(err, &accepted):
+			// 这批日志已经写入过，不需要重试
+			w.sequenceToken = accepted.ExpectedSequenceToken
+			return nil
+		default:
+			return err
+		}
+	}
+	return err
+}
+
+// putLogEvents 发送一次PutLogEvents请求
+func (w *CloudWatchWriter) putLogEvents(batch []types.InputLogEvent) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	return w.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(w.group),
+		LogStreamName: aws.String(w.stream),
+		LogEvents:     batch,
+		SequenceToken: w.sequenceToken,
+	})
+}
+
+// createStream 创建日志流，日志流已存在时视为成功
+func (w *CloudWatchWriter) createStream() error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+
+	_, err := w.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(w.group),
+		LogStreamName: aws.String(w.stream),
+	})
+	var exists *types.ResourceAlreadyExistsException
+	if errors.As(err, &exists) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,124 @@
+//go:build windows
+
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLogEventID 写入Windows事件日志使用的事件ID
+const eventLogEventID = 1
+
+// EventLogWriter 将日志写入Windows事件日志的输出目标，
+// 按每行日志的级别映射为事件类型：Error及以上为错误，Warn为警告，其他为信息
+type EventLogWriter struct {
+	log *eventlog.Log
+}
+
+// 确保EventLogWriter实现了WriteSyncer和io.Closer接口
+var (
+	_ logger.WriteSyncer = (*EventLogWriter)(nil)
+	_ io.Closer          = (*EventLogWriter)(nil)
+)
+
+// NewEventLogWriter 创建Windows事件日志输出目标，source为事件来源名称，需预先通过eventlog.InstallAsEventCreate注册
+func NewEventLogWriter(source string) (*EventLogWriter, error) {
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("open event log %q: %w", source, err)
+	}
+	return &EventLogWriter{log: log}, nil
+}
+
+// Write 写入一条日志
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, "\r\n"))
+
+	var err error
+	switch eventLogLevel(p) {
+	case logger.ErrorLevel, logger.FatalLevel, logger.PanicLevel:
+		err = w.log.Error(eventLogEventID, msg)
+	case logger.WarnLevel:
+		err = w.log.Warning(eventLogEventID, msg)
+	default:
+		err = w.log.Info(eventLogEventID, msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync 事件日志直接写入，无需刷新
+func (w *EventLogWriter) Sync() error {
+	return nil
+}
+
+// Close 关闭事件日志句柄
+func (w *EventLogWriter) Close() error {
+	return w.log.Close()
+}
+
+// eventLogLevel 从编码后的一行日志中解析级别，支持JSON格式的level字段和文本格式的[LEVEL]
+func eventLogLevel(p []byte) logger.LogLevel {
+	var record struct {
+		Level string `json:"level"`
+	}
+	level := ""
+	if json.Unmarshal(p, &record) == nil {
+		level = strings.ToUpper(record.Level)
+	}
+
+	for _, l := range []logger.LogLevel{logger.PanicLevel, logger.FatalLevel, logger.ErrorLevel, logger.WarnLevel} {
+		if level == l.String() || (level == "" && bytes.Contains(p, []byte("["+l.String()+"]"))) {
+			return l
+		}
+	}
+	return logger.InfoLevel
+}
+
+// EventLogProvider 写入Windows事件日志的日志提供者，事件来源默认为日志名称
+type EventLogProvider struct{}
+
+// NewEventLogProvider 创建eventlog日志提供者
+func NewEventLogProvider() *EventLogProvider {
+	return &EventLogProvider{}
+}
+
+// Create 创建日志实例
+func (p *EventLogProvider) Create(name string) Logger {
+	return p.CreateWithConfig(name, map[string]interface{}{})
+}
+
+// CreateWithConfig 根据配置创建日志实例，支持level和source配置项
+func (p *EventLogProvider) CreateWithConfig(name string, config map[string]interface{}) Logger {
+	opts := []logger.Option{logger.WithConfig(config), logger.WithFormat("json")}
+	if lvl, ok := config["level"].(LogLevel); ok {
+		opts = append(opts, logger.WithLevel(lvl))
+	}
+
+	source := name
+	if s, ok := config["source"].(string); ok && s != "" {
+		source = s
+	}
+
+	writer, err := NewEventLogWriter(source)
+	if err != nil {
+		// 无法打开事件日志时退化为标准错误输出
+		fmt.Fprintf(os.Stderr, "LandcLogFace: %v, falling back to stderr\n", err)
+		return logger.NewConsoleLogger(name, append(opts, logger.WithOutputPath("stderr"))...)
+	}
+	return logger.NewConsoleLogger(name, append(opts, logger.WithWriter(writer))...)
+}
+
+func init() {
+	logger.GetLogFactory().RegisterProvider("eventlog", NewEventLogProvider())
+}
@@ -1,7 +1,12 @@
 package adapters
 
 import (
+	"crypto/rand"
 	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/LandcLi/LandcLogFace/pkg/logger"
@@ -9,16 +14,233 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// ginRequestLoggerKey gin上下文中请求级日志实例的键
+const ginRequestLoggerKey = "landclogface.logger"
+
+// redactedHeaderValue 脱敏请求头的替换值
+const redactedHeaderValue = "[REDACTED]"
+
+// defaultRedactedHeaders 默认脱敏的请求头和响应头
+var defaultRedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// defaultRedactedQueryParams 默认脱敏的查询参数
+var defaultRedactedQueryParams = []string{"token", "api_key"}
+
+// defaultMaxQueryParams 访问日志中默认最多记录的查询参数个数
+const defaultMaxQueryParams = 20
+
 // GinLogger 是gin框架的日志适配器
 type GinLogger struct {
-	log Logger
+	log             Logger
+	requestIDHeader string
+	traceIDHeader   string
+	logHeaders      []string
+	redactHeaders   map[string]bool
+	logQuery        bool
+	maxQueryParams  int
+	redactQuery     map[string]bool
+}
+
+// GinOption gin日志适配器配置选项
+type GinOption func(*GinLogger)
+
+// WithGinRequestIDHeader 设置请求ID使用的请求头和响应头名称，默认为X-Request-ID
+func WithGinRequestIDHeader(header string) GinOption {
+	return func(g *GinLogger) {
+		g.requestIDHeader = header
+	}
+}
+
+// WithGinTraceIDHeader 设置追踪ID使用的请求头名称，默认为X-Trace-ID
+func WithGinTraceIDHeader(header string) GinOption {
+	return func(g *GinLogger) {
+		g.traceIDHeader = header
+	}
+}
+
+// WithGinLogHeaders 设置访问日志中记录的请求头和响应头，以headers字段输出，名称不区分大小写，
+// Authorization、Cookie和Set-Cookie等敏感头的值会被脱敏
+func WithGinLogHeaders(headers ...string) GinOption {
+	return func(g *GinLogger) {
+		g.logHeaders = append(g.logHeaders, headers...)
+	}
+}
+
+// WithGinRedactHeaders 设置需要脱敏的请求头和响应头，替换默认的Authorization、Cookie和Set-Cookie
+func WithGinRedactHeaders(headers ...string) GinOption {
+	return func(g *GinLogger) {
+		g.redactHeaders = headerSet(headers)
+	}
+}
+
+// WithGinLogQuery 在访问日志中以query字段记录查询参数，uri字段和日志消息只保留路径，避免原始URL中的密钥被输出。
+// token、api_key等敏感参数的值会被脱敏，最多记录maxParams个参数（按名称排序），超出的个数以query_truncated字段输出，
+// maxParams不大于0时使用默认的20个
+func WithGinLogQuery(maxParams int) GinOption {
+	return func(g *GinLogger) {
+		if maxParams <= 0 {
+			maxParams = defaultMaxQueryParams
+		}
+		g.logQuery = true
+		g.maxQueryParams = maxParams
+	}
+}
+
+// WithGinRedactQuery 设置需要脱敏的查询参数，名称不区分大小写，替换默认的token和api_key
+func WithGinRedactQuery(params ...string) GinOption {
+	return func(g *GinLogger) {
+		g.redactQuery = querySet(params)
+	}
+}
+
+// querySet 将查询参数名称转换为小写后转换为集合
+func querySet(params []string) map[string]bool {
+	set := make(map[string]bool, len(params))
+	for _, p := range params {
+		set[strings.ToLower(p)] = true
+	}
+	return set
+}
+
+// headerSet 将请求头名称规范化后转换为集合
+func headerSet(headers []string) map[string]bool {
+	set := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		set[http.CanonicalHeaderKey(h)] = true
+	}
+	return set
 }
 
 // NewGinLogger 创建一个新的gin日志适配器
-func NewGinLogger(log Logger) *GinLogger {
-	return &GinLogger{
-		log: log,
+func NewGinLogger(log Logger, opts ...GinOption) *GinLogger {
+	g := &GinLogger{
+		log:             log,
+		requestIDHeader: "X-Request-ID",
+		traceIDHeader:   "X-Trace-ID",
+		redactHeaders:   headerSet(defaultRedactedHeaders),
+		redactQuery:     querySet(defaultRedactedQueryParams),
 	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// GinRequestLogger 获取Logger中间件为当前请求创建的日志实例，日志带有request_id字段，
+// 未经过Logger中间件的请求返回全局日志实例
+func GinRequestLogger(c *gin.Context) Logger {
+	return requestLogger(c, logger.GetLogger())
+}
+
+// requestLogger 获取当前请求的日志实例，没有时返回fallback
+func requestLogger(c *gin.Context, fallback Logger) Logger {
+	if l, ok := c.Get(ginRequestLoggerKey); ok {
+		if reqLogger, ok := l.(Logger); ok {
+			return reqLogger
+		}
+	}
+	return fallback
+}
+
+// requestID 获取请求携带的请求ID，没有时生成UUID，并写入响应头
+func (g *GinLogger) requestID(c *gin.Context) string {
+	id := c.GetHeader(g.requestIDHeader)
+	if id == "" {
+		id = newUUID()
+	}
+	c.Header(g.requestIDHeader, id)
+	return id
+}
+
+// headersField 收集配置的请求头和响应头，敏感头的值被脱敏，没有配置或都不存在时返回false
+func (g *GinLogger) headersField(c *gin.Context) (logger.Field, bool) {
+	if len(g.logHeaders) == 0 {
+		return logger.Field{}, false
+	}
+
+	headers := make(map[string]interface{}, 2)
+	if req := g.collectHeaders(c.Request.Header); len(req) > 0 {
+		headers["request"] = req
+	}
+	if resp := g.collectHeaders(c.Writer.Header()); len(resp) > 0 {
+		headers["response"] = resp
+	}
+	if len(headers) == 0 {
+		return logger.Field{}, false
+	}
+	return logger.Field{Key: "headers", Value: headers}, true
+}
+
+// collectHeaders 从header中取出配置的头，多个值以逗号连接
+func (g *GinLogger) collectHeaders(header http.Header) map[string]string {
+	values := make(map[string]string)
+	for _, name := range g.logHeaders {
+		key := http.CanonicalHeaderKey(name)
+		vs := header.Values(key)
+		if len(vs) == 0 {
+			continue
+		}
+		if g.redactHeaders[key] {
+			values[key] = redactedHeaderValue
+			continue
+		}
+		values[key] = strings.Join(vs, ", ")
+	}
+	return values
+}
+
+// requestURI 获取日志中记录的请求URI，记录查询参数时只保留路径
+func (g *GinLogger) requestURI(c *gin.Context) string {
+	if g.logQuery {
+		return c.Request.URL.Path
+	}
+	return c.Request.RequestURI
+}
+
+// queryFields 收集请求的查询参数，敏感参数的值被脱敏，多个值以逗号连接，
+// 超过上限的参数不记录，以query_truncated字段输出被省略的个数。未开启或没有查询参数时返回nil
+func (g *GinLogger) queryFields(query url.Values) []logger.Field {
+	if !g.logQuery || len(query) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	truncated := 0
+	if len(names) > g.maxQueryParams {
+		truncated = len(names) - g.maxQueryParams
+		names = names[:g.maxQueryParams]
+	}
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		if g.redactQuery[strings.ToLower(name)] {
+			values[name] = redactedHeaderValue
+			continue
+		}
+		values[name] = strings.Join(query[name], ",")
+	}
+
+	fields := []logger.Field{{Key: "query", Value: values}}
+	if truncated > 0 {
+		fields = append(fields, logger.Field{Key: "query_truncated", Value: truncated})
+	}
+	return fields
+}
+
+// newUUID 生成随机的UUID（版本4）
+func newUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	b[6] = b[6]&0x0f | 0x40 // 版本4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122变体
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
 
 // Logger 返回gin的日志中间件
@@ -27,6 +249,12 @@ func (g *GinLogger) Logger() gin.HandlerFunc {
 		// 开始时间
 		startTime := time.Now()
 
+		// 请求ID，请求没有携带时生成，并通过响应头返回
+		requestID := g.requestID(c)
+		requestField := logger.Field{Key: "request_id", Value: requestID}
+		c.Set(ginRequestLoggerKey, g.log.WithFields(requestField))
+		c.Request = c.Request.WithContext(logger.ContextWithFields(c.Request.Context(), requestField))
+
 		// 处理请求
 		c.Next()
 
@@ -39,15 +267,18 @@ func (g *GinLogger) Logger() gin.HandlerFunc {
 		// 请求方式
 		reqMethod := c.Request.Method
 
-		// 请求路由
-		reqUri := c.Request.RequestURI
+		// 请求URI和路由模板（如/users/:id），未匹配路由时路由模板为空。
+		// 记录查询参数时URI只保留路径，查询参数脱敏后单独输出
+		reqUri := g.requestURI(c)
+		route := c.FullPath()
+		queryFields := g.queryFields(c.Request.URL.Query())
 
 		// 状态码
 		statusCode := c.Writer.Status()
 
 		// 请求IP
 		clientIP := c.ClientIP()
-		traceID := c.Request.Header.Get("X-Trace-ID")
+		traceID := c.Request.Header.Get(g.traceIDHeader)
 		if traceID == "" {
 			traceID = fmt.Sprintf("%d", time.Now().UnixNano())
 		}
@@ -57,11 +288,17 @@ func (g *GinLogger) Logger() gin.HandlerFunc {
 			{Key: "status", Value: statusCode},
 			{Key: "method", Value: reqMethod},
 			{Key: "uri", Value: reqUri},
+			{Key: "route", Value: route},
 			{Key: "ip", Value: clientIP},
 			{Key: "latency", Value: latencyTime},
 			{Key: "timestamp", Value: endTime},
 			{Key: "trace_id", Value: traceID},
+			requestField,
+		}
+		if headers, ok := g.headersField(c); ok {
+			fields = append(fields, headers)
 		}
+		fields = append(fields, queryFields...)
 
 		// 根据状态码设置日志级别
 		switch {
@@ -82,10 +319,11 @@ func (g *GinLogger) Recovery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				// 记录错误日志
-				g.log.Error(fmt.Sprintf("[GIN] panic recovered: %v", err),
+				// 记录错误日志，经过Logger中间件时带有request_id字段
+				requestLogger(c, g.log).Error(fmt.Sprintf("[GIN] panic recovered: %v", err),
 					logger.Field{Key: "method", Value: c.Request.Method},
-					logger.Field{Key: "uri", Value: c.Request.RequestURI},
+					logger.Field{Key: "uri", Value: g.requestURI(c)},
+					logger.Field{Key: "route", Value: c.FullPath()},
 					logger.Field{Key: "ip", Value: c.ClientIP()},
 				)
 
@@ -99,10 +337,10 @@ func (g *GinLogger) Recovery() gin.HandlerFunc {
 }
 
 // UseWithGin 将日志适配器应用到gin引擎
-func UseWithGin(r *gin.Engine, log interface{}) {
+func UseWithGin(r *gin.Engine, log interface{}, opts ...GinOption) {
 	// 类型断言，确保log实现了必要的方法
 	if logger, ok := log.(Logger); ok {
-		ginLogger := NewGinLogger(logger)
+		ginLogger := NewGinLogger(logger, opts...)
 		r.Use(ginLogger.Logger())
 		r.Use(ginLogger.Recovery())
 	}
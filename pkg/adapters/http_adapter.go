@@ -0,0 +1,59 @@
+package adapters
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// defaultRequestFieldHeaders RequestField默认记录的请求头
+var defaultRequestFieldHeaders = []string{"User-Agent", "Content-Type", "X-Request-ID", "X-Forwarded-For"}
+
+// sensitiveRequestHeaders RequestField从不记录的请求头
+var sensitiveRequestHeaders = headerSet([]string{"Authorization", "Proxy-Authorization", "Cookie", "X-Api-Key"})
+
+// RequestField 创建以紧凑摘要表示*http.Request的嵌套字段，适合在错误日志中代替反射输出的整个结构体：
+// method、host、path（不含查询串）、remote_addr，以及User-Agent、Content-Type、X-Request-ID、X-Forwarded-For
+// 和headers中额外列出的请求头。Authorization、Cookie、X-Api-Key等敏感头即使列出也不会输出，r为nil时字段值为nil
+func RequestField(key string, r *http.Request, headers ...string) Field {
+	if r == nil {
+		return Field{Key: key, Value: nil}
+	}
+
+	fields := []Field{
+		{Key: "method", Value: r.Method},
+		{Key: "host", Value: r.Host},
+		{Key: "path", Value: requestPath(r)},
+		{Key: "remote_addr", Value: r.RemoteAddr},
+	}
+	names := append(append([]string(nil), defaultRequestFieldHeaders...), headers...)
+	if values := requestHeaders(r.Header, names); len(values) > 0 {
+		fields = append(fields, Field{Key: "headers", Value: values})
+	}
+	return logger.Group(key, fields...)
+}
+
+// requestPath 获取请求路径，URL为nil时从RequestURI中去掉查询串
+func requestPath(r *http.Request) string {
+	if r.URL != nil {
+		return r.URL.Path
+	}
+	path, _, _ := strings.Cut(r.RequestURI, "?")
+	return path
+}
+
+// requestHeaders 从header中取出列出的请求头，多个值以逗号连接，跳过敏感头和不存在的头
+func requestHeaders(header http.Header, names []string) map[string]string {
+	values := make(map[string]string)
+	for _, name := range names {
+		key := http.CanonicalHeaderKey(name)
+		if sensitiveRequestHeaders[key] {
+			continue
+		}
+		if vs := header.Values(key); len(vs) > 0 {
+			values[key] = strings.Join(vs, ", ")
+		}
+	}
+	return values
+}
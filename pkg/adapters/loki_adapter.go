@@ -0,0 +1,235 @@
+package adapters
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// lokiPushPath Loki推送接口路径
+const lokiPushPath = "/loki/api/v1/push"
+
+// LokiWriter 将日志批量推送到Grafana Loki的输出目标
+type LokiWriter struct {
+	url           string
+	labels        map[string]string
+	client        *http.Client
+	batchSize     int
+	flushInterval time.Duration
+	maxRetries    int
+	backoff       time.Duration
+
+	mu      sync.Mutex
+	values  [][2]string
+	dropped uint64
+
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// LokiOption LokiWriter配置选项
+type LokiOption func(*LokiWriter)
+
+// WithLokiFlushInterval 设置定时推送间隔
+func WithLokiFlushInterval(interval time.Duration) LokiOption {
+	return func(w *LokiWriter) {
+		w.flushInterval = interval
+	}
+}
+
+// WithLokiBatchSize 设置单批推送的最大日志条数
+func WithLokiBatchSize(size int) LokiOption {
+	return func(w *LokiWriter) {
+		w.batchSize = size
+	}
+}
+
+// WithLokiRetry 设置推送失败时的重试次数和初始退避时间
+func WithLokiRetry(maxRetries int, backoff time.Duration) LokiOption {
+	return func(w *LokiWriter) {
+		w.maxRetries = maxRetries
+		w.backoff = backoff
+	}
+}
+
+// WithLokiHTTPClient 设置推送使用的HTTP客户端
+func WithLokiHTTPClient(client *http.Client) LokiOption {
+	return func(w *LokiWriter) {
+		w.client = client
+	}
+}
+
+// 确保LokiWriter实现了WriteSyncer和io.Closer接口
+var (
+	_ logger.WriteSyncer = (*LokiWriter)(nil)
+	_ io.Closer          = (*LokiWriter)(nil)
+)
+
+// NewLokiWriter 创建Loki输出目标，url为Loki地址，labels为日志流标签（如job、service）
+func NewLokiWriter(url string, labels map[string]string, opts ...LokiOption) *LokiWriter {
+	if !strings.HasSuffix(url, lokiPushPath) {
+		url = strings.TrimSuffix(url, "/") + lokiPushPath
+	}
+
+	streamLabels := make(map[string]string, len(labels))
+	for k, v := range labels {
+		streamLabels[k] = v
+	}
+
+	w := &LokiWriter{
+		url:           url,
+		labels:        streamLabels,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		batchSize:     1000,
+		flushInterval: time.Second,
+		maxRetries:    3,
+		backoff:       100 * time.Millisecond,
+		done:          make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	if w.flushInterval > 0 {
+		w.wg.Add(1)
+		go w.flushLoop()
+	}
+
+	return w
+}
+
+// Write 将一行日志加入待推送批次
+func (w *LokiWriter) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+	timestamp := strconv.FormatInt(time.Now().UnixNano(), 10)
+
+	w.mu.Lock()
+	w.values = append(w.values, [2]string{timestamp, line})
+	full := len(w.values) >= w.batchSize
+	w.mu.Unlock()
+
+	if full {
+		if err := w.Sync(); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Sync 立即推送所有待推送的日志
+func (w *LokiWriter) Sync() error {
+	w.mu.Lock()
+	values := w.values
+	w.values = nil
+	w.mu.Unlock()
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	if err := w.push(values); err != nil {
+		atomic.AddUint64(&w.dropped, uint64(len(values)))
+		return err
+	}
+	return nil
+}
+
+// Close 停止定时推送并推送剩余的日志
+func (w *LokiWriter) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+	return w.Sync()
+}
+
+// Dropped 返回因推送持续失败而丢弃的日志条数
+func (w *LokiWriter) Dropped() uint64 {
+	return atomic.LoadUint64(&w.dropped)
+}
+
+// flushLoop 定时推送日志
+func (w *LokiWriter) flushLoop() {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.Sync()
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// lokiPushRequest Loki推送请求体
+type lokiPushRequest struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+// lokiStream Loki日志流
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// push 推送一批日志，失败时按指数退避重试
+func (w *LokiWriter) push(values [][2]string) error {
+	body, err := json.Marshal(lokiPushRequest{
+		Streams: []lokiStream{{Stream: w.labels, Values: values}},
+	})
+	if err != nil {
+		return err
+	}
+
+	backoff := w.backoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := w.send(body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= w.maxRetries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// send 发送一次推送请求，返回失败是否可以重试
+func (w *LokiWriter) send(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	// 4xx（限流除外）属于请求本身的问题，重试无意义
+	retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("loki push failed: %s", resp.Status)
+}
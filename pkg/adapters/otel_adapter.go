@@ -0,0 +1,123 @@
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BaggageExtractor 创建从上下文的W3C baggage中提取字段的上下文提取器，只提取keys中列出的条目，
+// 字段键与baggage键相同，可配合WithContextExtractor使用
+func BaggageExtractor(keys ...string) logger.ContextExtractor {
+	allowed := make([]string, len(keys))
+	copy(allowed, keys)
+
+	return func(ctx context.Context) []Field {
+		bag := baggage.FromContext(ctx)
+		if bag.Len() == 0 {
+			return nil
+		}
+
+		var fields []Field
+		for _, key := range allowed {
+			member := bag.Member(key)
+			if member.Key() == "" {
+				continue
+			}
+			fields = append(fields, Field{Key: key, Value: member.Value()})
+		}
+		return fields
+	}
+}
+
+// TraceExtractor 创建从上下文的OpenTelemetry span上下文中提取追踪字段的上下文提取器：trace_id、span_id，
+// 以及由采样标志得到的布尔字段trace_sampled，便于只筛选被采样追踪的日志。上下文中没有有效的span上下文时不添加字段，
+// 可配合WithContextExtractor使用
+func TraceExtractor() logger.ContextExtractor {
+	return func(ctx context.Context) []Field {
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.IsValid() {
+			return nil
+		}
+		return []Field{
+			{Key: "trace_id", Value: sc.TraceID().String()},
+			{Key: "span_id", Value: sc.SpanID().String()},
+			{Key: "trace_sampled", Value: sc.IsSampled()},
+		}
+	}
+}
+
+// SpanEventHook 创建将日志记录为span事件的日志钩子：级别不低于minLevel的日志在上下文中正在记录的span上
+// 添加以日志消息为名称的事件，字段转换为事件属性，另加level属性，使错误同时出现在追踪的时间线上。
+// 上下文中没有正在记录的span时不做任何事，可配合WithEntryHook使用
+func SpanEventHook(minLevel logger.LogLevel) logger.EntryHook {
+	return func(ctx context.Context, level logger.LogLevel, msg string, fields []Field) {
+		if level < minLevel {
+			return
+		}
+		span := trace.SpanFromContext(ctx)
+		if !span.IsRecording() {
+			return
+		}
+
+		attrs := make([]attribute.KeyValue, 0, len(fields)+1)
+		attrs = append(attrs, attribute.String("level", level.String()))
+		for _, field := range fields {
+			attrs = append(attrs, otelAttribute(field.Key, field.Value))
+		}
+		span.AddEvent(msg, trace.WithAttributes(attrs...))
+	}
+}
+
+// otelAttribute 将字段值转换为OpenTelemetry属性，不支持的类型转换为字符串
+func otelAttribute(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int8:
+		return attribute.Int64(key, int64(v))
+	case int16:
+		return attribute.Int64(key, int64(v))
+	case int32:
+		return attribute.Int64(key, int64(v))
+	case int64:
+		return attribute.Int64(key, v)
+	case uint8:
+		return attribute.Int64(key, int64(v))
+	case uint16:
+		return attribute.Int64(key, int64(v))
+	case uint32:
+		return attribute.Int64(key, int64(v))
+	case float32:
+		return attribute.Float64(key, float64(v))
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case time.Time:
+		return attribute.String(key, v.Format(time.RFC3339Nano))
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,33 @@
+package adapters
+
+import (
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// ProtoField 创建值为protobuf消息的字段，JSON输出中使用protojson编码为对象，文本输出中为紧凑的JSON字符串
+func ProtoField(key string, m proto.Message) Field {
+	return Field{Key: key, Value: protoValue{message: m}}
+}
+
+// protoValue 使用protojson编码的protobuf消息
+type protoValue struct {
+	message proto.Message
+}
+
+// MarshalJSON 使用protojson编码消息
+func (v protoValue) MarshalJSON() ([]byte, error) {
+	if v.message == nil {
+		return []byte("null"), nil
+	}
+	return protojson.Marshal(v.message)
+}
+
+// String 返回消息的紧凑JSON表示
+func (v protoValue) String() string {
+	data, err := v.MarshalJSON()
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
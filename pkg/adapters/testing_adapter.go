@@ -0,0 +1,40 @@
+package adapters
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// testLogTimeLayout 测试日志的时间格式，测试输出中日期没有意义
+const testLogTimeLayout = "15:04:05.000"
+
+// NewTestLogger 创建通过tb.Log输出的日志实例，日志归属于当前测试，只在测试失败或使用-v时显示。
+// 默认级别为Debug，日志名称为测试名称，致命级日志调用tb.Fatal结束当前测试而不是退出进程，
+// 错误级日志只输出而不会使测试失败
+func NewTestLogger(tb testing.TB, opts ...logger.Option) Logger {
+	encoder := &logger.TextEncoder{TimeLayout: testLogTimeLayout, SanitizeNewlines: true}
+	name := tb.Name()
+
+	emit := func(level LogLevel, msg string, fields []Field) {
+		tb.Helper()
+		line, err := encoder.Encode(logger.Entry{Time: time.Now(), Level: level, Name: name, Message: msg, Fields: fields})
+		if err != nil {
+			tb.Logf("[%s] %s encode_error=%v", level, msg, err)
+			return
+		}
+
+		text := strings.TrimSuffix(string(line), "\n")
+		if level == FatalLevel {
+			tb.Fatal(text)
+			return
+		}
+		tb.Log(text)
+	}
+
+	// tb.Fatal已经结束当前测试，不再退出进程，调用方传入的选项可以覆盖
+	defaults := []logger.Option{logger.WithExitFunc(func(int) {})}
+	return logger.NewFuncLogger(name, DebugLevel, emit, append(defaults, opts...)...)
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// AuditLevel 审计日志使用的固定级别，使用错误级别以通过生产环境常用的错误级别阈值
+const AuditLevel = ErrorLevel
+
+// AuditLogger 审计日志包装器，Audit输出的日志固定为AuditLevel级别并带有audit=true标记字段，
+// 不受日志实例级别、采样和按字段值限流的影响，用于密钥轮换、配置重新加载等合规相关的事件。
+// 门面级别的豁免依赖上下文中的级别覆盖，被包装的zap、logrus实例或自定义Logger仍按自身级别过滤，
+// 其级别高于错误级别（如只输出致命级日志）时审计日志仍会被丢弃。其他方法直接转发给被包装的日志实例
+type AuditLogger struct {
+	Logger
+}
+
+// NewAuditLogger 创建包装inner的审计日志实例
+func NewAuditLogger(inner Logger) *AuditLogger {
+	return &AuditLogger{Logger: inner}
+}
+
+// contextWithAudit 标记上下文中的日志为审计日志，并将级别覆盖设置为AuditLevel，使其通过级别检查
+func contextWithAudit(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return ContextWithLevel(context.WithValue(ctx, auditContextKey, true), AuditLevel)
+}
+
+// isAuditContext 判断上下文中的日志是否为审计日志
+func isAuditContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	audit, _ := ctx.Value(auditContextKey).(bool)
+	return audit
+}
+
+// Audit 输出审计日志，action作为日志消息
+func (a *AuditLogger) Audit(action string, fields ...Field) {
+	a.AuditCtx(context.Background(), action, fields...)
+}
+
+// AuditCtx 使用ctx中提取的字段输出审计日志，action作为日志消息
+func (a *AuditLogger) AuditCtx(ctx context.Context, action string, fields ...Field) {
+	auditFields := make([]Field, 0, len(fields)+1)
+	auditFields = append(auditFields, Field{Key: "audit", Value: true})
+	auditFields = append(auditFields, fields...)
+	a.Logger.ErrorCtx(contextWithAudit(ctx), action, auditFields...)
+}
+
+// WithFields 添加字段到日志，返回的日志实例仍为审计日志实例
+func (a *AuditLogger) WithFields(fields ...Field) Logger {
+	return &AuditLogger{Logger: a.Logger.WithFields(fields...)}
+}
+
+// WithField 添加单个字段到日志，返回的日志实例仍为审计日志实例
+func (a *AuditLogger) WithField(key string, value interface{}) Logger {
+	return &AuditLogger{Logger: a.Logger.WithField(key, value)}
+}
+
+// WithContext 添加上下文到日志，返回的日志实例仍为审计日志实例
+func (a *AuditLogger) WithContext(ctx context.Context) Logger {
+	return &AuditLogger{Logger: a.Logger.WithContext(ctx)}
+}
+
+// WithError 添加错误信息到日志，返回的日志实例仍为审计日志实例
+func (a *AuditLogger) WithError(err error) Logger {
+	return &AuditLogger{Logger: a.Logger.WithError(err)}
+}
+
+// WithTime 添加时间到日志，返回的日志实例仍为审计日志实例
+func (a *AuditLogger) WithTime(t time.Time) Logger {
+	return &AuditLogger{Logger: a.Logger.WithTime(t)}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvironmentEnvVar 未通过WithEnvironment指定环境时，auto提供者读取的环境变量
+const EnvironmentEnvVar = "LANDCLOGFACE_ENV"
+
+// NewAutoLogger 根据运行环境创建日志实例：开发环境使用控制台日志，输出到终端时带颜色，生产环境使用zap输出JSON。
+// 环境由WithEnvironment指定，未指定时读取LANDCLOGFACE_ENV环境变量，默认为生产环境
+func NewAutoLogger(name string, opts ...Option) Logger {
+	options := &LoggerOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	env := options.Environment
+	if env == "" {
+		env = os.Getenv(EnvironmentEnvVar)
+	}
+
+	if isDevelopmentEnv(env) {
+		// 只在输出到终端时使用颜色，避免ANSI转义序列写入日志文件
+		return NewConsoleLogger(name, append([]Option{WithColor(outputIsTerminal(options))}, opts...)...)
+	}
+	return NewZapLogger(name, opts...)
+}
+
+// isDevelopmentEnv 判断环境名称是否表示开发环境
+func isDevelopmentEnv(env string) bool {
+	switch strings.ToLower(env) {
+	case "dev", "development", "local":
+		return true
+	default:
+		return false
+	}
+}
+
+// AutoLoggerProvider 根据运行环境选择后端的日志提供者
+type AutoLoggerProvider struct{}
+
+// NewAutoLoggerProvider 创建auto日志提供者
+func NewAutoLoggerProvider() *AutoLoggerProvider {
+	return &AutoLoggerProvider{}
+}
+
+// Create 创建日志实例
+func (p *AutoLoggerProvider) Create(name string) Logger {
+	return NewAutoLogger(name)
+}
+
+// CreateWithConfig 根据配置创建日志实例
+func (p *AutoLoggerProvider) CreateWithConfig(name string, config map[string]interface{}) Logger {
+	opts := []Option{WithConfig(config)}
+
+	if lvl, ok := config["level"].(LogLevel); ok {
+		opts = append(opts, WithLevel(lvl))
+	}
+	if path, ok := config["outputPath"].(string); ok {
+		opts = append(opts, WithOutputPath(path))
+	}
+	if env, ok := config["environment"].(string); ok {
+		opts = append(opts, WithEnvironment(env))
+	}
+
+	return NewAutoLogger(name, opts...)
+}
@@ -0,0 +1,50 @@
+package logger
+
+import "sync"
+
+// 构建信息，可以在编译时通过ldflags设置，如
+// go build -ldflags "-X github.com/LandcLi/LandcLogFace/pkg/logger.buildVersion=v1.2.0 -X github.com/LandcLi/LandcLogFace/pkg/logger.buildCommit=$(git rev-parse HEAD)"
+var (
+	buildVersion   string
+	buildCommit    string
+	buildTimestamp string
+	buildInfoMu    sync.RWMutex
+)
+
+// BuildInfo 程序的构建信息
+type BuildInfo struct {
+	Version   string
+	Commit    string
+	BuildTime string
+}
+
+// SetBuildInfo 设置全局构建信息，开启WithBuildInfoFields后新创建的日志实例会附加version、commit和build_time字段，
+// 已创建的日志实例不受影响
+func SetBuildInfo(version, commit, buildTime string) {
+	buildInfoMu.Lock()
+	defer buildInfoMu.Unlock()
+	buildVersion, buildCommit, buildTimestamp = version, commit, buildTime
+}
+
+// GetBuildInfo 获取全局构建信息
+func GetBuildInfo() BuildInfo {
+	buildInfoMu.RLock()
+	defer buildInfoMu.RUnlock()
+	return BuildInfo{Version: buildVersion, Commit: buildCommit, BuildTime: buildTimestamp}
+}
+
+// buildInfoFields 将构建信息转换为字段，未设置的项不输出
+func buildInfoFields() []Field {
+	info := GetBuildInfo()
+	fields := make([]Field, 0, 3)
+	for _, field := range []Field{
+		{Key: "version", Value: info.Version},
+		{Key: "commit", Value: info.Commit},
+		{Key: "build_time", Value: info.BuildTime},
+	} {
+		if field.Value != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// circuitBreakerWriter 带熔断的输出，主输出连续写入失败达到阈值后暂停写入并改为写入备用输出，
+// 避免失效的网络或文件输出拖慢应用
+type circuitBreakerWriter struct {
+	primary   io.Writer
+	fallback  io.Writer
+	threshold int
+	cooldown  time.Duration
+	clock     Clock
+
+	mu        sync.Mutex
+	failures  int       // 连续失败次数
+	openUntil time.Time // 熔断结束时间，零值表示未熔断
+}
+
+// newCircuitBreakerWriter 创建带熔断的输出，fallback为nil时使用标准错误输出
+func newCircuitBreakerWriter(primary, fallback io.Writer, threshold int, cooldown time.Duration, clock Clock) *circuitBreakerWriter {
+	if fallback == nil {
+		fallback = os.Stderr
+	}
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &circuitBreakerWriter{
+		primary:   primary,
+		fallback:  fallback,
+		threshold: threshold,
+		cooldown:  cooldown,
+		clock:     clock,
+	}
+}
+
+// Write 写入主输出，熔断期间直接写入备用输出，冷却结束后的写入作为探测重新尝试主输出。
+// 主输出写入失败的数据同样转写到备用输出，不会丢失
+func (c *circuitBreakerWriter) Write(p []byte) (int, error) {
+	if c.isOpen() {
+		return c.fallback.Write(p)
+	}
+
+	n, err := c.primary.Write(p)
+	if err == nil {
+		c.recordSuccess()
+		return n, nil
+	}
+
+	if c.recordFailure() {
+		internalWarnf("log output failed %d times, diverting to fallback for %s: %v", c.threshold, c.cooldown, err)
+	}
+	return c.fallback.Write(p)
+}
+
+// isOpen 判断当前是否处于熔断期间
+func (c *circuitBreakerWriter) isOpen() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.openUntil.IsZero() && c.clock.Now().Before(c.openUntil)
+}
+
+// recordSuccess 主输出写入成功，关闭熔断并清零失败次数
+func (c *circuitBreakerWriter) recordSuccess() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failures = 0
+	c.openUntil = time.Time{}
+}
+
+// recordFailure 记录一次主输出写入失败，失败次数达到阈值或探测失败时开始熔断，返回是否新开始熔断
+func (c *circuitBreakerWriter) recordFailure() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failures++
+	probing := !c.openUntil.IsZero()
+	if c.failures < c.threshold && !probing {
+		return false
+	}
+	c.openUntil = c.clock.Now().Add(c.cooldown)
+	// 探测失败时保持熔断，不再重复警告
+	return !probing
+}
+
+// Sync 刷新输出，熔断期间只刷新备用输出，避免等待失效的主输出
+func (c *circuitBreakerWriter) Sync() error {
+	if c.isOpen() {
+		return syncOutput(c.fallback)
+	}
+	return syncOutput(c.primary)
+}
+
+// Close 关闭主输出，备用输出由调用方管理
+func (c *circuitBreakerWriter) Close() error {
+	return closeOutput(c.primary)
+}
@@ -10,7 +10,7 @@ type LogConfig struct {
 	Provider     string        `json:"provider" yaml:"provider"`     // 日志提供者名称
 	Name         string        `json:"name" yaml:"name"`             // 日志名称
 	Level        LogLevel      `json:"level" yaml:"level"`           // 日志级别
-	Format       string        `json:"format" yaml:"format"`         // 日志格式（text/json）
+	Format       string        `json:"format" yaml:"format"`         // 日志格式（text/json/logfmt/cloudevents/gcp）
 	OutputPath   string        `json:"outputPath" yaml:"outputPath"` // 日志输出路径
 
 	// 日志文件轮转配置
@@ -157,7 +157,7 @@ func (c *LogConfig) Validate() bool {
 	// 验证格式
 	if c.Format == "" {
 		c.Format = "text"
-	} else if c.Format != "text" && c.Format != "json" {
+	} else if c.Format != "text" && c.Format != "json" && c.Format != "logfmt" && c.Format != "cloudevents" && c.Format != "gcp" {
 		c.Format = "text"
 	}
 
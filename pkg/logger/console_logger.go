@@ -3,63 +3,61 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"strings"
 	"time"
-
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // ConsoleLogger 默认的控制台日志适配器
 type ConsoleLogger struct {
-	level  LogLevel
-	fields []Field
-	ctx    context.Context
-	logger *log.Logger
-	name   string
+	level   LogLevel
+	fields  []Field
+	ctx     context.Context
+	logger  *log.Logger
+	encoder Encoder
+	name    string
+	sites   []string // 开启字段追踪时各字段的添加位置
+	core    *loggerCore
 }
 
 // NewConsoleLogger 创建控制台日志实例
 func NewConsoleLogger(name string, opts ...Option) *ConsoleLogger {
 	options := &LoggerOptions{
-		Level:          InfoLevel,
-		Format:         "text",
-		OutputPath:     "stdout",
-		MaxLogSize:     100,                // 默认100MB
-		MaxLogAge:      7 * 24 * time.Hour, // 默认7天
-		MaxLogFiles:    10,                 // 默认10个文件
-		CompressLogs:   false,              // 默认不压缩
-		MaxMessageSize: 0,                  // 默认不限制
-		Config:         make(map[string]interface{}),
+		Level:            InfoLevel,
+		Format:           "text",
+		OutputPath:       "stdout",
+		MaxLogSize:       100,                // 默认100MB
+		MaxLogAge:        7 * 24 * time.Hour, // 默认7天
+		MaxLogFiles:      10,                 // 默认10个文件
+		CompressLogs:     false,              // 默认不压缩
+		MaxMessageSize:   0,                  // 默认不限制
+		AlwaysLogAbove:   ErrorLevel,         // 默认错误及以上级别不受采样和限流影响
+		SanitizeNewlines: true,               // 默认转义文本格式中的换行符
+		Config:           make(map[string]interface{}),
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
 
-	var output interface {
-		Write(p []byte) (n int, err error)
-	}
-	if options.OutputPath == "stdout" {
-		output = os.Stdout
-	} else {
-		// 使用lumberjack进行日志轮转
-		output = &lumberjack.Logger{
-			Filename:   options.OutputPath,
-			MaxSize:    int(options.MaxLogSize),             // MB
-			MaxAge:     int(options.MaxLogAge.Hours() / 24), // 天
-			MaxBackups: options.MaxLogFiles,
-			Compress:   options.CompressLogs,
-		}
+	// 配置输出
+	core := newLoggerCore(options)
+
+	c := &ConsoleLogger{
+		level:   options.Level,
+		fields:  make([]Field, 0),
+		ctx:     context.Background(),
+		logger:  log.New(core.writer(), "", 0),
+		encoder: newEncoder(options),
+		name:    name,
+		core:    core,
 	}
 
-	return &ConsoleLogger{
-		level:  options.Level,
-		fields: make([]Field, 0),
-		ctx:    context.Background(),
-		logger: log.New(output, "", 0),
-		name:   name,
-	}
+	c.core.startHeartbeat(func(count uint64) {
+		c.Info(c.core.heartbeatMessage(), Field{Key: "heartbeat", Value: count})
+	})
+	return c
 }
 
 // SetLevel 设置日志级别
@@ -72,119 +70,166 @@ func (c *ConsoleLogger) GetLevel() LogLevel {
 	return c.level
 }
 
-// formatMessage 格式化日志消息
-func (c *ConsoleLogger) formatMessage(level LogLevel, msg string, fields []Field) string {
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	allFields := append(c.fields, fields...)
-
-	fieldStr := ""
-	for _, field := range allFields {
-		fieldStr += fmt.Sprintf(" %s=%v", field.Key, field.Value)
+// formatMessage 使用编码器格式化日志消息
+func (c *ConsoleLogger) formatMessage(ctx context.Context, level LogLevel, msg string, fields []Field) string {
+	entry := getEntry()
+	defer putEntry(entry)
+	*entry = newEntry(c.core.now(), level, c.name, c.core.redactMessage(msg), c.core.appendMergedFields(entry.Fields, level, msg, c.fields, c.sites, ctx, fields))
+	line, err := c.encoder.Encode(*entry)
+	if err != nil {
+		return fmt.Sprintf("%s [%s] [%s] %s encode_error=%v", entry.Time.Format(DefaultTextTimeLayout), level.String(), c.name, msg, err)
 	}
+	return strings.TrimSuffix(string(line), "\n")
+}
 
-	return fmt.Sprintf("%s [%s] [%s] %s%s", timestamp, level.String(), c.name, msg, fieldStr)
+// log 输出一条日志，致命级日志退出程序，恐慌级日志触发panic
+func (c *ConsoleLogger) log(ctx context.Context, level LogLevel, msg string, fields []Field) {
+	line := c.formatMessage(ctx, level, msg, fields)
+	c.logger.Println(line)
+
+	switch level {
+	case FatalLevel:
+		c.core.exit(1)
+	case PanicLevel:
+		c.Sync()
+		c.core.writeCrashDump(line)
+		panic(line)
+	}
 }
 
 // Debug 输出调试级日志
 func (c *ConsoleLogger) Debug(msg string, fields ...Field) {
-	if c.level <= DebugLevel {
-		c.logger.Println(c.formatMessage(DebugLevel, msg, fields))
+	if levelEnabled(c.level, c.ctx, DebugLevel) && c.core.allow(DebugLevel, c.fields, c.ctx, fields) {
+		c.log(c.ctx, DebugLevel, msg, fields)
 	}
 }
 
 // Debugf 输出格式化的调试级日志
 func (c *ConsoleLogger) Debugf(format string, args ...interface{}) {
-	if c.level <= DebugLevel {
-		msg := fmt.Sprintf(format, args...)
-		c.logger.Println(c.formatMessage(DebugLevel, msg, nil))
+	if levelEnabled(c.level, c.ctx, DebugLevel) && c.core.allow(DebugLevel, c.fields, c.ctx, nil) {
+		c.log(c.ctx, DebugLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// DebugCtx 使用ctx中提取的字段输出调试级日志
+func (c *ConsoleLogger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(c.level, ctx, DebugLevel) && c.core.allow(DebugLevel, c.fields, ctx, fields) {
+		c.log(ctx, DebugLevel, msg, fields)
 	}
 }
 
 // Info 输出信息级日志
 func (c *ConsoleLogger) Info(msg string, fields ...Field) {
-	if c.level <= InfoLevel {
-		c.logger.Println(c.formatMessage(InfoLevel, msg, fields))
+	if levelEnabled(c.level, c.ctx, InfoLevel) && c.core.allow(InfoLevel, c.fields, c.ctx, fields) {
+		c.log(c.ctx, InfoLevel, msg, fields)
 	}
 }
 
 // Infof 输出格式化的信息级日志
 func (c *ConsoleLogger) Infof(format string, args ...interface{}) {
-	if c.level <= InfoLevel {
-		msg := fmt.Sprintf(format, args...)
-		c.logger.Println(c.formatMessage(InfoLevel, msg, nil))
+	if levelEnabled(c.level, c.ctx, InfoLevel) && c.core.allow(InfoLevel, c.fields, c.ctx, nil) {
+		c.log(c.ctx, InfoLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// InfoCtx 使用ctx中提取的字段输出信息级日志
+func (c *ConsoleLogger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(c.level, ctx, InfoLevel) && c.core.allow(InfoLevel, c.fields, ctx, fields) {
+		c.log(ctx, InfoLevel, msg, fields)
 	}
 }
 
 // Warn 输出警告级日志
 func (c *ConsoleLogger) Warn(msg string, fields ...Field) {
-	if c.level <= WarnLevel {
-		c.logger.Println(c.formatMessage(WarnLevel, msg, fields))
+	if levelEnabled(c.level, c.ctx, WarnLevel) && c.core.allow(WarnLevel, c.fields, c.ctx, fields) {
+		c.log(c.ctx, WarnLevel, msg, fields)
 	}
 }
 
 // Warnf 输出格式化的警告级日志
 func (c *ConsoleLogger) Warnf(format string, args ...interface{}) {
-	if c.level <= WarnLevel {
-		msg := fmt.Sprintf(format, args...)
-		c.logger.Println(c.formatMessage(WarnLevel, msg, nil))
+	if levelEnabled(c.level, c.ctx, WarnLevel) && c.core.allow(WarnLevel, c.fields, c.ctx, nil) {
+		c.log(c.ctx, WarnLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// WarnCtx 使用ctx中提取的字段输出警告级日志
+func (c *ConsoleLogger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(c.level, ctx, WarnLevel) && c.core.allow(WarnLevel, c.fields, ctx, fields) {
+		c.log(ctx, WarnLevel, msg, fields)
 	}
 }
 
 // Error 输出错误级日志
 func (c *ConsoleLogger) Error(msg string, fields ...Field) {
-	if c.level <= ErrorLevel {
-		c.logger.Println(c.formatMessage(ErrorLevel, msg, fields))
+	if levelEnabled(c.level, c.ctx, ErrorLevel) && c.core.allow(ErrorLevel, c.fields, c.ctx, fields) {
+		c.log(c.ctx, ErrorLevel, msg, fields)
 	}
 }
 
 // Errorf 输出格式化的错误级日志
 func (c *ConsoleLogger) Errorf(format string, args ...interface{}) {
-	if c.level <= ErrorLevel {
-		msg := fmt.Sprintf(format, args...)
-		c.logger.Println(c.formatMessage(ErrorLevel, msg, nil))
+	if levelEnabled(c.level, c.ctx, ErrorLevel) && c.core.allow(ErrorLevel, c.fields, c.ctx, nil) {
+		c.log(c.ctx, ErrorLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// ErrorCtx 使用ctx中提取的字段输出错误级日志
+func (c *ConsoleLogger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(c.level, ctx, ErrorLevel) && c.core.allow(ErrorLevel, c.fields, ctx, fields) {
+		c.log(ctx, ErrorLevel, msg, fields)
 	}
 }
 
 // Fatal 输出致命级日志并退出程序
 func (c *ConsoleLogger) Fatal(msg string, fields ...Field) {
-	if c.level <= FatalLevel {
-		c.logger.Println(c.formatMessage(FatalLevel, msg, fields))
-		os.Exit(1)
+	if levelEnabled(c.level, c.ctx, FatalLevel) && c.core.allow(FatalLevel, c.fields, c.ctx, fields) {
+		c.log(c.ctx, FatalLevel, msg, fields)
 	}
 }
 
 // Fatalf 输出格式化的致命级日志并退出程序
 func (c *ConsoleLogger) Fatalf(format string, args ...interface{}) {
-	if c.level <= FatalLevel {
-		msg := fmt.Sprintf(format, args...)
-		c.logger.Println(c.formatMessage(FatalLevel, msg, nil))
-		os.Exit(1)
+	if levelEnabled(c.level, c.ctx, FatalLevel) && c.core.allow(FatalLevel, c.fields, c.ctx, nil) {
+		c.log(c.ctx, FatalLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+func (c *ConsoleLogger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(c.level, ctx, FatalLevel) && c.core.allow(FatalLevel, c.fields, ctx, fields) {
+		c.log(ctx, FatalLevel, msg, fields)
 	}
 }
 
 // Panic 输出恐慌级日志并触发panic
 func (c *ConsoleLogger) Panic(msg string, fields ...Field) {
-	if c.level <= PanicLevel {
-		msg := c.formatMessage(PanicLevel, msg, fields)
-		c.logger.Println(msg)
-		panic(msg)
+	if levelEnabled(c.level, c.ctx, PanicLevel) && c.core.allow(PanicLevel, c.fields, c.ctx, fields) {
+		c.log(c.ctx, PanicLevel, msg, fields)
 	}
 }
 
 // Panicf 输出格式化的恐慌级日志并触发panic
 func (c *ConsoleLogger) Panicf(format string, args ...interface{}) {
-	if c.level <= PanicLevel {
-		msg := fmt.Sprintf(format, args...)
-		fullMsg := c.formatMessage(PanicLevel, msg, nil)
-		c.logger.Println(fullMsg)
-		panic(fullMsg)
+	if levelEnabled(c.level, c.ctx, PanicLevel) && c.core.allow(PanicLevel, c.fields, c.ctx, nil) {
+		c.log(c.ctx, PanicLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+func (c *ConsoleLogger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(c.level, ctx, PanicLevel) && c.core.allow(PanicLevel, c.fields, ctx, fields) {
+		c.log(ctx, PanicLevel, msg, fields)
 	}
 }
 
 // WithFields 添加字段到日志
 func (c *ConsoleLogger) WithFields(fields ...Field) Logger {
 	newLogger := *c
+	newLogger.fields = make([]Field, 0, len(c.fields)+len(fields))
+	newLogger.fields = append(newLogger.fields, c.fields...)
 	newLogger.fields = append(newLogger.fields, fields...)
+	newLogger.sites = c.core.traceFields(c.sites, fields)
 	return &newLogger
 }
 
@@ -193,6 +238,22 @@ func (c *ConsoleLogger) WithField(key string, value interface{}) Logger {
 	return c.WithFields(Field{Key: key, Value: value})
 }
 
+// Clone 以新的名称复制日志实例，保留级别、字段、上下文和输出配置
+func (c *ConsoleLogger) Clone(name string) Logger {
+	newLogger := *c
+	newLogger.name = name
+	newLogger.fields = append([]Field(nil), c.fields...)
+	newLogger.sites = append([]string(nil), c.sites...)
+	newLogger.core = c.core.clone()
+	newLogger.logger = log.New(newLogger.core.writer(), "", 0)
+	return &newLogger
+}
+
+// WithStruct 将结构体的导出字段展开为 prefix.FieldName 形式的字段添加到日志
+func (c *ConsoleLogger) WithStruct(prefix string, v interface{}) Logger {
+	return c.WithFields(StructFields(prefix, v)...)
+}
+
 // WithContext 添加上下文到日志
 func (c *ConsoleLogger) WithContext(ctx context.Context) Logger {
 	newLogger := *c
@@ -200,8 +261,11 @@ func (c *ConsoleLogger) WithContext(ctx context.Context) Logger {
 	return &newLogger
 }
 
-// WithError 添加错误信息到日志
+// WithError 添加错误信息到日志，err为nil时返回当前日志实例
 func (c *ConsoleLogger) WithError(err error) Logger {
+	if err == nil {
+		return c
+	}
 	return c.WithField("error", err)
 }
 
@@ -240,9 +304,51 @@ func (c *ConsoleLogger) IsPanicEnabled() bool {
 	return c.level <= PanicLevel
 }
 
-// Sync 刷新日志缓冲区
+// IsEnabled 检查level级别是否启用，与实际输出日志时一样考虑上下文中的级别覆盖
+func (c *ConsoleLogger) IsEnabled(level LogLevel) bool {
+	return levelEnabled(c.level, c.ctx, level)
+}
+
+// SetOutput 替换日志输出目标，派生的日志实例同时生效，原输出目标不会被关闭
+func (c *ConsoleLogger) SetOutput(w io.Writer) {
+	c.core.setOutput(w)
+}
+
+// Stats 获取日志输出统计
+func (c *ConsoleLogger) Stats() LoggerStats {
+	stats := c.core.stats.snapshot()
+	stats.FieldSuppressed = c.core.fieldSuppressed()
+	return stats
+}
+
+// ResetSampling 清空按字段值限流的计数窗口，被抑制的消息可以立即再次输出，派生的日志实例同时生效。
+// 适合在每个请求或测试开始时调用，避免上一阶段的限流状态影响下一阶段，统计中累计的抑制条数不受影响
+func (c *ConsoleLogger) ResetSampling() {
+	c.core.resetSampling()
+}
+
+// StartTimer 将uptime字段的起始时间重置为当前时间，派生的日志实例同时生效
+func (c *ConsoleLogger) StartTimer() {
+	c.core.startTimer()
+}
+
+// Describe 获取日志实例的有效配置信息
+func (c *ConsoleLogger) Describe() LoggerInfo {
+	return c.core.describe("console", c.name, c.level)
+}
+
+// Sync 输出采样汇总并刷新日志缓冲区
 func (c *ConsoleLogger) Sync() error {
-	return nil
+	if level, fields, ok := c.core.samplingSummary(); ok && c.level <= level {
+		c.log(c.ctx, level, samplingSummaryMessage, fields)
+	}
+	return c.core.sync()
+}
+
+// Close 刷新并关闭日志输出，标准输出不会被关闭
+func (c *ConsoleLogger) Close() error {
+	c.Sync()
+	return c.core.close()
 }
 
 // ConsoleLoggerProvider 控制台日志提供者
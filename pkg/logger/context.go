@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+)
+
+// ContextExtractor 从上下文中提取日志字段
+type ContextExtractor func(ctx context.Context) []Field
+
+// contextKey 日志门面在上下文中使用的键类型
+type contextKey int
+
+const (
+	// fieldsContextKey 上下文中附加字段的键
+	fieldsContextKey contextKey = iota
+	// levelContextKey 上下文中日志级别覆盖的键
+	levelContextKey
+	// operationContextKey 上下文中操作名称的键
+	operationContextKey
+	// auditContextKey 上下文中审计日志标记的键
+	auditContextKey
+	// tenantContextKey 上下文中租户ID的键
+	tenantContextKey
+	// userContextKey 上下文中用户ID的键
+	userContextKey
+)
+
+// ContextWithFields 将字段附加到上下文，通过WithContext或*Ctx方法输出日志时自动提取
+func ContextWithFields(ctx context.Context, fields ...Field) context.Context {
+	existing := FieldsFromContext(ctx)
+	merged := make([]Field, 0, len(existing)+len(fields))
+	merged = append(merged, existing...)
+	merged = append(merged, fields...)
+	return context.WithValue(ctx, fieldsContextKey, merged)
+}
+
+// FieldsFromContext 获取通过ContextWithFields附加到上下文的字段
+func FieldsFromContext(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsContextKey).([]Field)
+	return fields
+}
+
+// ContextWithOperation 将操作名称附加到上下文，通过WithContext或*Ctx方法输出的日志自动添加operation字段，
+// 通常由中间件在请求入口设置为路由或span名称，内层设置的名称覆盖外层
+func ContextWithOperation(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operationContextKey, name)
+}
+
+// OperationFromContext 获取通过ContextWithOperation附加到上下文的操作名称
+func OperationFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	name, ok := ctx.Value(operationContextKey).(string)
+	return name, ok
+}
+
+// ContextWithTenant 将租户ID附加到上下文，通过WithContext或*Ctx方法输出的日志自动添加tenant_id字段，
+// 使多租户服务的日志使用统一的键，内层设置的值覆盖外层
+func ContextWithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey, tenantID)
+}
+
+// TenantFromContext 获取通过ContextWithTenant附加到上下文的租户ID
+func TenantFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	tenantID, ok := ctx.Value(tenantContextKey).(string)
+	return tenantID, ok
+}
+
+// ContextWithUser 将用户ID附加到上下文，通过WithContext或*Ctx方法输出的日志自动添加user_id字段，内层设置的值覆盖外层
+func ContextWithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userContextKey, userID)
+}
+
+// UserFromContext 获取通过ContextWithUser附加到上下文的用户ID
+func UserFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	userID, ok := ctx.Value(userContextKey).(string)
+	return userID, ok
+}
+
+// ContextWithLevel 将日志级别覆盖附加到上下文，通过WithContext或*Ctx方法输出日志时生效，
+// 只有低于日志实例自身级别时才生效，如在进程保持Info级别的同时以Debug级别处理单个请求
+func ContextWithLevel(ctx context.Context, level LogLevel) context.Context {
+	return context.WithValue(ctx, levelContextKey, level)
+}
+
+// LevelFromContext 获取通过ContextWithLevel附加到上下文的日志级别
+func LevelFromContext(ctx context.Context) (LogLevel, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(levelContextKey).(LogLevel)
+	return level, ok
+}
+
+// levelEnabled 判断target级别的日志是否输出，上下文中的级别覆盖低于日志实例级别时以覆盖为准
+func levelEnabled(level LogLevel, ctx context.Context, target LogLevel) bool {
+	if override, ok := LevelFromContext(ctx); ok && override < level {
+		level = override
+	}
+	return level <= target
+}
+
+// WithContextExtractor 添加上下文字段提取器，可多次调用添加多个
+func WithContextExtractor(extractor ContextExtractor) Option {
+	return func(opt *LoggerOptions) {
+		opt.ContextExtractors = append(opt.ContextExtractors, extractor)
+	}
+}
+
+// WithContextKeys 按键从上下文中取值作为字段输出，字段名为键的字符串形式（fmt.Sprint），
+// 适用于应用自定义的上下文键，上下文中不存在的键不输出。可多次调用添加多个
+func WithContextKeys(keys ...interface{}) Option {
+	return WithContextExtractor(contextKeysExtractor(keys))
+}
+
+// contextKeysExtractor 创建按键列表从上下文中取值的提取器
+func contextKeysExtractor(keys []interface{}) ContextExtractor {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = fmt.Sprint(key)
+	}
+	return func(ctx context.Context) []Field {
+		var fields []Field
+		for i, key := range keys {
+			if value := ctx.Value(key); value != nil {
+				fields = append(fields, Field{Key: names[i], Value: value})
+			}
+		}
+		return fields
+	}
+}
+
+// WithContextErrField 设置日志使用的上下文已取消或超时时是否添加ctx_err字段，
+// 便于发现在取消之后仍在继续的工作
+func WithContextErrField(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.ContextErrField = enabled
+	}
+}
@@ -0,0 +1,902 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+)
+
+// loggerCore 各适配器共享的日志处理核心，派生的日志实例共享同一个核心
+type loggerCore struct {
+	options     *LoggerOptions
+	constFields []Field // 常量字段，包括构造时附加的进程信息
+	jsonOutput  bool    // 后端是否输出JSON，只有JSON输出添加severity_code字段
+	seq         uint64  // 日志序号计数器，使用原子操作递增
+	stats       loggerStats
+
+	output     *coreOutput // 输出目标，复制的核心与原核心共享
+	ownsOutput bool        // 是否负责关闭输出目标，复制的核心不负责关闭
+
+	warnedKeys sync.Map // 已输出过冲突警告的保留键
+
+	allowedKeys map[string]bool // 允许输出的字段键，nil表示不限制
+	droppedKeys sync.Map        // 已输出过丢弃警告的字段键
+
+	fieldLimiters []*fieldRateLimiter // 按字段值限流的限流器
+
+	reportedSampled    uint64 // 上次汇总时的采样丢弃条数
+	reportedSuppressed uint64 // 上次汇总时的限流抑制条数
+
+	start int64 // uptime字段的起始时间（Unix纳秒）
+
+	heartbeat *heartbeat // 心跳goroutine，未配置心跳时为nil
+}
+
+// newLoggerCore 根据配置创建日志处理核心及其输出目标
+func newLoggerCore(options *LoggerOptions) *loggerCore {
+	return newLoggerCoreWithOutput(options, newOutput(options))
+}
+
+// wrappedLoggerOptions 生成包装已有日志实例时使用的配置，输出由被包装的实例负责
+func wrappedLoggerOptions(level LogLevel, format string, opts []Option) *LoggerOptions {
+	options := &LoggerOptions{
+		Level:          level,
+		Format:         format,
+		AlwaysLogAbove: ErrorLevel, // 默认错误及以上级别不受采样和限流影响
+		Config:         make(map[string]interface{}),
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	return options
+}
+
+// newLoggerCoreWithOutput 使用已创建的输出目标创建日志处理核心
+func newLoggerCoreWithOutput(options *LoggerOptions, output io.Writer) *loggerCore {
+	core := &loggerCore{
+		options:       options,
+		constFields:   constFields(options),
+		jsonOutput:    isJSONFormat(options.Format),
+		output:        &coreOutput{writer: output},
+		ownsOutput:    true,
+		fieldLimiters: newFieldRateLimiters(options.FieldRateLimits),
+		allowedKeys:   fieldAllowlist(options.FieldAllowlist),
+	}
+	core.startTimer()
+	return core
+}
+
+// clone 复制日志处理核心，与原核心共享配置和输出目标，任何一方替换输出目标对另一方同样生效，
+// 复制的核心不负责关闭输出目标。序号、统计、限流状态和心跳各自独立，
+// uptime字段的起始时间与原核心相同，复制的核心不启动心跳
+func (c *loggerCore) clone() *loggerCore {
+	core := &loggerCore{
+		options:       c.options,
+		constFields:   c.constFields,
+		jsonOutput:    c.jsonOutput,
+		output:        c.output,
+		fieldLimiters: newFieldRateLimiters(c.options.FieldRateLimits),
+		allowedKeys:   c.allowedKeys,
+	}
+	atomic.StoreInt64(&core.start, atomic.LoadInt64(&c.start))
+	return core
+}
+
+// constFields 根据配置生成常量字段，进程信息和构建信息字段位于用户常量字段之前
+func constFields(options *LoggerOptions) []Field {
+	if !options.Hostname && !options.PID && !options.BuildInfoFields {
+		return options.ConstFields
+	}
+
+	fields := make([]Field, 0, len(options.ConstFields)+5)
+	if options.Hostname {
+		fields = append(fields, Field{Key: "host", Value: hostname()})
+	}
+	if options.PID {
+		fields = append(fields, Field{Key: "pid", Value: os.Getpid()})
+	}
+	if options.BuildInfoFields {
+		fields = append(fields, buildInfoFields()...)
+	}
+	return append(fields, options.ConstFields...)
+}
+
+// globalSeq 进程内所有日志实例共享的日志序号计数器
+var globalSeq atomic.Uint64
+
+// 缓存的主机名，只查询一次
+var (
+	hostnameOnce  sync.Once
+	hostnameValue string
+)
+
+// hostname 获取缓存的主机名，查询失败时返回unknown
+func hostname() string {
+	hostnameOnce.Do(func() {
+		name, err := os.Hostname()
+		if err != nil {
+			internalWarnf("failed to get hostname: %v", err)
+			name = "unknown"
+		}
+		hostnameValue = name
+	})
+	return hostnameValue
+}
+
+// writer 获取写入当前输出目标的io.Writer，供各日志后端使用
+func (c *loggerCore) writer() io.Writer {
+	return coreWriter{core: c}
+}
+
+// coreOutput 日志处理核心的输出目标，复制的核心与原核心共享同一个coreOutput
+type coreOutput struct {
+	mu     sync.RWMutex
+	writer io.Writer
+}
+
+// currentOutput 获取当前输出目标
+func (c *loggerCore) currentOutput() io.Writer {
+	c.output.mu.RLock()
+	defer c.output.mu.RUnlock()
+	return c.output.writer
+}
+
+// setOutput 替换输出目标，返回时正在进行的写入已经完成，w使用SynchronizedWriter包装以保证每条日志完整写入，
+// 共享该输出目标的复制核心同样使用新的输出目标
+func (c *loggerCore) setOutput(w io.Writer) {
+	c.output.mu.Lock()
+	defer c.output.mu.Unlock()
+	c.output.writer = NewSynchronizedWriter(w)
+}
+
+// sync 刷新当前输出目标，忽略终端、管道等不支持fsync的输出返回的错误
+func (c *loggerCore) sync() error {
+	return filterBenignSyncErrors(syncOutput(c.currentOutput()))
+}
+
+// close 关闭当前输出目标，复制的核心不负责关闭输出目标，只刷新输出，原日志实例可以继续写入
+func (c *loggerCore) close() error {
+	c.stopHeartbeat()
+	if !c.ownsOutput {
+		return c.sync()
+	}
+	return closeOutput(c.currentOutput())
+}
+
+// exit 刷新输出并按注册顺序执行致命钩子后退出程序
+func (c *loggerCore) exit(code int) {
+	c.sync()
+	for _, hook := range c.options.FatalHooks {
+		runFatalHook(hook)
+	}
+
+	exit := c.options.ExitFunc
+	if exit == nil {
+		exit = os.Exit
+	}
+	exit(code)
+}
+
+// runFatalHook 执行致命钩子，钩子中的panic不会阻止程序退出
+func runFatalHook(hook func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			internalWarnf("fatal hook panicked: %v", r)
+		}
+	}()
+	hook()
+}
+
+// coreWriter 写入核心当前的输出目标并统计写入结果，每次写入对应一条日志
+type coreWriter struct {
+	core *loggerCore
+}
+
+// Write 写入当前输出目标并更新统计，关闭结尾换行符时去掉记录结尾的\n
+func (w coreWriter) Write(p []byte) (int, error) {
+	record := p
+	if w.core.options.NoTrailingNewline {
+		record = bytes.TrimSuffix(p, []byte("\n"))
+	}
+
+	w.core.output.mu.RLock()
+	n, err := w.core.output.writer.Write(record)
+	w.core.output.mu.RUnlock()
+
+	if err != nil {
+		atomic.AddUint64(&w.core.stats.dropped, 1)
+		return n, err
+	}
+	atomic.AddUint64(&w.core.stats.emitted, 1)
+	return len(p), nil
+}
+
+// Sync 刷新当前输出目标
+func (w coreWriter) Sync() error {
+	return w.core.sync()
+}
+
+// now 获取日志时间，未配置时钟时使用系统时钟
+func (c *loggerCore) now() time.Time {
+	if c.options.Clock != nil {
+		return c.options.Clock.Now()
+	}
+	return systemClock{}.Now()
+}
+
+// startTimer 将uptime字段的起始时间重置为当前时间
+func (c *loggerCore) startTimer() {
+	atomic.StoreInt64(&c.start, c.now().UnixNano())
+}
+
+// uptime 获取自起始时间以来经过的时长
+func (c *loggerCore) uptime() time.Duration {
+	return c.now().Sub(time.Unix(0, atomic.LoadInt64(&c.start)))
+}
+
+// alwaysLog 判断该级别的日志是否总是输出，采样和限流不得丢弃这些日志
+func (c *loggerCore) alwaysLog(level LogLevel) bool {
+	return level >= c.options.AlwaysLogAbove
+}
+
+// sample 按采样配置判断该级别的日志是否输出，被丢弃的日志计入采样统计
+func (c *loggerCore) sample(level LogLevel) bool {
+	sampling := c.options.LevelSampling
+	if sampling == nil || level > sampling.Level || c.alwaysLog(level) {
+		return true
+	}
+	if rand.Float64() < sampling.Probability {
+		return true
+	}
+	atomic.AddUint64(&c.stats.sampled, 1)
+	return false
+}
+
+// samplingSummaryMessage 采样汇总日志的消息
+const samplingSummaryMessage = "sampling summary"
+
+// samplingSummary 获取自上次汇总以来被采样丢弃和限流抑制的条数，未开启汇总或没有新的丢弃时返回false
+func (c *loggerCore) samplingSummary() (LogLevel, []Field, bool) {
+	if !c.options.SamplingSummary {
+		return 0, nil, false
+	}
+
+	sampled := atomic.LoadUint64(&c.stats.sampled)
+	suppressed := atomic.LoadUint64(&c.stats.suppressed)
+	sampled -= atomic.SwapUint64(&c.reportedSampled, sampled)
+	suppressed -= atomic.SwapUint64(&c.reportedSuppressed, suppressed)
+	if sampled == 0 && suppressed == 0 {
+		return 0, nil, false
+	}
+
+	return c.options.SummaryLevel, []Field{
+		{Key: "sampled", Value: sampled},
+		{Key: "suppressed", Value: suppressed},
+	}, true
+}
+
+// describe 根据配置生成日志实例信息
+func (c *loggerCore) describe(provider, name string, level LogLevel) LoggerInfo {
+	return LoggerInfo{
+		Name:       name,
+		Provider:   provider,
+		Level:      level,
+		Format:     c.options.Format,
+		OutputPath: c.outputPath(),
+	}
+}
+
+// outputPath 获取用于展示的输出路径，多个路径以逗号分隔
+func (c *loggerCore) outputPath() string {
+	if len(c.options.OutputPaths) > 0 {
+		return strings.Join(c.options.OutputPaths, ",")
+	}
+	if c.options.Writer != nil {
+		return "writer"
+	}
+	return c.options.OutputPath
+}
+
+// contextFields 从上下文中提取字段
+func (c *loggerCore) contextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+
+	fields := FieldsFromContext(ctx)
+	if identity := identityFields(ctx); len(identity) > 0 {
+		fields = append(identity, fields...)
+	}
+	for _, extractor := range c.options.ContextExtractors {
+		fields = append(fields[:len(fields):len(fields)], extractor(ctx)...)
+	}
+	if c.options.ContextErrField {
+		if err := ctx.Err(); err != nil {
+			fields = append(fields[:len(fields):len(fields)], Field{Key: "ctx_err", Value: err.Error()})
+		}
+	}
+	return fields
+}
+
+// identityFields 获取上下文中的操作名称、租户ID和用户ID字段，按此顺序排列
+func identityFields(ctx context.Context) []Field {
+	var fields []Field
+	if operation, ok := OperationFromContext(ctx); ok {
+		fields = append(fields, Field{Key: "operation", Value: operation})
+	}
+	if tenantID, ok := TenantFromContext(ctx); ok {
+		fields = append(fields, Field{Key: "tenant_id", Value: tenantID})
+	}
+	if userID, ok := UserFromContext(ctx); ok {
+		fields = append(fields, Field{Key: "user_id", Value: userID})
+	}
+	return fields
+}
+
+// mergeFields 按固定顺序合并字段：常量字段、日志实例上的字段、上下文字段（操作名称、租户ID和用户ID在最前）、本次调用的字段，
+// 开启去重时后出现的同名字段覆盖先出现的字段值，并保留其首次出现的位置。
+// sites为日志实例上各字段的添加位置，仅在开启字段追踪时使用，msg为未脱敏的日志消息，供条件堆栈判断使用。
+// 合并完成后调用日志钩子
+func (c *loggerCore) mergeFields(level LogLevel, msg string, loggerFields []Field, sites []string, ctx context.Context, callFields []Field) []Field {
+	return c.appendMergedFields(nil, level, msg, loggerFields, sites, ctx, callFields)
+}
+
+// appendMergedFields 与mergeFields相同，但复用dst的底层数组存放结果，供池化的日志记录使用
+func (c *loggerCore) appendMergedFields(dst []Field, level LogLevel, msg string, loggerFields []Field, sites []string, ctx context.Context, callFields []Field) []Field {
+	constFields := c.constFields
+	ctxFields := c.contextFields(ctx)
+	trace, traced := c.fieldTrace(loggerFields, sites, ctxFields, callFields)
+
+	fields := dst[:0]
+	if n := len(constFields) + len(loggerFields) + len(ctxFields) + len(callFields) + 2; cap(fields) < n {
+		fields = make([]Field, 0, n)
+	}
+	fields = append(fields, constFields...)
+	fields = append(fields, loggerFields...)
+	fields = append(fields, ctxFields...)
+	fields = append(fields, callFields...)
+	if c.options.DedupeFields {
+		fields = dedupeFields(fields)
+	}
+	if c.options.OmitNilFields {
+		fields = omitNilFields(fields)
+	}
+	fields = c.filterAllowedFields(fields)
+	fields = c.capFields(fields)
+	fields = hashFields(fields)
+	fields = c.marshalFields(fields)
+	fields = c.formatErrors(fields)
+	fields = c.formatDurations(fields)
+	fields = c.collapseStacks(fields)
+	fields = c.redactFields(fields)
+	fields = c.truncateFields(fields)
+	fields = c.checkReservedKeys(fields)
+
+	if c.options.NumericSeverity && c.jsonOutput {
+		fields = append(fields, Field{Key: "severity_code", Value: c.severityCode(level)})
+	}
+	if c.options.CallerFields {
+		fields = appendCallerFields(fields)
+	}
+	if level == WarnLevel && c.options.WarnStackDepth > 0 {
+		fields = append(fields, Field{Key: "stack", Value: c.stackValue(c.options.WarnStackDepth)})
+	}
+	if c.options.StackPredicate != nil && c.options.StackPredicate(level, msg, fields) {
+		fields = append(fields, Field{Key: "stacktrace", Value: c.stackValue(conditionalStackDepth)})
+	}
+	if level >= WarnLevel && c.options.RuntimeStats {
+		fields = append(fields, runtimeStatsFields()...)
+	}
+	if c.options.UptimeField {
+		fields = append(fields, c.formatDurations([]Field{{Key: "uptime", Value: c.uptime()}})...)
+	}
+	if c.options.GoroutineID {
+		fields = append(fields, Field{Key: "goid", Value: goroutineID()})
+	}
+	if c.options.Sequence {
+		fields = append(fields, Field{Key: "seq", Value: atomic.AddUint64(&c.seq, 1)})
+	}
+	if c.options.GlobalSequence {
+		fields = append(fields, Field{Key: "gseq", Value: globalSeq.Add(1)})
+	}
+	if traced {
+		fields = append(fields, trace)
+	}
+	c.runEntryHooks(ctx, level, msg, fields)
+	return fields
+}
+
+// runEntryHooks 使用合并后的字段调用日志钩子
+func (c *loggerCore) runEntryHooks(ctx context.Context, level LogLevel, msg string, fields []Field) {
+	if len(c.options.EntryHooks) == 0 {
+		return
+	}
+	msg = c.redactMessage(msg)
+	flat := flattenFields(fields)
+	for _, hook := range c.options.EntryHooks {
+		hook(ctx, level, msg, flat)
+	}
+}
+
+// syslogSeverityCodes 各级别对应的syslog数值级别
+var syslogSeverityCodes = map[LogLevel]int{
+	DebugLevel: 7,
+	InfoLevel:  6,
+	WarnLevel:  4,
+	ErrorLevel: 3,
+	FatalLevel: 2,
+	PanicLevel: 1,
+}
+
+// severityCode 获取级别对应的数值级别，优先使用WithSeverityCodes的配置
+func (c *loggerCore) severityCode(level LogLevel) int {
+	if code, ok := c.options.SeverityCodes[level]; ok {
+		return code
+	}
+	return syslogSeverityCodes[level]
+}
+
+// omitNilFields 去除值为nil的字段，包括值为nil指针、nil map等的字段，嵌套字段中的nil值同样被去除
+func omitNilFields(fields []Field) []Field {
+	kept := fields[:0]
+	for _, field := range fields {
+		if isNilValue(field.Value) {
+			continue
+		}
+		if group, ok := field.Value.(fieldGroup); ok {
+			field.Value = fieldGroup(omitNilFields(append(fieldGroup(nil), group...)))
+		}
+		kept = append(kept, field)
+	}
+	return kept
+}
+
+// isNilValue 判断值是否为nil或包含nil的指针、map、切片、函数、通道或接口
+func isNilValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// dedupeFields 去除同名字段，保留首次出现的位置和最后出现的值
+func dedupeFields(fields []Field) []Field {
+	index := make(map[string]int, len(fields))
+	deduped := fields[:0]
+	for _, field := range fields {
+		if i, ok := index[field.Key]; ok {
+			deduped[i].Value = field.Value
+			continue
+		}
+		index[field.Key] = len(deduped)
+		deduped = append(deduped, field)
+	}
+	return deduped
+}
+
+// formatErrors 格式化值为error的字段，包括嵌套字段
+func (c *loggerCore) formatErrors(fields []Field) []Field {
+	for i, field := range fields {
+		switch value := field.Value.(type) {
+		case error:
+			fields[i].Value = c.formatError(value)
+		case fieldGroup:
+			group := make(fieldGroup, len(value))
+			copy(group, value)
+			fields[i].Value = fieldGroup(c.formatErrors(group))
+		}
+	}
+	return fields
+}
+
+// marshalFields 使用按类型注册的转换函数转换字段值，包括嵌套字段
+func (c *loggerCore) marshalFields(fields []Field) []Field {
+	if len(c.options.Marshalers) == 0 {
+		return fields
+	}
+	for i, field := range fields {
+		if group, ok := field.Value.(fieldGroup); ok {
+			copied := make(fieldGroup, len(group))
+			copy(copied, group)
+			fields[i].Value = fieldGroup(c.marshalFields(copied))
+			continue
+		}
+		if field.Value == nil {
+			continue
+		}
+		if marshal, ok := c.options.Marshalers[reflect.TypeOf(field.Value)]; ok {
+			fields[i].Value = marshal(field.Value)
+		}
+	}
+	return fields
+}
+
+// formatError 格式化错误：errors.Join等组合错误展开为每个子错误一项的数组，
+// 其他错误使用配置的ErrorFormatter，未配置时保持原样
+func (c *loggerCore) formatError(err error) interface{} {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs := joined.Unwrap()
+		values := make([]interface{}, 0, len(errs))
+		for _, e := range errs {
+			value := c.formatError(e)
+			if e, ok := value.(error); ok {
+				value = e.Error()
+			}
+			values = append(values, value)
+		}
+		return values
+	}
+
+	if c.options.ErrorFormatter != nil {
+		return c.options.ErrorFormatter(err)
+	}
+	return err
+}
+
+// formatDurations 将time.Duration字段转换为DurationUnit单位下的数值并为键名添加单位后缀，包括嵌套字段
+func (c *loggerCore) formatDurations(fields []Field) []Field {
+	unit := c.options.DurationUnit
+	if unit <= 0 {
+		return fields
+	}
+
+	for i, field := range fields {
+		switch value := field.Value.(type) {
+		case time.Duration:
+			fields[i].Key = field.Key + durationSuffix(unit)
+			fields[i].Value = durationValue(value, unit)
+		case fieldGroup:
+			group := make(fieldGroup, len(value))
+			copy(group, value)
+			fields[i].Value = fieldGroup(c.formatDurations(group))
+		}
+	}
+	return fields
+}
+
+// durationValue 将时长转换为指定单位下的数值，整除时为整数，否则为浮点数
+func durationValue(d, unit time.Duration) interface{} {
+	if d%unit == 0 {
+		return int64(d / unit)
+	}
+	return float64(d) / float64(unit)
+}
+
+// durationSuffix 获取时长单位对应的键名后缀
+func durationSuffix(unit time.Duration) string {
+	switch unit {
+	case time.Nanosecond:
+		return "_ns"
+	case time.Microsecond:
+		return "_us"
+	case time.Millisecond:
+		return "_ms"
+	case time.Second:
+		return "_s"
+	case time.Minute:
+		return "_m"
+	case time.Hour:
+		return "_h"
+	default:
+		return ""
+	}
+}
+
+// redactMessage 按配置的脱敏规则替换日志消息中的敏感内容
+func (c *loggerCore) redactMessage(msg string) string {
+	for _, rule := range c.options.RedactPatterns {
+		msg = rule.Pattern.ReplaceAllString(msg, rule.Replacement)
+	}
+	return msg
+}
+
+// redactFields 按配置的脱敏规则替换字符串字段值中的敏感内容，包括嵌套字段
+func (c *loggerCore) redactFields(fields []Field) []Field {
+	if len(c.options.RedactPatterns) == 0 {
+		return fields
+	}
+
+	for i, field := range fields {
+		switch value := field.Value.(type) {
+		case string:
+			fields[i].Value = c.redactMessage(value)
+		case fieldGroup:
+			group := make(fieldGroup, len(value))
+			copy(group, value)
+			fields[i].Value = fieldGroup(c.redactFields(group))
+		}
+	}
+	return fields
+}
+
+// fieldsTruncatedKey 超出MaxFields时记录丢弃字段个数的字段键名
+const fieldsTruncatedKey = "fields_truncated"
+
+// capFields 字段数超过MaxFields时保留前MaxFields个字段，并添加记录丢弃个数的fields_truncated字段，分组字段计为一个
+func (c *loggerCore) capFields(fields []Field) []Field {
+	limit := c.options.MaxFields
+	if limit <= 0 || len(fields) <= limit {
+		return fields
+	}
+	dropped := len(fields) - limit
+	return append(fields[:limit], Field{Key: fieldsTruncatedKey, Value: dropped})
+}
+
+// truncateFields 将超过MaxFieldBytes的字段值截断，包括嵌套字段
+func (c *loggerCore) truncateFields(fields []Field) []Field {
+	limit := c.options.MaxFieldBytes
+	if limit <= 0 {
+		return fields
+	}
+
+	for i, field := range fields {
+		if group, ok := field.Value.(fieldGroup); ok {
+			copied := make(fieldGroup, len(group))
+			copy(copied, group)
+			fields[i].Value = fieldGroup(c.truncateFields(copied))
+			continue
+		}
+		fields[i].Value = truncateValue(field.Value, limit)
+	}
+	return fields
+}
+
+// truncateValue 将渲染后超过max字节的值截断为字符串，未超过时保持原值
+func truncateValue(value interface{}, limit int) interface{} {
+	var s string
+	switch v := value.(type) {
+	case nil, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return value
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if len(s) <= limit {
+		return value
+	}
+
+	// 避免截断在多字节字符中间
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + fmt.Sprintf("...(%d bytes truncated)", len(s)-cut)
+}
+
+// reservedKeyPolicy 获取生效的保留键冲突策略，返回空字符串表示不检查
+func (c *loggerCore) reservedKeyPolicy() ReservedKeyPolicy {
+	if c.options.ReservedKeyPolicy != "" {
+		return c.options.ReservedKeyPolicy
+	}
+	if c.options.Development {
+		return ReservedKeyWarn
+	}
+	return ""
+}
+
+// fieldAllowlist 将允许输出的字段键转换为集合，未设置时返回nil
+func fieldAllowlist(keys []string) map[string]bool {
+	if len(keys) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		allowed[key] = true
+	}
+	return allowed
+}
+
+// filterAllowedFields 去除键不在允许列表中的字段，嵌套字段按顶层键判断。
+// 开发模式下每个被丢弃的键只警告一次
+func (c *loggerCore) filterAllowedFields(fields []Field) []Field {
+	if c.allowedKeys == nil {
+		return fields
+	}
+
+	kept := fields[:0]
+	for _, field := range fields {
+		if c.allowedKeys[field.Key] {
+			kept = append(kept, field)
+			continue
+		}
+		if c.options.Development {
+			if _, warned := c.droppedKeys.LoadOrStore(field.Key, true); !warned {
+				internalWarnf("field key %q is not in the allowlist and was dropped", field.Key)
+			}
+		}
+	}
+	return kept
+}
+
+// checkReservedKeys 按配置的策略处理与保留键冲突的字段
+func (c *loggerCore) checkReservedKeys(fields []Field) []Field {
+	policy := c.reservedKeyPolicy()
+	if policy == "" || policy == ReservedKeyOverwrite {
+		return fields
+	}
+
+	for i, field := range fields {
+		if !reservedKeys[field.Key] {
+			continue
+		}
+		switch policy {
+		case ReservedKeyRename:
+			fields[i].Key = "fields." + field.Key
+		case ReservedKeyWarn:
+			if _, warned := c.warnedKeys.LoadOrStore(field.Key, true); !warned {
+				internalWarnf("field key %q collides with a reserved key", field.Key)
+			}
+		}
+	}
+	return fields
+}
+
+// 日志门面自身的包路径，查找调用位置时跳过这些包中的栈帧
+var (
+	loggerPkgPath = reflect.TypeOf(loggerCore{}).PkgPath()
+	facadePkgPath = strings.TrimSuffix(loggerPkgPath, "/pkg/logger")
+)
+
+// appendCallerFields 添加日志门面之外第一个调用者的文件、行号和函数名字段
+func appendCallerFields(fields []Field) []Field {
+	name, file, line, ok := facadeCaller()
+	if !ok {
+		return fields
+	}
+	return append(fields,
+		Field{Key: "caller.file", Value: trimCallerPath(file)},
+		Field{Key: "caller.line", Value: line},
+		Field{Key: "caller.func", Value: name},
+	)
+}
+
+// facadeCaller 获取调用日志门面的第一个外部调用方的函数名、文件和行号
+func facadeCaller() (string, string, int, bool) {
+	for skip := 2; ; skip++ {
+		pc, file, line, ok := runtime.Caller(skip)
+		if !ok {
+			return "", "", 0, false
+		}
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			continue
+		}
+		if name := fn.Name(); !isFacadeFunc(name) {
+			return name, file, line, true
+		}
+	}
+}
+
+// callerStack 获取日志门面之外最近的depth个栈帧，每个栈帧格式为 函数名 (目录/文件:行号)
+func callerStack(depth int) []string {
+	// 多取一些栈帧，跳过日志门面自身的栈帧后仍能得到depth个
+	pcs := make([]uintptr, depth+16)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make([]string, 0, depth)
+	for len(stack) < depth {
+		frame, more := frames.Next()
+		if !isFacadeFunc(frame.Function) {
+			stack = append(stack, frame.Function+" ("+trimCallerPath(frame.File)+":"+strconv.Itoa(frame.Line)+")")
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// conditionalStackDepth WithConditionalStacktrace添加的stacktrace字段包含的栈帧数
+const conditionalStackDepth = 32
+
+// stackValue 获取调用方的depth个栈帧，开启单行堆栈时合并为一行
+func (c *loggerCore) stackValue(depth int) interface{} {
+	stack := callerStack(depth)
+	if c.options.SingleLineStack {
+		return strings.Join(stack, stackFrameSeparator)
+	}
+	return stack
+}
+
+// stackFrameSeparator 单行堆栈中栈帧之间的分隔符
+const stackFrameSeparator = " | "
+
+// collapseStacks 开启单行堆栈时，将键为stack或stacktrace的字符串字段（如debug.Stack()的结果）合并为一行
+func (c *loggerCore) collapseStacks(fields []Field) []Field {
+	if !c.options.SingleLineStack {
+		return fields
+	}
+	for i, field := range fields {
+		if field.Key != "stack" && field.Key != "stacktrace" {
+			continue
+		}
+		switch value := field.Value.(type) {
+		case string:
+			fields[i].Value = singleLineStack(value)
+		case []byte:
+			fields[i].Value = singleLineStack(string(value))
+		case []string:
+			fields[i].Value = strings.Join(value, stackFrameSeparator)
+		}
+	}
+	return fields
+}
+
+// singleLineStack 将多行堆栈合并为一行：以制表符缩进的文件位置行与上一行的函数名以\t连接，栈帧之间以 | 分隔
+func singleLineStack(stack string) string {
+	frames := make([]string, 0, 16)
+	for _, line := range strings.Split(strings.TrimSpace(stack), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "\t") && len(frames) > 0 {
+			frames[len(frames)-1] += "\t" + trimmed
+			continue
+		}
+		frames = append(frames, trimmed)
+	}
+	return strings.Join(frames, stackFrameSeparator)
+}
+
+// isFacadeFunc 判断函数是否属于日志门面的根包或logger包
+func isFacadeFunc(name string) bool {
+	return strings.HasPrefix(name, loggerPkgPath+".") || strings.HasPrefix(name, facadePkgPath+".")
+}
+
+// trimCallerPath 保留文件路径的最后一级目录和文件名，与zap的ShortCallerEncoder一致
+func trimCallerPath(file string) string {
+	idx := strings.LastIndexByte(file, '/')
+	if idx == -1 {
+		return file
+	}
+	if idx = strings.LastIndexByte(file[:idx], '/'); idx == -1 {
+		return file
+	}
+	return file[idx+1:]
+}
+
+// runtimeStatsFields 获取当前的goroutine数量、堆内存分配字节数和GC次数字段。
+// runtime.ReadMemStats会短暂暂停程序，只在警告及以上级别调用
+func runtimeStatsFields() []Field {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return []Field{
+		{Key: "goroutines", Value: runtime.NumGoroutine()},
+		{Key: "heap_alloc", Value: stats.HeapAlloc},
+		{Key: "num_gc", Value: stats.NumGC},
+	}
+}
+
+// goroutineID 从运行时堆栈头部 "goroutine 123 [running]:" 中解析当前goroutine的ID
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	header := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// maxCrashStackSize 崩溃转储中goroutine堆栈的最大字节数
+const maxCrashStackSize = 64 * 1024 * 1024
+
+// crashDumpEnabled 判断是否配置了崩溃转储文件
+func (c *loggerCore) crashDumpEnabled() bool {
+	return c.options.CrashDumpPath != ""
+}
+
+// crashEntry 使用日志配置的格式编码恐慌级日志记录，供不使用门面编码器输出的适配器写入崩溃转储
+func (c *loggerCore) crashEntry(name, msg string, fields []Field) string {
+	entry := newEntry(c.now(), PanicLevel, name, msg, fields)
+	line, err := newEncoder(c.options).Encode(entry)
+	if err != nil {
+		return entry.Time.Format(DefaultTextTimeLayout) + " [" + PanicLevel.String() + "] [" + name + "] " + msg
+	}
+	return strings.TrimSuffix(string(line), "\n")
+}
+
+// writeCrashDump 将恐慌级日志记录和所有goroutine的堆栈同步追加到崩溃转储文件，
+// 在panic传播之前调用，即使主输出带缓冲或已丢失，崩溃现场也能保留
+func (c *loggerCore) writeCrashDump(line string) {
+	path := c.options.CrashDumpPath
+	if path == "" {
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(line)
+	b.WriteString("\n\ngoroutine dump:\n\n")
+	b.Write(goroutineDump())
+	b.WriteString("\n")
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		internalWarnf("failed to write crash dump %q: %v", path, err)
+		return
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		internalWarnf("failed to write crash dump %q: %v", path, err)
+		return
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(b.String()); err != nil {
+		internalWarnf("failed to write crash dump %q: %v", path, err)
+		return
+	}
+	if err := file.Sync(); err != nil {
+		internalWarnf("failed to sync crash dump %q: %v", path, err)
+	}
+}
+
+// goroutineDump 获取所有goroutine的堆栈，缓冲区不足时加倍，最多maxCrashStackSize字节
+func goroutineDump() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxCrashStackSize {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
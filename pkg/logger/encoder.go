@@ -0,0 +1,581 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 默认的时间格式
+const (
+	// DefaultTextTimeLayout 文本格式使用的时间格式
+	DefaultTextTimeLayout = "2006-01-02 15:04:05.000"
+	// DefaultJSONTimeLayout JSON和logfmt格式使用的时间格式
+	DefaultJSONTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// Encoder 日志编码器接口，将一条日志记录编码为一行输出（包含结尾换行符）
+type Encoder interface {
+	// Encode 编码日志记录，日志记录可能被复用，Encode返回后不应继续持有entry.Fields
+	Encode(entry Entry) ([]byte, error)
+}
+
+// NewEncoder 根据格式名称创建编码器，未知格式使用文本编码器
+func NewEncoder(format string) Encoder {
+	switch format {
+	case "json":
+		return &JSONEncoder{}
+	case "logfmt":
+		return &LogfmtEncoder{}
+	case "cloudevents":
+		return &CloudEventsEncoder{}
+	case "gcp":
+		return &GCPEncoder{}
+	default:
+		return &TextEncoder{}
+	}
+}
+
+// isJSONFormat 判断格式是否输出JSON，json、gcp和cloudevents格式都输出JSON
+func isJSONFormat(format string) bool {
+	switch format {
+	case "json", "gcp", "cloudevents":
+		return true
+	default:
+		return false
+	}
+}
+
+// newEncoder 根据日志配置创建编码器
+func newEncoder(options *LoggerOptions) Encoder {
+	encoder := NewEncoder(options.Format)
+	switch e := encoder.(type) {
+	case *TextEncoder:
+		e.Color = options.Color
+		e.LevelColors = options.LevelColors
+		e.CompositesAsJSON = options.CompositesAsJSON
+		e.SanitizeNewlines = options.SanitizeNewlines
+		e.FloatPrecision = options.FloatPrecision
+		e.BytesEncoding = options.BytesEncoding
+		e.TimeLayout = options.TimeFormat
+	case *JSONEncoder:
+		e.Keys = keysFromOptions(options)
+		e.LevelEncoder = options.LevelEncoder
+		e.FieldsKey = nestedFieldsKey(options)
+	case *LogfmtEncoder:
+		e.Keys = keysFromOptions(options)
+		e.LevelEncoder = options.LevelEncoder
+		e.CompositesAsJSON = options.CompositesAsJSON
+		e.FloatPrecision = options.FloatPrecision
+		e.BytesEncoding = options.BytesEncoding
+		e.TimeLayout = options.TimeFormat
+	case *GCPEncoder:
+		e.ProjectID = options.GCPProjectID
+	case *CloudEventsEncoder:
+		e.Keys = keysFromOptions(options)
+		e.LevelEncoder = options.LevelEncoder
+		e.FieldsKey = nestedFieldsKey(options)
+		e.Source = options.CloudEventsSource
+		e.Type = options.CloudEventsType
+	}
+	return encoder
+}
+
+// textTimeLayout 获取文本格式使用的时间格式，未配置时使用DefaultTextTimeLayout
+func textTimeLayout(options *LoggerOptions) string {
+	if options.TimeFormat != "" {
+		return options.TimeFormat
+	}
+	return DefaultTextTimeLayout
+}
+
+// EncoderKeys 结构化输出中时间、级别、名称和消息使用的键名，为空时使用默认值
+type EncoderKeys struct {
+	TimeKey    string // 默认为time
+	LevelKey   string // 默认为level
+	NameKey    string // 默认为logger
+	MessageKey string // 默认为msg
+}
+
+// keysFromOptions 从日志配置中获取键名
+func keysFromOptions(options *LoggerOptions) EncoderKeys {
+	return EncoderKeys{
+		TimeKey:    options.TimeKey,
+		LevelKey:   options.LevelKey,
+		NameKey:    options.NameKey,
+		MessageKey: options.MessageKey,
+	}
+}
+
+// withDefaults 为空的键名填充默认值
+func (k EncoderKeys) withDefaults() EncoderKeys {
+	if k.TimeKey == "" {
+		k.TimeKey = "time"
+	}
+	if k.LevelKey == "" {
+		k.LevelKey = "level"
+	}
+	if k.NameKey == "" {
+		k.NameKey = "logger"
+	}
+	if k.MessageKey == "" {
+		k.MessageKey = "msg"
+	}
+	return k
+}
+
+// nestedFieldsKey 获取嵌套自定义字段使用的键名，平铺字段时返回空字符串
+func nestedFieldsKey(options *LoggerOptions) string {
+	if !options.NestFields {
+		return ""
+	}
+	if options.FieldsKey == "" {
+		return "fields"
+	}
+	return options.FieldsKey
+}
+
+// encodeLevel 使用级别编码函数编码日志级别，未设置时使用级别名称
+func encodeLevel(encoder LevelEncoder, level LogLevel) interface{} {
+	if encoder == nil {
+		return level.String()
+	}
+	return encoder(level)
+}
+
+// formatValue 将字段值格式化为文本
+func formatValue(value interface{}) string {
+	return fmt.Sprintf("%v", value)
+}
+
+// formatTextValue 将字段值格式化为文本，compositesAsJSON为true时切片、数组和map输出为紧凑JSON，
+// floatPrecision不为nil时浮点数按固定小数位数输出，[]byte按bytesEncoding编码，time.Time按timeLayout格式化
+func formatTextValue(value interface{}, compositesAsJSON bool, floatPrecision *int, bytesEncoding, timeLayout string) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(timeLayout)
+	case []byte:
+		return encodeBytes(v, bytesEncoding)
+	case float64:
+		if floatPrecision != nil {
+			return strconv.FormatFloat(v, 'f', *floatPrecision, 64)
+		}
+	case float32:
+		if floatPrecision != nil {
+			return strconv.FormatFloat(float64(v), 'f', *floatPrecision, 32)
+		}
+	}
+	if compositesAsJSON && isComposite(value) {
+		return string(marshalJSONValue(value))
+	}
+	return formatValue(value)
+}
+
+// 二进制字段值的编码方式
+const (
+	// BytesEncodingBase64 标准base64编码，与encoding/json对[]byte的编码一致
+	BytesEncodingBase64 = "base64"
+	// BytesEncodingHex 小写十六进制编码
+	BytesEncodingHex = "hex"
+)
+
+// encodeBytes 按编码方式将二进制值编码为文本，未知的编码方式使用base64
+func encodeBytes(data []byte, encoding string) string {
+	if encoding == BytesEncodingHex {
+		return hex.EncodeToString(data)
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// isComposite 判断值是否为切片（[]byte除外）、数组或map
+func isComposite(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+	switch value.(type) {
+	case []byte, fmt.Stringer, error:
+		return false
+	}
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+// TextEncoder 文本编码器，输出形如 "时间 [级别] [名称] 消息 key=value"
+type TextEncoder struct {
+	// TimeLayout 时间格式，为空时使用DefaultTextTimeLayout
+	TimeLayout string
+	// Color 是否使用ANSI颜色输出级别
+	Color bool
+	// LevelColors 按级别覆盖默认的ANSI颜色，未设置的级别使用默认颜色
+	LevelColors LevelColorMap
+	// CompositesAsJSON 是否将切片、数组和map字段值输出为紧凑JSON
+	CompositesAsJSON bool
+	// SanitizeNewlines 是否转义消息、字段键和字段值中的换行符，防止伪造日志行
+	SanitizeNewlines bool
+	// FloatPrecision 浮点数字段保留的小数位数，nil表示使用默认格式
+	FloatPrecision *int
+	// BytesEncoding []byte字段值的编码方式，为空时使用BytesEncodingBase64
+	BytesEncoding string
+}
+
+// newlineEscaper 将换行符转义为字面的\r和\n
+var newlineEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+// sanitize 按配置转义换行符
+func (e *TextEncoder) sanitize(s string) string {
+	if !e.SanitizeNewlines {
+		return s
+	}
+	return newlineEscaper.Replace(s)
+}
+
+// Encode 编码日志记录
+func (e *TextEncoder) Encode(entry Entry) ([]byte, error) {
+	layout := e.TimeLayout
+	if layout == "" {
+		layout = DefaultTextTimeLayout
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	buf.WriteString(entry.Time.Format(layout))
+	buf.WriteString(" [")
+	if e.Color {
+		buf.WriteString(e.levelColor(entry.Level))
+		buf.WriteString(entry.Level.String())
+		buf.WriteString(colorReset)
+	} else {
+		buf.WriteString(entry.Level.String())
+	}
+	buf.WriteString("] [")
+	buf.WriteString(entry.Name)
+	buf.WriteString("] ")
+	buf.WriteString(e.sanitize(entry.Message))
+
+	for _, field := range flattenFields(entry.Fields) {
+		buf.WriteByte(' ')
+		buf.WriteString(e.sanitize(field.Key))
+		buf.WriteByte('=')
+		buf.WriteString(e.sanitize(formatTextValue(field.Value, e.CompositesAsJSON, e.FloatPrecision, e.BytesEncoding, layout)))
+	}
+
+	buf.WriteByte('\n')
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// colorReset ANSI颜色重置序列
+const colorReset = "\x1b[0m"
+
+// levelColor 获取日志级别对应的ANSI颜色序列，优先使用LevelColors中的颜色，
+// 颜色可以是完整的转义序列（如"\x1b[35m"）或SGR参数（如"35"、"1;35"）
+func (e *TextEncoder) levelColor(level LogLevel) string {
+	if color, ok := e.LevelColors[level]; ok && color != "" {
+		if strings.HasPrefix(color, "\x1b[") {
+			return color
+		}
+		return "\x1b[" + color + "m"
+	}
+	return defaultLevelColor(level)
+}
+
+// defaultLevelColor 获取日志级别默认的ANSI颜色序列
+func defaultLevelColor(level LogLevel) string {
+	switch level {
+	case DebugLevel:
+		return "\x1b[36m"
+	case InfoLevel:
+		return "\x1b[32m"
+	case WarnLevel:
+		return "\x1b[33m"
+	default:
+		return "\x1b[31m"
+	}
+}
+
+// JSONEncoder JSON编码器，每条日志输出为一行JSON对象
+type JSONEncoder struct {
+	// TimeLayout 时间格式，为空时使用DefaultJSONTimeLayout
+	TimeLayout string
+	// Keys 时间、级别、名称和消息使用的键名
+	Keys EncoderKeys
+	// LevelEncoder 级别编码函数，为空时使用级别名称
+	LevelEncoder LevelEncoder
+	// FieldsKey 不为空时自定义字段嵌套在该键下，为空时与消息平铺在顶层
+	FieldsKey string
+}
+
+// Encode 编码日志记录
+func (e *JSONEncoder) Encode(entry Entry) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	e.writeObject(buf, entry)
+	buf.WriteByte('\n')
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// writeObject 将日志记录写入为JSON对象，不包含结尾换行符
+func (e *JSONEncoder) writeObject(buf *bytes.Buffer, entry Entry) {
+	layout := e.TimeLayout
+	if layout == "" {
+		layout = DefaultJSONTimeLayout
+	}
+
+	keys := e.Keys.withDefaults()
+
+	buf.WriteByte('{')
+	writeJSONPair(buf, keys.TimeKey, entry.Time.Format(layout), true)
+	writeJSONPair(buf, keys.LevelKey, encodeLevel(e.LevelEncoder, entry.Level), false)
+	writeJSONPair(buf, keys.NameKey, entry.Name, false)
+	writeJSONPair(buf, keys.MessageKey, entry.Message, false)
+
+	if e.FieldsKey != "" {
+		buf.WriteByte(',')
+		writeJSONString(buf, e.FieldsKey)
+		buf.WriteString(":{")
+		for i, field := range entry.Fields {
+			writeJSONPair(buf, field.Key, field.Value, i == 0)
+		}
+		buf.WriteString("}}")
+		return
+	}
+
+	for _, field := range entry.Fields {
+		writeJSONPair(buf, field.Key, field.Value, false)
+	}
+
+	buf.WriteByte('}')
+}
+
+// DefaultCloudEventsType CloudEvents编码器默认的事件类型
+const DefaultCloudEventsType = "com.landclogface.log"
+
+// CloudEventsEncoder CloudEvents v1.0编码器，每条日志输出为一行结构化模式的CloudEvents JSON，
+// data为JSON编码器输出的日志对象，便于日志与应用事件经过同一事件总线
+type CloudEventsEncoder struct {
+	JSONEncoder
+	// Source 事件来源，为空时使用日志名称
+	Source string
+	// Type 事件类型，为空时使用DefaultCloudEventsType
+	Type string
+}
+
+// Encode 编码日志记录
+func (e *CloudEventsEncoder) Encode(entry Entry) ([]byte, error) {
+	source := e.Source
+	if source == "" {
+		source = entry.Name
+	}
+	if source == "" {
+		source = "landclogface"
+	}
+	eventType := e.Type
+	if eventType == "" {
+		eventType = DefaultCloudEventsType
+	}
+
+	id, err := newEventID()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	buf.WriteByte('{')
+	writeJSONPair(buf, "specversion", "1.0", true)
+	writeJSONPair(buf, "id", id, false)
+	writeJSONPair(buf, "source", source, false)
+	writeJSONPair(buf, "type", eventType, false)
+	writeJSONPair(buf, "time", entry.Time.Format(time.RFC3339Nano), false)
+	writeJSONPair(buf, "datacontenttype", "application/json", false)
+	buf.WriteString(`,"data":`)
+	e.writeObject(buf, entry)
+	buf.WriteString("}\n")
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// GCP Cloud Logging结构化日志中的特殊键
+const (
+	// GCPTraceKey 追踪ID的键，值为projects/项目ID/traces/追踪ID
+	GCPTraceKey = "logging.googleapis.com/trace"
+	// GCPSpanIDKey 跨度ID的键
+	GCPSpanIDKey = "logging.googleapis.com/spanId"
+	// GCPTraceSampledKey 追踪是否被采样的键
+	GCPTraceSampledKey = "logging.googleapis.com/trace_sampled"
+)
+
+// GCPEncoder GCP Cloud Logging编码器，每条日志输出为一行Cloud Logging可识别的结构化JSON，
+// 级别输出为severity，trace_id、span_id和trace_sampled字段映射为Cloud Logging的追踪键，使日志与追踪关联
+type GCPEncoder struct {
+	// ProjectID GCP项目ID，不为空时追踪ID输出为projects/ProjectID/traces/trace_id的完整形式
+	ProjectID string
+}
+
+// Encode 编码日志记录
+func (e *GCPEncoder) Encode(entry Entry) ([]byte, error) {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	buf.WriteByte('{')
+	writeJSONPair(buf, "time", entry.Time.Format(time.RFC3339Nano), true)
+	writeJSONPair(buf, "severity", gcpSeverity(entry.Level), false)
+	writeJSONPair(buf, "logger", entry.Name, false)
+	writeJSONPair(buf, "message", entry.Message, false)
+
+	for _, field := range entry.Fields {
+		switch field.Key {
+		case "trace_id":
+			writeJSONPair(buf, GCPTraceKey, e.traceName(field.Value), false)
+		case "span_id":
+			writeJSONPair(buf, GCPSpanIDKey, formatValue(field.Value), false)
+		case "trace_sampled":
+			writeJSONPair(buf, GCPTraceSampledKey, field.Value, false)
+		default:
+			writeJSONPair(buf, field.Key, field.Value, false)
+		}
+	}
+
+	buf.WriteString("}\n")
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// traceName 生成Cloud Logging的追踪名称，未设置项目ID或已是完整形式时原样输出
+func (e *GCPEncoder) traceName(traceID interface{}) string {
+	id := formatValue(traceID)
+	if e.ProjectID == "" || strings.HasPrefix(id, "projects/") {
+		return id
+	}
+	return "projects/" + e.ProjectID + "/traces/" + id
+}
+
+// gcpSeverity 获取日志级别对应的Cloud Logging severity
+func gcpSeverity(level LogLevel) string {
+	switch level {
+	case DebugLevel:
+		return "DEBUG"
+	case InfoLevel:
+		return "INFO"
+	case WarnLevel:
+		return "WARNING"
+	case ErrorLevel:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
+
+// newEventID 生成随机的事件ID
+func newEventID() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id[:]), nil
+}
+
+// writeJSONPair 写入一个JSON键值对
+func writeJSONPair(buf *bytes.Buffer, key string, value interface{}, first bool) {
+	if !first {
+		buf.WriteByte(',')
+	}
+	writeJSONString(buf, key)
+	buf.WriteByte(':')
+	writeJSONValue(buf, value)
+}
+
+// marshalJSONValue 将值编码为JSON，无法编码的值退化为字符串
+func marshalJSONValue(value interface{}) []byte {
+	value = jsonValue(value)
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(formatValue(value))
+	}
+	return data
+}
+
+// jsonValue 将error和fmt.Stringer转换为字符串，避免通过反射编码其内部结构，
+// 自身实现了json.Marshaler的类型（如time.Time）保持不变
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Marshaler:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return value
+	}
+}
+
+// LogfmtEncoder logfmt编码器，输出形如 "time=... level=INFO logger=app msg=hello key=value"
+type LogfmtEncoder struct {
+	// TimeLayout 时间格式，为空时使用DefaultJSONTimeLayout
+	TimeLayout string
+	// Keys 时间、级别、名称和消息使用的键名
+	Keys EncoderKeys
+	// LevelEncoder 级别编码函数，为空时使用级别名称
+	LevelEncoder LevelEncoder
+	// CompositesAsJSON 是否将切片、数组和map字段值输出为紧凑JSON
+	CompositesAsJSON bool
+	// FloatPrecision 浮点数字段保留的小数位数，nil表示使用默认格式
+	FloatPrecision *int
+	// BytesEncoding []byte字段值的编码方式，为空时使用BytesEncodingBase64
+	BytesEncoding string
+}
+
+// Encode 编码日志记录
+func (e *LogfmtEncoder) Encode(entry Entry) ([]byte, error) {
+	layout := e.TimeLayout
+	if layout == "" {
+		layout = DefaultJSONTimeLayout
+	}
+
+	keys := e.Keys.withDefaults()
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+	writeLogfmtPair(buf, keys.TimeKey, entry.Time.Format(layout), true)
+	writeLogfmtPair(buf, keys.LevelKey, formatValue(encodeLevel(e.LevelEncoder, entry.Level)), false)
+	writeLogfmtPair(buf, keys.NameKey, entry.Name, false)
+	writeLogfmtPair(buf, keys.MessageKey, entry.Message, false)
+
+	for _, field := range flattenFields(entry.Fields) {
+		writeLogfmtPair(buf, field.Key, formatTextValue(field.Value, e.CompositesAsJSON, e.FloatPrecision, e.BytesEncoding, layout), false)
+	}
+
+	buf.WriteByte('\n')
+	return bytes.Clone(buf.Bytes()), nil
+}
+
+// writeLogfmtPair 写入一个logfmt键值对，键中的换行符被转义，必要时为值加引号
+func writeLogfmtPair(buf *bytes.Buffer, key, value string, first bool) {
+	if !first {
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(newlineEscaper.Replace(key))
+	buf.WriteByte('=')
+	if needsLogfmtQuote(value) {
+		buf.WriteString(strconv.Quote(value))
+	} else {
+		buf.WriteString(value)
+	}
+}
+
+// needsLogfmtQuote 判断logfmt值是否需要加引号
+func needsLogfmtQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	return strings.ContainsAny(value, " =\"\t\r\n")
+}
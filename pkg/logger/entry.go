@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry 一条待编码的日志记录，由各适配器共享
+type Entry struct {
+	Time    time.Time
+	Level   LogLevel
+	Name    string
+	Message string
+	Fields  []Field
+}
+
+// newEntry 创建日志记录
+func newEntry(t time.Time, level LogLevel, name, msg string, fields []Field) Entry {
+	return Entry{
+		Time:    t,
+		Level:   level,
+		Name:    name,
+		Message: msg,
+		Fields:  fields,
+	}
+}
+
+// maxPooledFields 放回池中的日志记录字段缓冲区的最大容量
+const maxPooledFields = 256
+
+// entryPool 日志记录池，复用合并字段使用的缓冲区
+var entryPool = sync.Pool{
+	New: func() interface{} { return &Entry{Fields: make([]Field, 0, 16)} },
+}
+
+// getEntry 从池中获取日志记录，Fields为空但保留已有容量
+func getEntry() *Entry {
+	entry := entryPool.Get().(*Entry)
+	entry.Fields = entry.Fields[:0]
+	return entry
+}
+
+// putEntry 清除字段值的引用后将日志记录放回池中，字段缓冲区过大的记录直接丢弃
+func putEntry(entry *Entry) {
+	if cap(entry.Fields) > maxPooledFields {
+		return
+	}
+	clear(entry.Fields)
+	*entry = Entry{Fields: entry.Fields[:0]}
+	entryPool.Put(entry)
+}
+
+// Clock 时钟接口，用于获取日志时间，测试中可注入固定时钟
+type Clock interface {
+	// Now 获取当前时间
+	Now() time.Time
+}
+
+// systemClock 使用time.Now的系统时钟
+type systemClock struct{}
+
+// Now 获取当前时间
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,219 @@
+package logger
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// fieldGroup 一组嵌套字段，JSON输出为嵌套对象，文本输出为点分隔的键
+type fieldGroup []Field
+
+// Group 创建嵌套字段，例如 Group("http", Field{Key: "method", Value: "GET"})
+// 在JSON中输出为 {"http":{"method":"GET"}}，在文本中输出为 http.method=GET
+func Group(key string, fields ...Field) Field {
+	return Field{Key: key, Value: fieldGroup(fields)}
+}
+
+// hashedValueLength 哈希字段值保留的十六进制字符数
+const hashedValueLength = 8
+
+// hashedValue 输出时替换为哈希的字段值，哈希在日志实际输出时才计算
+type hashedValue struct {
+	value interface{}
+}
+
+// String 返回值的哈希，使被限流统计等直接格式化字段值的地方也不会输出原值
+func (h hashedValue) String() string {
+	return hashValue(h.value)
+}
+
+// Hashed 创建以稳定哈希（sha256的前8个十六进制字符）代替原值输出的字段，用于邮箱、IP等标识的假名化，
+// 相同的值总是得到相同的哈希，日志仍然可以按该字段分组。哈希只在日志实际输出时计算
+func Hashed(key string, value interface{}) Field {
+	return Field{Key: key, Value: hashedValue{value: value}}
+}
+
+// hashValue 计算值的哈希，字符串和[]byte直接计算，其他类型先按%v格式化
+func hashValue(value interface{}) string {
+	var data []byte
+	switch v := value.(type) {
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		data = []byte(formatValue(v))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:hashedValueLength]
+}
+
+// hashFields 将Hashed创建的字段值替换为哈希，包括嵌套字段
+func hashFields(fields []Field) []Field {
+	for i, field := range fields {
+		switch value := field.Value.(type) {
+		case hashedValue:
+			fields[i].Value = value.String()
+		case fieldGroup:
+			group := make(fieldGroup, len(value))
+			copy(group, value)
+			fields[i].Value = fieldGroup(hashFields(group))
+		}
+	}
+	return fields
+}
+
+// MarshalJSON 按字段顺序编码为JSON对象
+func (g fieldGroup) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range g {
+		writeJSONPair(&buf, field.Key, field.Value, i == 0)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// flattenFields 将嵌套字段展开为点分隔的键，用于文本类输出
+func flattenFields(fields []Field) []Field {
+	hasGroup := false
+	for _, field := range fields {
+		if _, ok := field.Value.(fieldGroup); ok {
+			hasGroup = true
+			break
+		}
+	}
+	if !hasGroup {
+		return fields
+	}
+
+	flat := make([]Field, 0, len(fields))
+	return appendFlattened(flat, "", fields)
+}
+
+// appendFlattened 递归展开嵌套字段
+func appendFlattened(dst []Field, prefix string, fields []Field) []Field {
+	for _, field := range fields {
+		key := field.Key
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if group, ok := field.Value.(fieldGroup); ok {
+			dst = appendFlattened(dst, key, group)
+			continue
+		}
+		dst = append(dst, Field{Key: key, Value: field.Value})
+	}
+	return dst
+}
+
+// ReservedKeyPolicy 字段键与保留键冲突时的处理策略
+type ReservedKeyPolicy string
+
+const (
+	// ReservedKeyRename 将冲突的字段重命名为 fields.<key>
+	ReservedKeyRename ReservedKeyPolicy = "rename"
+	// ReservedKeyOverwrite 保留原字段键，由字段值覆盖保留键
+	ReservedKeyOverwrite ReservedKeyPolicy = "overwrite"
+	// ReservedKeyWarn 保留原字段键，并输出一次内部警告
+	ReservedKeyWarn ReservedKeyPolicy = "warn"
+)
+
+// reservedKeys 日志输出自身使用的键
+var reservedKeys = map[string]bool{
+	"time":       true,
+	"level":      true,
+	"msg":        true,
+	"logger":     true,
+	"caller":     true,
+	"stacktrace": true,
+}
+
+// maxStructDepth StructFields展开嵌套结构体的最大深度
+const maxStructDepth = 8
+
+// StructFields 通过反射将结构体的导出字段展开为 prefix.FieldName 形式的字段，
+// 支持 log:"name,omitempty" 标签，标签为"-"时忽略该字段
+func StructFields(prefix string, v interface{}) []Field {
+	return appendStructFields(nil, prefix, reflect.ValueOf(v), 0, map[uintptr]bool{})
+}
+
+// appendStructFields 递归展开结构体字段，visited记录当前路径上的指针用于检测循环引用
+func appendStructFields(dst []Field, prefix string, v reflect.Value, depth int, visited map[uintptr]bool) []Field {
+	if !v.IsValid() {
+		return append(dst, Field{Key: prefix, Value: nil})
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(dst, Field{Key: prefix, Value: nil})
+		}
+		if v.Kind() == reflect.Ptr {
+			ptr := v.Pointer()
+			if visited[ptr] {
+				return append(dst, Field{Key: prefix, Value: "<cycle>"})
+			}
+			visited[ptr] = true
+			defer delete(visited, ptr)
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || isLeafValue(v) {
+		return append(dst, Field{Key: prefix, Value: v.Interface()})
+	}
+	if depth >= maxStructDepth {
+		return append(dst, Field{Key: prefix, Value: "<max depth>"})
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, omitEmpty := sf.Name, false
+		if tag, ok := sf.Tag.Lookup("log"); ok {
+			if tag == "-" {
+				continue
+			}
+			parts := strings.Split(tag, ",")
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				if opt == "omitempty" {
+					omitEmpty = true
+				}
+			}
+		}
+
+		fv := v.Field(i)
+		if omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		dst = appendStructFields(dst, key, fv, depth+1, visited)
+	}
+	return dst
+}
+
+// isLeafValue 判断结构体是否应作为单个值输出，例如time.Time等实现了String或Error的类型
+func isLeafValue(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	switch v.Interface().(type) {
+	case fmt.Stringer, error:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxTrackedFieldValues 每个限流键同时跟踪的字段值数量上限，超过时清理已过期的计数窗口
+const maxTrackedFieldValues = 1024
+
+// FieldRateLimit 按字段值限流的规则，携带相同Key值的日志每秒最多输出MaxPerSecond条
+type FieldRateLimit struct {
+	Key          string
+	MaxPerSecond int
+}
+
+// fieldRateLimiter 单个键的限流状态，每个字段值独立计数
+type fieldRateLimiter struct {
+	limit FieldRateLimit
+
+	mu         sync.Mutex
+	windows    map[string]*fieldRateWindow
+	suppressed map[string]uint64 // 按字段值累计的抑制条数
+}
+
+// fieldRateWindow 一个字段值在当前一秒窗口内的计数
+type fieldRateWindow struct {
+	start time.Time
+	count int
+}
+
+// newFieldRateLimiters 根据配置创建各个键的限流器
+func newFieldRateLimiters(limits []FieldRateLimit) []*fieldRateLimiter {
+	limiters := make([]*fieldRateLimiter, 0, len(limits))
+	for _, limit := range limits {
+		if limit.Key == "" || limit.MaxPerSecond <= 0 {
+			continue
+		}
+		limiters = append(limiters, &fieldRateLimiter{
+			limit:      limit,
+			windows:    make(map[string]*fieldRateWindow),
+			suppressed: make(map[string]uint64),
+		})
+	}
+	return limiters
+}
+
+// allow 判断携带value的日志在now时刻是否输出
+func (l *fieldRateLimiter) allow(value string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window, exists := l.windows[value]
+	if !exists {
+		if len(l.windows) >= maxTrackedFieldValues {
+			l.pruneLocked(now)
+		}
+		window = &fieldRateWindow{start: now}
+		l.windows[value] = window
+	} else if now.Sub(window.start) >= time.Second {
+		window.start = now
+		window.count = 0
+	}
+
+	if window.count < l.limit.MaxPerSecond {
+		window.count++
+		return true
+	}
+	l.suppressed[value]++
+	return false
+}
+
+// reset 清空所有字段值的计数窗口，累计的抑制条数保留
+func (l *fieldRateLimiter) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.windows = make(map[string]*fieldRateWindow)
+}
+
+// pruneLocked 清理已过期的计数窗口，调用方需持有锁
+func (l *fieldRateLimiter) pruneLocked(now time.Time) {
+	for value, window := range l.windows {
+		if now.Sub(window.start) >= time.Second {
+			delete(l.windows, value)
+		}
+	}
+}
+
+// snapshot 获取按"键=值"汇总的抑制条数
+func (l *fieldRateLimiter) snapshot(into map[string]uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for value, count := range l.suppressed {
+		into[l.limit.Key+"="+value] += count
+	}
+}
+
+// allowFields 按字段值限流判断日志是否输出，同名字段以最后出现的值为准，被抑制的日志计入统计
+func (c *loggerCore) allowFields(level LogLevel, loggerFields []Field, ctx context.Context, callFields []Field) bool {
+	if len(c.fieldLimiters) == 0 || c.alwaysLog(level) {
+		return true
+	}
+
+	var ctxFields []Field
+	if ctx != nil {
+		ctxFields = c.contextFields(ctx)
+	}
+
+	now := c.now()
+	for _, limiter := range c.fieldLimiters {
+		value, ok := lastFieldValue(limiter.limit.Key, loggerFields, ctxFields, callFields)
+		if !ok {
+			continue
+		}
+		if !limiter.allow(formatValue(value), now) {
+			atomic.AddUint64(&c.stats.suppressed, 1)
+			return false
+		}
+	}
+	return true
+}
+
+// allow 判断日志是否输出：审计日志总是输出，其他日志先按级别采样，再按字段值限流
+func (c *loggerCore) allow(level LogLevel, loggerFields []Field, ctx context.Context, callFields []Field) bool {
+	if isAuditContext(ctx) {
+		return true
+	}
+	return c.sample(level) && c.allowFields(level, loggerFields, ctx, callFields)
+}
+
+// lastFieldValue 在多组字段中查找键最后出现的值
+func lastFieldValue(key string, groups ...[]Field) (interface{}, bool) {
+	for i := len(groups) - 1; i >= 0; i-- {
+		fields := groups[i]
+		for j := len(fields) - 1; j >= 0; j-- {
+			if fields[j].Key == key {
+				return fields[j].Value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// SamplingResetter 支持清空限流状态的日志实例
+type SamplingResetter interface {
+	// ResetSampling 清空按字段值限流的计数窗口，之后每个字段值重新开始计数
+	ResetSampling()
+}
+
+// resetSampling 清空所有限流键的计数窗口
+func (c *loggerCore) resetSampling() {
+	for _, limiter := range c.fieldLimiters {
+		limiter.reset()
+	}
+}
+
+// fieldSuppressed 汇总所有限流键按值的抑制条数，未配置限流时返回nil
+func (c *loggerCore) fieldSuppressed() map[string]uint64 {
+	if len(c.fieldLimiters) == 0 {
+		return nil
+	}
+	counts := make(map[string]uint64)
+	for _, limiter := range c.fieldLimiters {
+		limiter.snapshot(counts)
+	}
+	return counts
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"strconv"
+)
+
+// fieldTraceKey 字段追踪诊断字段的键名
+const fieldTraceKey = "field_trace"
+
+// fieldTraceEnabled 判断是否开启字段追踪，字段追踪只在开发模式下生效
+func (c *loggerCore) fieldTraceEnabled() bool {
+	return c.options.FieldTrace && c.options.Development
+}
+
+// traceFields 记录新添加字段的调用位置，返回与日志实例上全部字段一一对应的位置列表，未开启字段追踪时返回nil
+func (c *loggerCore) traceFields(sites []string, fields []Field) []string {
+	if !c.fieldTraceEnabled() {
+		return nil
+	}
+
+	site := callSite()
+	traced := make([]string, 0, len(sites)+len(fields))
+	traced = append(traced, sites...)
+	for range fields {
+		traced = append(traced, site)
+	}
+	return traced
+}
+
+// fieldTrace 生成同名字段的来源诊断字段，值为键到各来源位置的映射，只包含被设置多次的键。
+// 常量字段的来源为const，上下文字段的来源为context，本次调用的字段的来源为日志调用位置
+func (c *loggerCore) fieldTrace(loggerFields []Field, sites []string, ctxFields []Field, callFields []Field) (Field, bool) {
+	if !c.fieldTraceEnabled() {
+		return Field{}, false
+	}
+
+	sources := make(map[string][]string)
+	var order []string
+	add := func(key, source string) {
+		if _, exists := sources[key]; !exists {
+			order = append(order, key)
+		}
+		sources[key] = append(sources[key], source)
+	}
+
+	for _, field := range c.constFields {
+		add(field.Key, "const")
+	}
+	for i, field := range loggerFields {
+		site := "unknown"
+		if i < len(sites) {
+			site = sites[i]
+		}
+		add(field.Key, site)
+	}
+	for _, field := range ctxFields {
+		add(field.Key, "context")
+	}
+	if len(callFields) > 0 {
+		site := callSite()
+		for _, field := range callFields {
+			add(field.Key, site)
+		}
+	}
+
+	trace := make(map[string][]string)
+	for _, key := range order {
+		if len(sources[key]) > 1 {
+			trace[key] = sources[key]
+		}
+	}
+	if len(trace) == 0 {
+		return Field{}, false
+	}
+	return Field{Key: fieldTraceKey, Value: trace}, true
+}
+
+// callSite 获取日志门面之外第一个调用者的位置，格式为 目录/文件:行号
+func callSite() string {
+	_, file, line, ok := facadeCaller()
+	if !ok {
+		return "unknown"
+	}
+	return trimCallerPath(file) + ":" + strconv.Itoa(line)
+}
@@ -0,0 +1,309 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// EmitFunc 接收一条日志的回调函数，fields为经过合并、脱敏等处理后的全部字段
+type EmitFunc func(level LogLevel, msg string, fields []Field)
+
+// FuncLogger 将日志分发给回调函数的日志实例，无需编写完整的适配器即可桥接任意第三方日志库、
+// 自定义输出或测试中的记录器
+type FuncLogger struct {
+	level  LogLevel
+	fields []Field
+	ctx    context.Context
+	emit   EmitFunc
+	name   string
+	sites  []string // 开启字段追踪时各字段的添加位置
+	core   *loggerCore
+}
+
+// 确保FuncLogger实现了Logger接口
+var _ Logger = (*FuncLogger)(nil)
+
+// NewFuncLogger 创建将日志分发给emit的日志实例，低于level的日志不会调用emit，
+// 字段合并、脱敏、采样等选项与其他日志实例一致，输出相关的选项不生效，也不会创建或打开输出文件
+func NewFuncLogger(name string, level LogLevel, emit EmitFunc, opts ...Option) *FuncLogger {
+	options := wrappedLoggerOptions(level, "", opts)
+
+	return &FuncLogger{
+		level:  options.Level,
+		fields: make([]Field, 0),
+		ctx:    context.Background(),
+		emit:   emit,
+		name:   name,
+		core:   newLoggerCoreWithOutput(options, io.Discard),
+	}
+}
+
+// SetLevel 设置日志级别
+func (f *FuncLogger) SetLevel(level LogLevel) {
+	f.level = level
+}
+
+// GetLevel 获取当前日志级别
+func (f *FuncLogger) GetLevel() LogLevel {
+	return f.level
+}
+
+// log 将一条日志交给回调函数，致命级日志退出程序，恐慌级日志触发panic
+func (f *FuncLogger) log(ctx context.Context, level LogLevel, msg string, fields []Field) {
+	allFields := f.core.mergeFields(level, msg, f.fields, f.sites, ctx, fields)
+	msg = f.core.redactMessage(msg)
+	f.emit(level, msg, allFields)
+	atomic.AddUint64(&f.core.stats.emitted, 1)
+
+	switch level {
+	case FatalLevel:
+		f.core.exit(1)
+	case PanicLevel:
+		if f.core.crashDumpEnabled() {
+			f.core.writeCrashDump(f.core.crashEntry(f.name, msg, allFields))
+		}
+		panic(msg)
+	}
+}
+
+// Debug 输出调试级日志
+func (f *FuncLogger) Debug(msg string, fields ...Field) {
+	if levelEnabled(f.level, f.ctx, DebugLevel) && f.core.allow(DebugLevel, f.fields, f.ctx, fields) {
+		f.log(f.ctx, DebugLevel, msg, fields)
+	}
+}
+
+// Debugf 输出格式化的调试级日志
+func (f *FuncLogger) Debugf(format string, args ...interface{}) {
+	if levelEnabled(f.level, f.ctx, DebugLevel) && f.core.allow(DebugLevel, f.fields, f.ctx, nil) {
+		f.log(f.ctx, DebugLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// DebugCtx 使用ctx中提取的字段输出调试级日志
+func (f *FuncLogger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(f.level, ctx, DebugLevel) && f.core.allow(DebugLevel, f.fields, ctx, fields) {
+		f.log(ctx, DebugLevel, msg, fields)
+	}
+}
+
+// Info 输出信息级日志
+func (f *FuncLogger) Info(msg string, fields ...Field) {
+	if levelEnabled(f.level, f.ctx, InfoLevel) && f.core.allow(InfoLevel, f.fields, f.ctx, fields) {
+		f.log(f.ctx, InfoLevel, msg, fields)
+	}
+}
+
+// Infof 输出格式化的信息级日志
+func (f *FuncLogger) Infof(format string, args ...interface{}) {
+	if levelEnabled(f.level, f.ctx, InfoLevel) && f.core.allow(InfoLevel, f.fields, f.ctx, nil) {
+		f.log(f.ctx, InfoLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// InfoCtx 使用ctx中提取的字段输出信息级日志
+func (f *FuncLogger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(f.level, ctx, InfoLevel) && f.core.allow(InfoLevel, f.fields, ctx, fields) {
+		f.log(ctx, InfoLevel, msg, fields)
+	}
+}
+
+// Warn 输出警告级日志
+func (f *FuncLogger) Warn(msg string, fields ...Field) {
+	if levelEnabled(f.level, f.ctx, WarnLevel) && f.core.allow(WarnLevel, f.fields, f.ctx, fields) {
+		f.log(f.ctx, WarnLevel, msg, fields)
+	}
+}
+
+// Warnf 输出格式化的警告级日志
+func (f *FuncLogger) Warnf(format string, args ...interface{}) {
+	if levelEnabled(f.level, f.ctx, WarnLevel) && f.core.allow(WarnLevel, f.fields, f.ctx, nil) {
+		f.log(f.ctx, WarnLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// WarnCtx 使用ctx中提取的字段输出警告级日志
+func (f *FuncLogger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(f.level, ctx, WarnLevel) && f.core.allow(WarnLevel, f.fields, ctx, fields) {
+		f.log(ctx, WarnLevel, msg, fields)
+	}
+}
+
+// Error 输出错误级日志
+func (f *FuncLogger) Error(msg string, fields ...Field) {
+	if levelEnabled(f.level, f.ctx, ErrorLevel) && f.core.allow(ErrorLevel, f.fields, f.ctx, fields) {
+		f.log(f.ctx, ErrorLevel, msg, fields)
+	}
+}
+
+// Errorf 输出格式化的错误级日志
+func (f *FuncLogger) Errorf(format string, args ...interface{}) {
+	if levelEnabled(f.level, f.ctx, ErrorLevel) && f.core.allow(ErrorLevel, f.fields, f.ctx, nil) {
+		f.log(f.ctx, ErrorLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// ErrorCtx 使用ctx中提取的字段输出错误级日志
+func (f *FuncLogger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(f.level, ctx, ErrorLevel) && f.core.allow(ErrorLevel, f.fields, ctx, fields) {
+		f.log(ctx, ErrorLevel, msg, fields)
+	}
+}
+
+// Fatal 输出致命级日志并退出程序
+func (f *FuncLogger) Fatal(msg string, fields ...Field) {
+	if levelEnabled(f.level, f.ctx, FatalLevel) && f.core.allow(FatalLevel, f.fields, f.ctx, fields) {
+		f.log(f.ctx, FatalLevel, msg, fields)
+	}
+}
+
+// Fatalf 输出格式化的致命级日志并退出程序
+func (f *FuncLogger) Fatalf(format string, args ...interface{}) {
+	if levelEnabled(f.level, f.ctx, FatalLevel) && f.core.allow(FatalLevel, f.fields, f.ctx, nil) {
+		f.log(f.ctx, FatalLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+func (f *FuncLogger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(f.level, ctx, FatalLevel) && f.core.allow(FatalLevel, f.fields, ctx, fields) {
+		f.log(ctx, FatalLevel, msg, fields)
+	}
+}
+
+// Panic 输出恐慌级日志并触发panic
+func (f *FuncLogger) Panic(msg string, fields ...Field) {
+	if levelEnabled(f.level, f.ctx, PanicLevel) && f.core.allow(PanicLevel, f.fields, f.ctx, fields) {
+		f.log(f.ctx, PanicLevel, msg, fields)
+	}
+}
+
+// Panicf 输出格式化的恐慌级日志并触发panic
+func (f *FuncLogger) Panicf(format string, args ...interface{}) {
+	if levelEnabled(f.level, f.ctx, PanicLevel) && f.core.allow(PanicLevel, f.fields, f.ctx, nil) {
+		f.log(f.ctx, PanicLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+func (f *FuncLogger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(f.level, ctx, PanicLevel) && f.core.allow(PanicLevel, f.fields, ctx, fields) {
+		f.log(ctx, PanicLevel, msg, fields)
+	}
+}
+
+// WithFields 添加字段到日志
+func (f *FuncLogger) WithFields(fields ...Field) Logger {
+	newLogger := *f
+	newLogger.fields = make([]Field, 0, len(f.fields)+len(fields))
+	newLogger.fields = append(newLogger.fields, f.fields...)
+	newLogger.fields = append(newLogger.fields, fields...)
+	newLogger.sites = f.core.traceFields(f.sites, fields)
+	return &newLogger
+}
+
+// WithField 添加单个字段到日志
+func (f *FuncLogger) WithField(key string, value interface{}) Logger {
+	return f.WithFields(Field{Key: key, Value: value})
+}
+
+// Clone 以新的名称复制日志实例，保留级别、字段、上下文和回调函数
+func (f *FuncLogger) Clone(name string) Logger {
+	newLogger := *f
+	newLogger.name = name
+	newLogger.fields = append([]Field(nil), f.fields...)
+	newLogger.sites = append([]string(nil), f.sites...)
+	newLogger.core = f.core.clone()
+	return &newLogger
+}
+
+// WithContext 添加上下文到日志
+func (f *FuncLogger) WithContext(ctx context.Context) Logger {
+	newLogger := *f
+	newLogger.ctx = ctx
+	return &newLogger
+}
+
+// WithError 添加错误信息到日志，err为nil时返回当前日志实例
+func (f *FuncLogger) WithError(err error) Logger {
+	if err == nil {
+		return f
+	}
+	return f.WithField("error", err)
+}
+
+// WithTime 添加时间到日志
+func (f *FuncLogger) WithTime(t time.Time) Logger {
+	return f.WithField("time", t)
+}
+
+// IsDebugEnabled 检查调试级别是否启用
+func (f *FuncLogger) IsDebugEnabled() bool {
+	return f.level <= DebugLevel
+}
+
+// IsInfoEnabled 检查信息级别是否启用
+func (f *FuncLogger) IsInfoEnabled() bool {
+	return f.level <= InfoLevel
+}
+
+// IsWarnEnabled 检查警告级别是否启用
+func (f *FuncLogger) IsWarnEnabled() bool {
+	return f.level <= WarnLevel
+}
+
+// IsErrorEnabled 检查错误级别是否启用
+func (f *FuncLogger) IsErrorEnabled() bool {
+	return f.level <= ErrorLevel
+}
+
+// IsFatalEnabled 检查致命级别是否启用
+func (f *FuncLogger) IsFatalEnabled() bool {
+	return f.level <= FatalLevel
+}
+
+// IsPanicEnabled 检查恐慌级别是否启用
+func (f *FuncLogger) IsPanicEnabled() bool {
+	return f.level <= PanicLevel
+}
+
+// IsEnabled 检查level级别是否启用，与实际输出日志时一样考虑上下文中的级别覆盖
+func (f *FuncLogger) IsEnabled(level LogLevel) bool {
+	return levelEnabled(f.level, f.ctx, level)
+}
+
+// Stats 获取日志输出统计，交给回调函数的日志计为成功写入
+func (f *FuncLogger) Stats() LoggerStats {
+	stats := f.core.stats.snapshot()
+	stats.FieldSuppressed = f.core.fieldSuppressed()
+	return stats
+}
+
+// ResetSampling 清空按字段值限流的计数窗口，被抑制的消息可以立即再次输出，派生的日志实例同时生效。
+// 适合在每个请求或测试开始时调用，避免上一阶段的限流状态影响下一阶段，统计中累计的抑制条数不受影响
+func (f *FuncLogger) ResetSampling() {
+	f.core.resetSampling()
+}
+
+// StartTimer 将uptime字段的起始时间重置为当前时间，派生的日志实例同时生效
+func (f *FuncLogger) StartTimer() {
+	f.core.startTimer()
+}
+
+// Describe 获取日志实例的有效配置信息
+func (f *FuncLogger) Describe() LoggerInfo {
+	info := f.core.describe("func", f.name, f.level)
+	info.OutputPath = "func"
+	return info
+}
+
+// Sync 输出采样汇总，回调函数直接处理日志，无需刷新
+func (f *FuncLogger) Sync() error {
+	if level, fields, ok := f.core.samplingSummary(); ok && f.level <= level {
+		f.log(f.ctx, level, samplingSummaryMessage, fields)
+	}
+	return nil
+}
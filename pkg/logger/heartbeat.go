@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultHeartbeatMessage 未指定消息时心跳日志使用的消息
+const DefaultHeartbeatMessage = "heartbeat"
+
+// heartbeat 定期输出心跳日志的后台goroutine
+type heartbeat struct {
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// startHeartbeat 配置了心跳间隔时启动心跳goroutine，每个间隔以从1开始递增的序号调用emit，
+// 日志实例关闭时通过stopHeartbeat停止
+func (c *loggerCore) startHeartbeat(emit func(count uint64)) {
+	interval := c.options.HeartbeatInterval
+	if interval <= 0 {
+		return
+	}
+
+	hb := &heartbeat{stop: make(chan struct{}), done: make(chan struct{})}
+	c.heartbeat = hb
+	go func() {
+		defer close(hb.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var count uint64
+		for {
+			select {
+			case <-hb.stop:
+				return
+			case <-ticker.C:
+				count++
+				emit(count)
+			}
+		}
+	}()
+}
+
+// stopHeartbeat 停止心跳goroutine并等待正在输出的心跳完成，可以重复调用
+func (c *loggerCore) stopHeartbeat() {
+	hb := c.heartbeat
+	if hb == nil {
+		return
+	}
+	hb.once.Do(func() { close(hb.stop) })
+	<-hb.done
+}
+
+// heartbeatMessage 返回心跳日志的消息
+func (c *loggerCore) heartbeatMessage() string {
+	if c.options.HeartbeatMessage == "" {
+		return DefaultHeartbeatMessage
+	}
+	return c.options.HeartbeatMessage
+}
@@ -0,0 +1,20 @@
+package logger
+
+// LoggerInfo 日志实例的有效配置信息，可用于诊断接口展示当前日志配置
+type LoggerInfo struct {
+	Name       string   // 日志实例名称
+	Provider   string   // 日志提供者名称
+	Level      LogLevel // 当前日志级别
+	Format     string   // 日志格式
+	OutputPath string   // 日志输出路径
+}
+
+// LoggerInfoMixin Describe方法的默认实现，自定义日志实例可嵌入并填充LoggerInfo
+type LoggerInfoMixin struct {
+	LoggerInfo LoggerInfo
+}
+
+// Describe 获取日志实例的有效配置信息
+func (m LoggerInfoMixin) Describe() LoggerInfo {
+	return m.LoggerInfo
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// errorOutput 日志门面自身的警告和错误输出，默认为标准错误
+var (
+	errorOutput   io.Writer = os.Stderr
+	errorOutputMu sync.Mutex
+)
+
+// SetErrorOutput 设置日志门面自身警告和错误的输出目标，传入nil时丢弃
+func SetErrorOutput(w io.Writer) {
+	errorOutputMu.Lock()
+	defer errorOutputMu.Unlock()
+	if w == nil {
+		w = io.Discard
+	}
+	errorOutput = w
+}
+
+// internalWarnf 向错误输出写入日志门面自身的警告
+func internalWarnf(format string, args ...interface{}) {
+	errorOutputMu.Lock()
+	defer errorOutputMu.Unlock()
+	fmt.Fprintf(errorOutput, "LandcLogFace: "+format+"\n", args...)
+}
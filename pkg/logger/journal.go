@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+func init() {
+	// 日志记录的字段值为interface{}，gob需要注册除基础类型以外的具体类型
+	gob.Register(time.Time{})
+	gob.Register(time.Duration(0))
+}
+
+// JournalLogger 日志记录包装器，在转发日志的同时将每条日志记录以长度前缀的gob格式追加到文件，
+// 之后可以通过ReadJournal读回并使用不同的编码器重新处理
+type JournalLogger struct {
+	Logger
+	journal *journal
+	fields  []Field
+	ctx     context.Context
+	time    time.Time
+}
+
+// journal 日志记录文件，派生的日志实例共享同一个文件
+type journal struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// 确保JournalLogger实现了Logger接口
+var _ Logger = (*JournalLogger)(nil)
+
+// NewJournalLogger 创建日志记录包装器，日志转发给logger，记录追加写入path
+func NewJournalLogger(logger Logger, path string) (*JournalLogger, error) {
+	if err := checkOutputPath(path); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("open journal %q: %w", path, err)
+	}
+
+	return &JournalLogger{
+		Logger:  logger,
+		journal: &journal{file: file},
+		ctx:     context.Background(),
+	}, nil
+}
+
+// derive 以新的被包装日志实例创建派生实例，共享记录文件
+func (j *JournalLogger) derive(logger Logger) *JournalLogger {
+	return &JournalLogger{
+		Logger:  logger,
+		journal: j.journal,
+		fields:  j.fields,
+		ctx:     j.ctx,
+		time:    j.time,
+	}
+}
+
+// record 将一条日志追加到记录文件，写入失败时输出内部警告
+func (j *JournalLogger) record(ctx context.Context, level LogLevel, msg string, fields []Field) {
+	if !levelEnabled(j.GetLevel(), ctx, level) {
+		return
+	}
+
+	ctxFields := FieldsFromContext(ctx)
+	all := make([]Field, 0, len(j.fields)+len(ctxFields)+len(fields))
+	all = append(all, j.fields...)
+	all = append(all, ctxFields...)
+	all = append(all, fields...)
+
+	t := j.time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	if err := j.journal.write(newEntry(t, level, j.Describe().Name, msg, journalFields(all))); err != nil {
+		internalWarnf("failed to write journal: %v", err)
+	}
+}
+
+// journalFields 展开嵌套字段并将字段值转换为gob可编码的类型
+func journalFields(fields []Field) []Field {
+	flat := flattenFields(fields)
+	converted := make([]Field, len(flat))
+	for i, field := range flat {
+		converted[i] = Field{Key: field.Key, Value: journalValue(field.Value)}
+	}
+	return converted
+}
+
+// journalValue 基础类型和时间类型保持原样，error转换为错误信息，其他类型转换为文本
+func journalValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string, []byte,
+		int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64,
+		time.Time, time.Duration:
+		return v
+	case error:
+		return v.Error()
+	default:
+		return formatValue(v)
+	}
+}
+
+// write 写入一条长度前缀的gob记录，每条记录单独编码，截断的记录不影响之前的记录
+func (j *journal) write(entry Entry) error {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 4))
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data, uint32(len(data)-4))
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err := j.file.Write(data)
+	return err
+}
+
+// ReadJournal 读取日志记录文件中的全部记录，文件末尾不完整的记录会被忽略
+func ReadJournal(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var entries []Entry
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(reader, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return entries, nil
+			}
+			return entries, err
+		}
+
+		data := make([]byte, binary.BigEndian.Uint32(header[:]))
+		if _, err := io.ReadFull(reader, data); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return entries, nil
+			}
+			return entries, err
+		}
+
+		var entry Entry
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+			return entries, fmt.Errorf("decode journal record %d: %w", len(entries), err)
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// Debug 输出调试级日志
+func (j *JournalLogger) Debug(msg string, fields ...Field) {
+	j.record(j.ctx, DebugLevel, msg, fields)
+	j.Logger.Debug(msg, fields...)
+}
+
+// Debugf 输出格式化的调试级日志
+func (j *JournalLogger) Debugf(format string, args ...interface{}) {
+	j.record(j.ctx, DebugLevel, fmt.Sprintf(format, args...), nil)
+	j.Logger.Debugf(format, args...)
+}
+
+// Info 输出信息级日志
+func (j *JournalLogger) Info(msg string, fields ...Field) {
+	j.record(j.ctx, InfoLevel, msg, fields)
+	j.Logger.Info(msg, fields...)
+}
+
+// Infof 输出格式化的信息级日志
+func (j *JournalLogger) Infof(format string, args ...interface{}) {
+	j.record(j.ctx, InfoLevel, fmt.Sprintf(format, args...), nil)
+	j.Logger.Infof(format, args...)
+}
+
+// Warn 输出警告级日志
+func (j *JournalLogger) Warn(msg string, fields ...Field) {
+	j.record(j.ctx, WarnLevel, msg, fields)
+	j.Logger.Warn(msg, fields...)
+}
+
+// Warnf 输出格式化的警告级日志
+func (j *JournalLogger) Warnf(format string, args ...interface{}) {
+	j.record(j.ctx, WarnLevel, fmt.Sprintf(format, args...), nil)
+	j.Logger.Warnf(format, args...)
+}
+
+// Error 输出错误级日志
+func (j *JournalLogger) Error(msg string, fields ...Field) {
+	j.record(j.ctx, ErrorLevel, msg, fields)
+	j.Logger.Error(msg, fields...)
+}
+
+// Errorf 输出格式化的错误级日志
+func (j *JournalLogger) Errorf(format string, args ...interface{}) {
+	j.record(j.ctx, ErrorLevel, fmt.Sprintf(format, args...), nil)
+	j.Logger.Errorf(format, args...)
+}
+
+// Fatal 输出致命级日志并退出程序，退出前先写入记录文件
+func (j *JournalLogger) Fatal(msg string, fields ...Field) {
+	j.record(j.ctx, FatalLevel, msg, fields)
+	j.Logger.Fatal(msg, fields...)
+}
+
+// Fatalf 输出格式化的致命级日志并退出程序，退出前先写入记录文件
+func (j *JournalLogger) Fatalf(format string, args ...interface{}) {
+	j.record(j.ctx, FatalLevel, fmt.Sprintf(format, args...), nil)
+	j.Logger.Fatalf(format, args...)
+}
+
+// Panic 输出恐慌级日志并触发panic
+func (j *JournalLogger) Panic(msg string, fields ...Field) {
+	j.record(j.ctx, PanicLevel, msg, fields)
+	j.Logger.Panic(msg, fields...)
+}
+
+// Panicf 输出格式化的恐慌级日志并触发panic
+func (j *JournalLogger) Panicf(format string, args ...interface{}) {
+	j.record(j.ctx, PanicLevel, fmt.Sprintf(format, args...), nil)
+	j.Logger.Panicf(format, args...)
+}
+
+// DebugCtx 使用ctx中提取的字段输出调试级日志
+func (j *JournalLogger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	j.record(ctx, DebugLevel, msg, fields)
+	j.Logger.DebugCtx(ctx, msg, fields...)
+}
+
+// InfoCtx 使用ctx中提取的字段输出信息级日志
+func (j *JournalLogger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	j.record(ctx, InfoLevel, msg, fields)
+	j.Logger.InfoCtx(ctx, msg, fields...)
+}
+
+// WarnCtx 使用ctx中提取的字段输出警告级日志
+func (j *JournalLogger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	j.record(ctx, WarnLevel, msg, fields)
+	j.Logger.WarnCtx(ctx, msg, fields...)
+}
+
+// ErrorCtx 使用ctx中提取的字段输出错误级日志
+func (j *JournalLogger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	j.record(ctx, ErrorLevel, msg, fields)
+	j.Logger.ErrorCtx(ctx, msg, fields...)
+}
+
+// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+func (j *JournalLogger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	j.record(ctx, FatalLevel, msg, fields)
+	j.Logger.FatalCtx(ctx, msg, fields...)
+}
+
+// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+func (j *JournalLogger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	j.record(ctx, PanicLevel, msg, fields)
+	j.Logger.PanicCtx(ctx, msg, fields...)
+}
+
+// WithFields 添加字段到日志
+func (j *JournalLogger) WithFields(fields ...Field) Logger {
+	derived := j.derive(j.Logger.WithFields(fields...))
+	derived.fields = make([]Field, 0, len(j.fields)+len(fields))
+	derived.fields = append(derived.fields, j.fields...)
+	derived.fields = append(derived.fields, fields...)
+	return derived
+}
+
+// WithField 添加单个字段到日志
+func (j *JournalLogger) WithField(key string, value interface{}) Logger {
+	return j.WithFields(Field{Key: key, Value: value})
+}
+
+// WithContext 添加上下文到日志
+func (j *JournalLogger) WithContext(ctx context.Context) Logger {
+	derived := j.derive(j.Logger.WithContext(ctx))
+	derived.ctx = ctx
+	return derived
+}
+
+// WithError 添加错误信息到日志，err为nil时返回当前日志实例
+func (j *JournalLogger) WithError(err error) Logger {
+	if err == nil {
+		return j
+	}
+	derived := j.derive(j.Logger.WithError(err))
+	derived.fields = make([]Field, 0, len(j.fields)+1)
+	derived.fields = append(derived.fields, j.fields...)
+	derived.fields = append(derived.fields, Field{Key: "error", Value: err})
+	return derived
+}
+
+// WithTime 添加时间到日志
+func (j *JournalLogger) WithTime(t time.Time) Logger {
+	derived := j.derive(j.Logger.WithTime(t))
+	derived.time = t
+	return derived
+}
+
+// Sync 刷新被包装的日志实例和记录文件
+func (j *JournalLogger) Sync() error {
+	err := j.Logger.Sync()
+	if syncErr := j.journal.sync(); err == nil {
+		err = syncErr
+	}
+	return err
+}
+
+// Close 刷新并关闭记录文件，不会关闭被包装的日志实例
+func (j *JournalLogger) Close() error {
+	return j.journal.close()
+}
+
+// sync 将记录文件写入磁盘
+func (j *journal) sync() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Sync()
+}
+
+// close 关闭记录文件
+func (j *journal) close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// ParseLevel 解析日志级别名称，不区分大小写，支持debug、info、warn（warning）、error、fatal和panic
+func ParseLevel(s string) (LogLevel, error) {
+	name := strings.TrimSpace(s)
+	if strings.EqualFold(name, "warning") {
+		return WarnLevel, nil
+	}
+	if level, ok := levelFromName(name); ok {
+		return level, nil
+	}
+	return InfoLevel, fmt.Errorf("unknown log level %q", s)
+}
+
+// ParseLevelOrDefault 解析日志级别名称，为空或无法解析时返回def
+func ParseLevelOrDefault(s string, def LogLevel) LogLevel {
+	level, err := ParseLevel(s)
+	if err != nil {
+		return def
+	}
+	return level
+}
+
+// LevelValue 实现flag.Value的日志级别，可以通过flag.Var将 -log-level=debug 这样的命令行参数解析为日志级别
+type LevelValue LogLevel
+
+// 确保LevelValue实现了flag.Getter接口
+var _ flag.Getter = (*LevelValue)(nil)
+
+// Set 解析并设置日志级别，无法解析时返回错误，flag包会输出错误和用法
+func (v *LevelValue) Set(s string) error {
+	level, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	*v = LevelValue(level)
+	return nil
+}
+
+// String 返回日志级别的小写名称，作为flag包用法中显示的默认值
+func (v *LevelValue) String() string {
+	if v == nil {
+		return ""
+	}
+	return strings.ToLower(LogLevel(*v).String())
+}
+
+// Get 返回日志级别
+func (v *LevelValue) Get() interface{} {
+	return v.Level()
+}
+
+// Level 返回日志级别
+func (v *LevelValue) Level() LogLevel {
+	return LogLevel(*v)
+}
+
+// LevelFlag 在flag.CommandLine上定义日志级别参数，返回保存解析结果的指针，用法与flag.String一致
+func LevelFlag(name string, value LogLevel, usage string) *LogLevel {
+	level := value
+	flag.CommandLine.Var((*LevelValue)(&level), name, usage)
+	return &level
+}
+
+// WithTemporaryLevel 以level级别运行fn，fn收到的是从l派生的日志实例，适合为关键代码段临时输出详细日志。
+// 派生实例拥有独立的级别，l本身及其他goroutine使用的日志实例不受影响，fn返回后派生实例不应继续使用
+func WithTemporaryLevel(l Logger, level LogLevel, fn func(Logger)) {
+	derived := l.WithFields()
+	derived.SetLevel(level)
+	fn(derived)
+}
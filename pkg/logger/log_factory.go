@@ -1,14 +1,49 @@
 package logger
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 )
 
+// ErrProviderUnavailable 请求的日志提供者未注册（如被构建标签裁剪）
+var ErrProviderUnavailable = errors.New("logger provider unavailable")
+
+// MissingProviderPolicy 请求的日志提供者未注册时的处理策略，各策略都会按默认提供者和备用提供者链降级创建日志实例
+type MissingProviderPolicy string
+
+const (
+	// MissingProviderWarn 每个缺失的提供者输出一次内部警告，降级的日志实例带有provider_unavailable字段，为默认策略
+	MissingProviderWarn MissingProviderPolicy = "warn"
+	// MissingProviderError 每次创建时通过降级的日志实例输出一条错误级日志，日志实例带有provider_unavailable字段
+	MissingProviderError MissingProviderPolicy = "error"
+	// MissingProviderSilent 静默降级，不输出警告也不添加字段
+	MissingProviderSilent MissingProviderPolicy = "silent"
+)
+
 // LogFactory 日志工厂
 type LogFactory struct {
-	providers       map[string]LoggerProvider
-	defaultProvider string
-	mu              sync.RWMutex
+	providers         map[string]LoggerProvider
+	defaultProvider   string
+	fallbackProviders []string
+	missingPolicy     MissingProviderPolicy
+	warnedMissing     map[string]bool // 已输出过警告的缺失提供者
+	mu                sync.RWMutex
+
+	cache   map[loggerCacheKey]*cachedLogger
+	cacheMu sync.Mutex
+}
+
+// loggerCacheKey 日志实例缓存的键
+type loggerCacheKey struct {
+	name     string
+	provider string
+}
+
+// cachedLogger 缓存的日志实例，once保证同一个键并发首次获取时只创建一次
+type cachedLogger struct {
+	once   sync.Once
+	logger Logger
 }
 
 // 全局日志工厂实例
@@ -26,6 +61,7 @@ func GetLogFactory() *LogFactory {
 		factory.RegisterProvider("zap", NewZapLoggerProvider())
 		factory.RegisterProvider("logrus", NewLogrusLoggerProvider())
 		factory.RegisterProvider("std", NewStdLoggerProvider())
+		factory.RegisterProvider("auto", NewAutoLoggerProvider())
 		// 设置默认提供者为console
 		factory.SetDefaultProvider("console")
 	})
@@ -37,6 +73,9 @@ func NewLogFactory() *LogFactory {
 	return &LogFactory{
 		providers:       make(map[string]LoggerProvider),
 		defaultProvider: "console",
+		missingPolicy:   MissingProviderWarn,
+		warnedMissing:   make(map[string]bool),
+		cache:           make(map[loggerCacheKey]*cachedLogger),
 	}
 }
 
@@ -68,6 +107,93 @@ func (f *LogFactory) GetDefaultProvider() string {
 	return f.defaultProvider
 }
 
+// SetFallbackProviders 设置备用提供者链，指定的提供者和默认提供者都不可用时按顺序尝试，
+// 全部不可用时使用控制台日志
+func (f *LogFactory) SetFallbackProviders(names ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallbackProviders = append([]string(nil), names...)
+}
+
+// GetFallbackProviders 获取备用提供者链
+func (f *LogFactory) GetFallbackProviders() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return append([]string(nil), f.fallbackProviders...)
+}
+
+// SetMissingProviderPolicy 设置请求的日志提供者未注册时的处理策略，默认为MissingProviderWarn
+func (f *LogFactory) SetMissingProviderPolicy(policy MissingProviderPolicy) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.missingPolicy = policy
+}
+
+// GetMissingProviderPolicy 获取请求的日志提供者未注册时的处理策略
+func (f *LogFactory) GetMissingProviderPolicy() MissingProviderPolicy {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.missingPolicy
+}
+
+// CheckProvider 检查日志提供者是否已注册，未注册时返回包装了ErrProviderUnavailable的错误，可在启动时校验配置
+func (f *LogFactory) CheckProvider(name string) error {
+	if _, exists := f.GetProvider(name); !exists {
+		return fmt.Errorf("%w: %q", ErrProviderUnavailable, name)
+	}
+	return nil
+}
+
+// providerUnavailable 按缺失策略处理使用未注册的提供者name降级创建的日志实例log
+func (f *LogFactory) providerUnavailable(name string, log Logger) Logger {
+	f.mu.Lock()
+	policy := f.missingPolicy
+	firstWarning := !f.warnedMissing[name]
+	f.warnedMissing[name] = true
+	f.mu.Unlock()
+
+	if policy == MissingProviderSilent {
+		return log
+	}
+	log = log.WithField("provider_unavailable", name)
+	if policy == MissingProviderError {
+		log.Error(fmt.Sprintf("%v: %q, using degraded logger %s", ErrProviderUnavailable, name, log.Describe().Provider))
+	} else if firstWarning {
+		internalWarnf("%v: %q, using degraded logger %s", ErrProviderUnavailable, name, log.Describe().Provider)
+	}
+	return log
+}
+
+// createLogger 解析提供者并通过create创建日志实例，指定的提供者、默认提供者和备用提供者都不存在时使用控制台日志，
+// 指定的提供者未注册时按缺失策略处理
+func (f *LogFactory) createLogger(name, providerName string, create func(LoggerProvider) Logger) Logger {
+	var log Logger
+	if provider, exists := f.resolveProvider(providerName); exists {
+		log = create(provider)
+	} else {
+		log = NewConsoleLogger(name)
+	}
+
+	if _, exists := f.GetProvider(providerName); !exists {
+		log = f.providerUnavailable(providerName, log)
+	}
+	return log
+}
+
+// resolveProvider 依次查找指定的提供者、默认提供者和备用提供者链
+func (f *LogFactory) resolveProvider(name string) (LoggerProvider, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	candidates := append([]string{name, f.defaultProvider}, f.fallbackProviders...)
+	for _, candidate := range candidates {
+		if provider, exists := f.providers[candidate]; exists {
+			return provider, true
+		}
+	}
+	return nil, false
+}
+
 // GetProvider 获取指定的日志提供者
 func (f *LogFactory) GetProvider(name string) (LoggerProvider, bool) {
 	f.mu.RLock()
@@ -78,53 +204,57 @@ func (f *LogFactory) GetProvider(name string) (LoggerProvider, bool) {
 
 // CreateLogger 创建日志实例
 func (f *LogFactory) CreateLogger(name string) Logger {
-	return f.CreateLoggerWithProvider(name, f.defaultProvider)
+	return f.CreateLoggerWithProvider(name, f.GetDefaultProvider())
 }
 
 // CreateLoggerWithProvider 使用指定的提供者创建日志实例
 func (f *LogFactory) CreateLoggerWithProvider(name string, providerName string) Logger {
-	f.mu.RLock()
-	provider, exists := f.providers[providerName]
-	f.mu.RUnlock()
+	return f.createLogger(name, providerName, func(provider LoggerProvider) Logger {
+		return provider.Create(name)
+	})
+}
 
+// GetOrCreateLogger 获取缓存的日志实例，相同名称和提供者的重复请求返回同一个实例，
+// providerName为空时使用默认提供者，并发的首次请求只会创建一次
+func (f *LogFactory) GetOrCreateLogger(name string, providerName string) Logger {
+	if providerName == "" {
+		providerName = f.GetDefaultProvider()
+	}
+	key := loggerCacheKey{name: name, provider: providerName}
+
+	f.cacheMu.Lock()
+	entry, exists := f.cache[key]
 	if !exists {
-		// 如果指定的提供者不存在，使用默认提供者
-		f.mu.RLock()
-		provider, exists = f.providers[f.defaultProvider]
-		f.mu.RUnlock()
-		if !exists {
-			// 如果默认提供者也不存在，使用控制台日志
-			return NewConsoleLogger(name)
-		}
+		entry = &cachedLogger{}
+		f.cache[key] = entry
 	}
+	f.cacheMu.Unlock()
+
+	// 在锁外创建，避免创建较慢的日志实例阻塞其他名称的请求
+	entry.once.Do(func() {
+		entry.logger = f.CreateLoggerWithProvider(name, providerName)
+	})
+	return entry.logger
+}
 
-	return provider.Create(name)
+// ClearLoggerCache 清空日志实例缓存，之后的请求会重新创建日志实例，已返回的实例不会被关闭
+func (f *LogFactory) ClearLoggerCache() {
+	f.cacheMu.Lock()
+	defer f.cacheMu.Unlock()
+	f.cache = make(map[loggerCacheKey]*cachedLogger)
 }
 
 // CreateLoggerWithConfig 根据配置创建日志实例
 func (f *LogFactory) CreateLoggerWithConfig(name string, config map[string]interface{}) Logger {
 	// 从配置中获取提供者名称
-	providerName := f.defaultProvider
+	providerName := f.GetDefaultProvider()
 	if pn, ok := config["provider"].(string); ok {
 		providerName = pn
 	}
 
-	f.mu.RLock()
-	provider, exists := f.providers[providerName]
-	f.mu.RUnlock()
-
-	if !exists {
-		// 如果指定的提供者不存在，使用默认提供者
-		f.mu.RLock()
-		provider, exists = f.providers[f.defaultProvider]
-		f.mu.RUnlock()
-		if !exists {
-			// 如果默认提供者也不存在，使用控制台日志
-			return NewConsoleLogger(name)
-		}
-	}
-
-	return provider.CreateWithConfig(name, config)
+	return f.createLogger(name, providerName, func(provider LoggerProvider) Logger {
+		return provider.CreateWithConfig(name, config)
+	})
 }
 
 // CreateLoggerWithLogConfig 根据LogConfig创建日志实例
@@ -132,22 +262,6 @@ func (f *LogFactory) CreateLoggerWithLogConfig(config *LogConfig) Logger {
 	// 验证配置
 	config.Validate()
 
-	// 获取提供者
-	f.mu.RLock()
-	provider, exists := f.providers[config.Provider]
-	f.mu.RUnlock()
-
-	if !exists {
-		// 如果指定的提供者不存在，使用默认提供者
-		f.mu.RLock()
-		provider, exists = f.providers[f.defaultProvider]
-		f.mu.RUnlock()
-		if !exists {
-			// 如果默认提供者也不存在，使用控制台日志
-			return NewConsoleLogger(config.Name)
-		}
-	}
-
 	// 创建配置map
 	configMap := make(map[string]interface{})
 	configMap["provider"] = config.Provider
@@ -165,7 +279,9 @@ func (f *LogFactory) CreateLoggerWithLogConfig(config *LogConfig) Logger {
 		configMap[k] = v
 	}
 
-	return provider.CreateWithConfig(config.Name, configMap)
+	return f.createLogger(config.Name, config.Provider, func(provider LoggerProvider) Logger {
+		return provider.CreateWithConfig(config.Name, configMap)
+	})
 }
 
 // 全局日志实例
@@ -182,14 +298,19 @@ func GetLogger() Logger {
 	return globalLogger
 }
 
-// GetLoggerWithName 获取指定名称的日志实例
+// GetLoggerWithName 获取指定名称的日志实例，相同名称的重复请求返回同一个实例
 func GetLoggerWithName(name string) Logger {
-	return GetLogFactory().CreateLogger(name)
+	return GetLogFactory().GetOrCreateLogger(name, "")
 }
 
-// GetLoggerWithProvider 获取指定提供者的日志实例
+// GetLoggerWithProvider 获取指定提供者的日志实例，相同名称和提供者的重复请求返回同一个实例
 func GetLoggerWithProvider(name string, provider string) Logger {
-	return GetLogFactory().CreateLoggerWithProvider(name, provider)
+	return GetLogFactory().GetOrCreateLogger(name, provider)
+}
+
+// ClearLoggerCache 清空全局日志工厂的日志实例缓存
+func ClearLoggerCache() {
+	GetLogFactory().ClearLoggerCache()
 }
 
 // GetLoggerWithConfig 根据配置获取日志实例
@@ -204,9 +325,84 @@ func GetLoggerWithLogConfig(config *LogConfig) Logger {
 
 // SetGlobalLogger 设置全局日志实例
 func SetGlobalLogger(logger Logger) {
+	// 确保之后的GetLogger不会覆盖这里设置的实例
+	loggerOnce.Do(func() {})
 	globalLogger = logger
 }
 
+// IsDebugEnabled 检查全局日志的调试级别是否启用
+func IsDebugEnabled() bool {
+	return GetLogger().IsDebugEnabled()
+}
+
+// IsInfoEnabled 检查全局日志的信息级别是否启用
+func IsInfoEnabled() bool {
+	return GetLogger().IsInfoEnabled()
+}
+
+// IsWarnEnabled 检查全局日志的警告级别是否启用
+func IsWarnEnabled() bool {
+	return GetLogger().IsWarnEnabled()
+}
+
+// IsErrorEnabled 检查全局日志的错误级别是否启用
+func IsErrorEnabled() bool {
+	return GetLogger().IsErrorEnabled()
+}
+
+// IsFatalEnabled 检查全局日志的致命级别是否启用
+func IsFatalEnabled() bool {
+	return GetLogger().IsFatalEnabled()
+}
+
+// IsPanicEnabled 检查全局日志的恐慌级别是否启用
+func IsPanicEnabled() bool {
+	return GetLogger().IsPanicEnabled()
+}
+
+// isEnabled 检查日志实例是否启用level级别，实现了LevelEnabler的实例会考虑上下文中的级别覆盖
+func isEnabled(log Logger, level LogLevel) bool {
+	if enabler, ok := log.(LevelEnabler); ok {
+		return enabler.IsEnabled(level)
+	}
+	switch level {
+	case DebugLevel:
+		return log.IsDebugEnabled()
+	case InfoLevel:
+		return log.IsInfoEnabled()
+	case WarnLevel:
+		return log.IsWarnEnabled()
+	case ErrorLevel:
+		return log.IsErrorEnabled()
+	case FatalLevel:
+		return log.IsFatalEnabled()
+	default:
+		return log.IsPanicEnabled()
+	}
+}
+
+// LogfIfEnabled 全局日志启用level级别时格式化并输出日志，未启用时直接返回，不会调用fmt.Sprintf
+func LogfIfEnabled(level LogLevel, format string, args ...interface{}) {
+	log := GetLogger()
+	if !isEnabled(log, level) {
+		return
+	}
+	switch level {
+	case DebugLevel:
+		log.Debugf(format, args...)
+	case InfoLevel:
+		log.Infof(format, args...)
+	case WarnLevel:
+		log.Warnf(format, args...)
+	case ErrorLevel:
+		log.Errorf(format, args...)
+	case FatalLevel:
+		log.Fatalf(format, args...)
+	default:
+		log.Panicf(format, args...)
+	}
+}
+
 // Debug 全局调试级日志
 func Debug(msg string, fields ...Field) {
 	GetLogger().Debug(msg, fields...)
@@ -214,7 +410,9 @@ func Debug(msg string, fields ...Field) {
 
 // Debugf 全局格式化调试级日志
 func Debugf(format string, args ...interface{}) {
-	GetLogger().Debugf(format, args...)
+	if log := GetLogger(); isEnabled(log, DebugLevel) {
+		log.Debugf(format, args...)
+	}
 }
 
 // Info 全局信息级日志
@@ -224,7 +422,9 @@ func Info(msg string, fields ...Field) {
 
 // Infof 全局格式化信息级日志
 func Infof(format string, args ...interface{}) {
-	GetLogger().Infof(format, args...)
+	if log := GetLogger(); isEnabled(log, InfoLevel) {
+		log.Infof(format, args...)
+	}
 }
 
 // Warn 全局警告级日志
@@ -234,7 +434,9 @@ func Warn(msg string, fields ...Field) {
 
 // Warnf 全局格式化警告级日志
 func Warnf(format string, args ...interface{}) {
-	GetLogger().Warnf(format, args...)
+	if log := GetLogger(); isEnabled(log, WarnLevel) {
+		log.Warnf(format, args...)
+	}
 }
 
 // Error 全局错误级日志
@@ -244,7 +446,9 @@ func Error(msg string, fields ...Field) {
 
 // Errorf 全局格式化错误级日志
 func Errorf(format string, args ...interface{}) {
-	GetLogger().Errorf(format, args...)
+	if log := GetLogger(); isEnabled(log, ErrorLevel) {
+		log.Errorf(format, args...)
+	}
 }
 
 // Fatal 全局致命级日志
@@ -254,7 +458,9 @@ func Fatal(msg string, fields ...Field) {
 
 // Fatalf 全局格式化致命级日志
 func Fatalf(format string, args ...interface{}) {
-	GetLogger().Fatalf(format, args...)
+	if log := GetLogger(); isEnabled(log, FatalLevel) {
+		log.Fatalf(format, args...)
+	}
 }
 
 // Panic 全局恐慌级日志
@@ -264,5 +470,7 @@ func Panic(msg string, fields ...Field) {
 
 // Panicf 全局格式化恐慌级日志
 func Panicf(format string, args ...interface{}) {
-	GetLogger().Panicf(format, args...)
+	if log := GetLogger(); isEnabled(log, PanicLevel) {
+		log.Panicf(format, args...)
+	}
 }
@@ -2,6 +2,9 @@ package logger
 
 import (
 	"context"
+	"io"
+	"reflect"
+	"regexp"
 	"time"
 )
 
@@ -86,6 +89,19 @@ type Logger interface {
 	// Panicf 输出格式化的恐慌级日志并触发panic
 	Panicf(format string, args ...interface{})
 
+	// DebugCtx 使用ctx中提取的字段输出调试级日志
+	DebugCtx(ctx context.Context, msg string, fields ...Field)
+	// InfoCtx 使用ctx中提取的字段输出信息级日志
+	InfoCtx(ctx context.Context, msg string, fields ...Field)
+	// WarnCtx 使用ctx中提取的字段输出警告级日志
+	WarnCtx(ctx context.Context, msg string, fields ...Field)
+	// ErrorCtx 使用ctx中提取的字段输出错误级日志
+	ErrorCtx(ctx context.Context, msg string, fields ...Field)
+	// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+	FatalCtx(ctx context.Context, msg string, fields ...Field)
+	// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+	PanicCtx(ctx context.Context, msg string, fields ...Field)
+
 	// WithFields 添加字段到日志
 	WithFields(fields ...Field) Logger
 	// WithField 添加单个字段到日志
@@ -110,6 +126,9 @@ type Logger interface {
 	// IsPanicEnabled 检查恐慌级别是否启用
 	IsPanicEnabled() bool
 
+	// Describe 获取日志实例的有效配置信息
+	Describe() LoggerInfo
+
 	// Sync 刷新日志缓冲区
 	Sync() error
 }
@@ -122,6 +141,25 @@ type LoggerProvider interface {
 	CreateWithConfig(name string, config map[string]interface{}) Logger
 }
 
+// TimerStarter 支持重置uptime字段起始时间的日志实例
+type TimerStarter interface {
+	// StartTimer 将uptime字段的起始时间重置为当前时间，派生的日志实例同时生效
+	StartTimer()
+}
+
+// LevelEnabler 支持按级别检查是否启用的日志实例，全局格式化日志函数据此在格式化消息之前跳过未启用的级别
+type LevelEnabler interface {
+	// IsEnabled 检查level级别是否启用
+	IsEnabled(level LogLevel) bool
+}
+
+// Cloner 支持以新名称复制配置的日志实例
+type Cloner interface {
+	// Clone 以新的名称复制日志实例，复制的实例与原实例共享输出目标，任何一方SetOutput对另一方同样生效，
+	// 关闭复制的实例只刷新输出而不关闭输出目标。序号、统计、限流状态相互独立，复制的实例不启动心跳
+	Clone(name string) Logger
+}
+
 // Option 日志配置选项
 type Option func(*LoggerOptions)
 
@@ -136,6 +174,77 @@ type LoggerOptions struct {
 	CompressLogs   bool          // 是否压缩旧日志
 	MaxMessageSize int           // 单条日志最大大小（KB）
 	Config         map[string]interface{}
+
+	BufferedWriterSize int                // 输出缓冲区大小（字节），0表示不缓冲
+	ContextExtractors  []ContextExtractor // 上下文字段提取器
+	ContextErrField    bool               // 上下文已取消或超时时是否添加ctx_err字段
+	ErrorFormatter     ErrorFormatter     // 错误字段格式化函数，nil表示保持原样
+	Marshalers         MarshalerMap       // 按字段值类型注册的转换函数
+	GoroutineID        bool               // 是否为每条日志添加goid字段
+	Sequence           bool               // 是否为每条日志添加递增的seq字段
+	GlobalSequence     bool               // 是否为每条日志添加进程内所有日志实例共享的递增gseq字段
+	CompressedOutput   bool               // 是否以gzip压缩写入日志文件
+	Development        bool               // 是否为开发模式
+	FieldTrace         bool               // 开发模式下是否记录字段的添加位置，并为同名字段输出field_trace诊断字段
+	ReservedKeyPolicy  ReservedKeyPolicy  // 字段键与保留键冲突时的处理策略
+	Color              bool               // 文本格式是否使用颜色输出级别
+	LevelColors        LevelColorMap      // 按级别覆盖的ANSI颜色
+	Environment        string             // 运行环境（dev或prod），供auto提供者选择后端
+	ConstFields        []Field            // 常量字段，输出在所有字段之前
+	DedupeFields       bool               // 是否对同名字段去重，后出现的值覆盖先出现的值
+	OmitNilFields      bool               // 是否去除值为nil的字段
+	FieldAllowlist     []string           // 允许输出的字段键，为空时不限制
+	OutputPaths        []string           // 多个日志输出路径，设置后代替OutputPath
+	Writer             io.Writer          // 自定义输出目标
+	ZapWriteSyncer     WriteSyncer        // zap日志直接使用的输出目标，优先于OutputPath等输出配置
+	DisableStacktrace  bool               // 是否禁止zap附加stacktrace字段
+	LogrusSortKeys     bool               // logrus JSON格式下是否对嵌套字段按键排序并去重
+	BreakerThreshold   int                // 输出目标连续写入失败多少次后断开，0表示不使用熔断
+	BreakerCooldown    time.Duration      // 熔断后暂停写入输出目标的时长，之后尝试恢复
+	BreakerFallback    io.Writer          // 熔断期间使用的备用输出目标，nil表示标准错误输出
+	MaxFieldBytes      int                // 单个字段值渲染后的最大字节数，0表示不限制
+	MaxFields          int                // 单条日志合并后的最大字段数，0表示不限制
+	Clock              Clock              // 获取日志时间的时钟，nil表示使用系统时钟
+	TimeKey            string             // 结构化输出中时间的键名，默认为time
+	LevelKey           string             // 结构化输出中级别的键名，默认为level
+	NameKey            string             // 结构化输出中日志名称的键名，默认为logger
+	MessageKey         string             // 结构化输出中消息的键名，默认为msg
+	LevelEncoder       LevelEncoder       // 结构化输出中级别的编码函数，nil表示使用级别名称
+	NumericSeverity    bool               // JSON格式（包括gcp和cloudevents）下是否添加数值级别的severity_code字段
+	SeverityCodes      SeverityCodeMap    // 按级别覆盖的数值级别，未覆盖的级别使用syslog级别
+	AlwaysLogAbove     LogLevel           // 不受采样和限流影响的最低级别
+	DurationUnit       time.Duration      // time.Duration字段输出的数值单位，0表示保持后端默认格式
+	RedactPatterns     []RedactPattern    // 对消息和字符串字段值脱敏的正则规则
+	Hostname           bool               // 是否为每条日志添加host字段
+	PID                bool               // 是否为每条日志添加pid字段
+	BuildInfoFields    bool               // 是否为每条日志添加SetBuildInfo设置的构建信息字段
+	CompositesAsJSON   bool               // 文本格式下是否将切片和map字段值输出为紧凑JSON
+	FatalHooks         []func()           // 致命级日志退出程序前执行的钩子
+	ExitFunc           func(code int)     // 致命级日志使用的退出函数，nil表示os.Exit
+	SanitizeNewlines   bool               // 文本格式下是否转义消息、字段键和字段值中的换行符
+	NoTrailingNewline  bool               // 是否去掉每条日志结尾的换行符，用于自行分隔记录的输出目标
+	CallerFields       bool               // 是否添加caller.file、caller.line和caller.func字段
+	WarnStackDepth     int                // 警告级日志stack字段包含的栈帧数，0表示不添加
+	StackPredicate     StackPredicate     // 判断是否为日志添加stacktrace字段，nil表示不添加
+	EntryHooks         []EntryHook        // 每条日志的字段合并完成后调用的钩子
+	RuntimeStats       bool               // 是否为警告及以上级别的日志添加goroutines、heap_alloc和num_gc字段
+	LevelSampling      *LevelSampling     // 按概率采样低级别日志，nil表示不采样
+	FieldRateLimits    []FieldRateLimit   // 按字段值限流的规则
+	SamplingSummary    bool               // Sync时是否输出采样和限流的汇总日志
+	SummaryLevel       LogLevel           // 采样汇总日志的级别
+	UptimeField        bool               // 是否为每条日志添加自日志实例创建或StartTimer以来经过时长的uptime字段
+	NestFields         bool               // JSON格式下是否将自定义字段嵌套在FieldsKey下，默认与msg平铺在顶层
+	FieldsKey          string             // 嵌套自定义字段使用的键名，默认为fields
+	FloatPrecision     *int               // 文本和logfmt格式下浮点数字段保留的小数位数，nil表示使用默认格式
+	TimeFormat         string             // 文本和logfmt格式下时间戳和time.Time字段值的格式，为空时使用各格式的默认格式
+	BytesEncoding      string             // 文本和logfmt格式下[]byte字段值的编码方式（base64/hex），默认为base64
+	CloudEventsSource  string             // cloudevents格式的事件来源，为空时使用日志名称
+	CloudEventsType    string             // cloudevents格式的事件类型，为空时使用DefaultCloudEventsType
+	GCPProjectID       string             // gcp格式的项目ID，用于生成完整的追踪名称
+	CrashDumpPath      string             // 恐慌级日志的崩溃转储文件路径，为空时不写入
+	SingleLineStack    bool               // 是否将stack和stacktrace字段中的堆栈合并为一行
+	HeartbeatInterval  time.Duration      // 心跳日志的输出间隔，0表示不输出
+	HeartbeatMessage   string             // 心跳日志的消息，为空时使用DefaultHeartbeatMessage
 }
 
 // WithLevel 设置日志级别
@@ -159,6 +268,20 @@ func WithOutputPath(path string) Option {
 	}
 }
 
+// WithOutputPaths 设置多个日志输出路径，日志同时写入所有路径，支持stdout和stderr
+func WithOutputPaths(paths ...string) Option {
+	return func(opt *LoggerOptions) {
+		opt.OutputPaths = paths
+	}
+}
+
+// WithWriter 设置自定义输出目标，未设置WithOutputPaths时代替OutputPath，否则同时写入
+func WithWriter(w io.Writer) Option {
+	return func(opt *LoggerOptions) {
+		opt.Writer = w
+	}
+}
+
 // WithConfig 设置额外配置
 func WithConfig(config map[string]interface{}) Option {
 	return func(opt *LoggerOptions) {
@@ -200,3 +323,519 @@ func WithMaxMessageSize(size int) Option {
 		opt.MaxMessageSize = size
 	}
 }
+
+// WithBufferedWriterSize 设置输出缓冲区大小（字节），缓冲的数据在Sync时写入输出
+func WithBufferedWriterSize(size int) Option {
+	return func(opt *LoggerOptions) {
+		opt.BufferedWriterSize = size
+	}
+}
+
+// WithOutputCircuitBreaker 为输出目标添加熔断，连续threshold次写入失败后在cooldown内不再写入该输出，
+// 改为写入备用输出，冷却结束后的下一次写入重新尝试该输出，成功则恢复，失败则再次熔断
+func WithOutputCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(opt *LoggerOptions) {
+		opt.BreakerThreshold = threshold
+		opt.BreakerCooldown = cooldown
+	}
+}
+
+// WithCircuitBreakerFallback 设置熔断期间使用的备用输出目标，默认为标准错误输出
+func WithCircuitBreakerFallback(w io.Writer) Option {
+	return func(opt *LoggerOptions) {
+		opt.BreakerFallback = w
+	}
+}
+
+// WithDevelopment 设置是否为开发模式，开发模式下会检查字段键与保留键的冲突
+func WithDevelopment(development bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.Development = development
+	}
+}
+
+// WithFieldTrace 设置是否记录每个字段的添加位置（文件:行号），同一个键被设置多次时输出field_trace诊断字段列出各个来源，
+// 只在开发模式下生效，用于排查WithFields链中字段被覆盖的问题
+func WithFieldTrace(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.FieldTrace = enabled
+	}
+}
+
+// WithReservedKeyPolicy 设置字段键与保留键（time、level、msg等）冲突时的处理策略，
+// 设置后即使不在开发模式下也会检查，开发模式下默认为ReservedKeyWarn
+func WithReservedKeyPolicy(policy ReservedKeyPolicy) Option {
+	return func(opt *LoggerOptions) {
+		opt.ReservedKeyPolicy = policy
+	}
+}
+
+// WithColor 设置文本格式是否使用ANSI颜色输出级别，默认关闭，JSON格式始终不输出颜色。
+// 该选项不检查输出是否为终端，开发环境的auto日志只在输出到终端时自动开启
+func WithColor(color bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.Color = color
+	}
+}
+
+// LevelColorMap 日志级别到ANSI颜色的映射
+type LevelColorMap map[LogLevel]string
+
+// WithLevelColors 按级别覆盖文本格式输出级别使用的ANSI颜色，颜色可以是完整的转义序列（如"\x1b[35m"）
+// 或SGR参数（如"35"表示品红色），未设置的级别使用默认颜色。只在WithColor(true)且为文本格式时生效
+func WithLevelColors(colors map[LogLevel]string) Option {
+	return func(opt *LoggerOptions) {
+		opt.LevelColors = LevelColorMap(colors)
+	}
+}
+
+// WithEnvironment 设置运行环境（"dev"或"prod"），auto提供者据此选择日志后端
+func WithEnvironment(env string) Option {
+	return func(opt *LoggerOptions) {
+		opt.Environment = env
+	}
+}
+
+// WithTimeKey 设置结构化输出中时间的键名，默认为time
+func WithTimeKey(key string) Option {
+	return func(opt *LoggerOptions) {
+		opt.TimeKey = key
+	}
+}
+
+// WithLevelKey 设置结构化输出中级别的键名，默认为level
+func WithLevelKey(key string) Option {
+	return func(opt *LoggerOptions) {
+		opt.LevelKey = key
+	}
+}
+
+// WithNameKey 设置结构化输出中日志名称的键名，默认为logger
+func WithNameKey(key string) Option {
+	return func(opt *LoggerOptions) {
+		opt.NameKey = key
+	}
+}
+
+// WithMessageKey 设置结构化输出中消息的键名，默认为msg
+func WithMessageKey(key string) Option {
+	return func(opt *LoggerOptions) {
+		opt.MessageKey = key
+	}
+}
+
+// WithClock 设置获取日志时间的时钟，测试中可注入固定时钟使输出时间确定
+func WithClock(clock Clock) Option {
+	return func(opt *LoggerOptions) {
+		opt.Clock = clock
+	}
+}
+
+// WithMaxFieldBytes 设置单个字段值渲染后的最大字节数，超出部分替换为 "...(N bytes truncated)"
+func WithMaxFieldBytes(n int) Option {
+	return func(opt *LoggerOptions) {
+		opt.MaxFieldBytes = n
+	}
+}
+
+// WithMaxFields 设置单条日志合并常量字段、日志实例字段、上下文字段和本次调用的字段后最多保留的字段数，
+// 超出时保留前n个字段并添加记录丢弃个数的fields_truncated字段，用于字段数量受限的下游索引，0表示不限制
+func WithMaxFields(n int) Option {
+	return func(opt *LoggerOptions) {
+		opt.MaxFields = n
+	}
+}
+
+// WithConstFields 设置常量字段，每条日志都会输出且位于其他字段之前，可多次调用追加
+func WithConstFields(fields ...Field) Option {
+	return func(opt *LoggerOptions) {
+		opt.ConstFields = append(opt.ConstFields, fields...)
+	}
+}
+
+// WithDedupeFields 设置是否对同名字段去重，后出现的值覆盖先出现的值
+func WithDedupeFields(dedupe bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.DedupeFields = dedupe
+	}
+}
+
+// WithOmitNilFields 设置是否去除值为nil的字段（包括nil指针、nil map等），
+// 避免文本格式输出x=<nil>而JSON格式输出null这类不一致且无用的字段
+func WithOmitNilFields(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.OmitNilFields = enabled
+	}
+}
+
+// WithFieldAllowlist 设置允许输出的字段键，键不在列表中的字段被丢弃，用于约束日志的字段结构。
+// 嵌套字段按顶层键判断，caller、seq等由选项添加的诊断字段不受影响，开发模式下每个被丢弃的键警告一次
+func WithFieldAllowlist(keys ...string) Option {
+	return func(opt *LoggerOptions) {
+		opt.FieldAllowlist = keys
+	}
+}
+
+// WithCompressedOutput 设置是否以gzip边写边压缩日志文件，文件名自动添加.gz后缀，
+// 该模式下不按大小轮转文件，关闭日志实例时写入完整的gzip结尾
+func WithCompressedOutput(compressed bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.CompressedOutput = compressed
+	}
+}
+
+// WithGoroutineID 设置是否为每条日志添加当前goroutine的ID（goid字段）
+func WithGoroutineID(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.GoroutineID = enabled
+	}
+}
+
+// WithSequence 设置是否为每条日志添加递增的序号（seq字段），派生的日志实例共享同一个计数器
+func WithSequence(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.Sequence = enabled
+	}
+}
+
+// WithGlobalSequence 设置是否为每条日志添加进程内全局递增的序号（gseq字段），所有日志实例共享同一个计数器，
+// 可以据此还原多个并发组件交错输出的日志的先后顺序
+func WithGlobalSequence(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.GlobalSequence = enabled
+	}
+}
+
+// WithAlwaysLogAbove 设置不受采样和限流影响的最低级别，默认为ErrorLevel，
+// 达到该级别的日志总是输出
+func WithAlwaysLogAbove(level LogLevel) Option {
+	return func(opt *LoggerOptions) {
+		opt.AlwaysLogAbove = level
+	}
+}
+
+// WithHostname 设置是否为每条日志添加主机名字段host，主机名只在首次使用时查询一次
+func WithHostname(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.Hostname = enabled
+	}
+}
+
+// WithPID 设置是否为每条日志添加进程ID字段pid
+func WithPID(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.PID = enabled
+	}
+}
+
+// WithBuildInfoFields 设置是否为每条日志添加version、commit和build_time字段，值来自SetBuildInfo或编译时的ldflags，
+// 在创建日志实例时读取，未设置的项不输出
+func WithBuildInfoFields(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.BuildInfoFields = enabled
+	}
+}
+
+// WithProcessInfo 同时设置是否添加host和pid字段
+func WithProcessInfo(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.Hostname = enabled
+		opt.PID = enabled
+	}
+}
+
+// WithFatalHooks 添加致命级日志退出程序前执行的钩子，如刷新指标、关闭数据库连接，
+// 钩子在日志刷新后按注册顺序执行，钩子中的panic会被恢复，可多次调用添加多个
+func WithFatalHooks(hooks ...func()) Option {
+	return func(opt *LoggerOptions) {
+		opt.FatalHooks = append(opt.FatalHooks, hooks...)
+	}
+}
+
+// WithExitFunc 设置致命级日志使用的退出函数，默认为os.Exit，测试中可替换以避免进程退出
+func WithExitFunc(exit func(code int)) Option {
+	return func(opt *LoggerOptions) {
+		opt.ExitFunc = exit
+	}
+}
+
+// LevelSampling 按概率采样的配置，Level及以下级别的日志以Probability的概率输出
+type LevelSampling struct {
+	Level       LogLevel
+	Probability float64
+}
+
+// WithProbabilisticLevel 设置level及以下级别的日志按probability的概率输出，如WithProbabilisticLevel(DebugLevel, 0.05)
+// 只输出5%的调试日志，达到WithAlwaysLogAbove阈值的日志总是输出
+func WithProbabilisticLevel(level LogLevel, probability float64) Option {
+	return func(opt *LoggerOptions) {
+		opt.LevelSampling = &LevelSampling{Level: level, Probability: probability}
+	}
+}
+
+// WithFlattenFields 设置JSON格式下自定义字段是否与msg平铺在顶层（默认），为false时嵌套在WithFieldsKey指定的键下，
+// 便于对接要求固定结构的日志平台
+func WithFlattenFields(flatten bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.NestFields = !flatten
+	}
+}
+
+// WithFieldsKey 设置嵌套自定义字段使用的键名，默认为fields，需配合WithFlattenFields(false)使用
+func WithFieldsKey(key string) Option {
+	return func(opt *LoggerOptions) {
+		opt.FieldsKey = key
+	}
+}
+
+// WithUptimeField 设置是否为每条日志添加uptime字段，值为自日志实例创建（或调用StartTimer）以来经过的时长，
+// 便于关联短时任务中的事件
+func WithUptimeField(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.UptimeField = enabled
+	}
+}
+
+// WithSamplingSummary 开启采样汇总，Sync时如果自上次汇总以来有日志被采样丢弃或限流抑制，
+// 以level级别输出一条包含sampled和suppressed字段的汇总日志，汇总日志本身不受采样和限流影响，
+// level高于ErrorLevel时按ErrorLevel输出，避免退出程序或触发panic
+func WithSamplingSummary(level LogLevel) Option {
+	return func(opt *LoggerOptions) {
+		if level > ErrorLevel {
+			level = ErrorLevel
+		}
+		opt.SamplingSummary = true
+		opt.SummaryLevel = level
+	}
+}
+
+// WithFieldRateLimit 按字段值限流，携带相同key值的日志（如error_code=TIMEOUT）每秒最多输出maxPerSecond条，
+// 不同的值独立计数，被抑制的条数按值汇总在Stats().FieldSuppressed中，可多次调用为多个键限流，
+// 达到WithAlwaysLogAbove阈值的日志不受影响
+func WithFieldRateLimit(key string, maxPerSecond int) Option {
+	return func(opt *LoggerOptions) {
+		opt.FieldRateLimits = append(opt.FieldRateLimits, FieldRateLimit{Key: key, MaxPerSecond: maxPerSecond})
+	}
+}
+
+// WithCallerFields 设置是否以caller.file、caller.line和caller.func三个独立字段输出调用位置，
+// 便于按函数名过滤日志，与zap输出的字符串形式caller互不影响
+func WithCallerFields(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.CallerFields = enabled
+	}
+}
+
+// WithWarnStackDepth 为警告级日志添加stack字段，包含调用位置开始的depth个栈帧，
+// 用于定位弃用路径等警告的来源，开销远小于完整的堆栈，depth为0时不添加
+func WithWarnStackDepth(depth int) Option {
+	return func(opt *LoggerOptions) {
+		opt.WarnStackDepth = depth
+	}
+}
+
+// WithRuntimeStats 设置是否为警告及以上级别的日志添加运行时统计字段：goroutine数量（goroutines）、
+// 堆内存分配字节数（heap_alloc）和GC次数（num_gc），便于将错误与资源压力关联。
+// 读取内存统计有一定开销，低级别日志不添加
+func WithRuntimeStats(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.RuntimeStats = enabled
+	}
+}
+
+// WithSanitizeNewlines 设置文本格式下是否将消息、字段键和字段值中的\r、\n转义，防止伪造日志行，默认开启，
+// JSON和logfmt格式本身会转义换行符，不受此选项影响
+func WithSanitizeNewlines(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.SanitizeNewlines = enabled
+	}
+}
+
+// WithTrailingNewline 设置每条日志是否以换行符结尾，默认开启，每条记录恰好以一个\n结尾，
+// 便于按行读取的NDJSON工具处理；输出目标自行添加换行符或分隔记录时可以关闭，对所有格式和适配器生效
+func WithTrailingNewline(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.NoTrailingNewline = !enabled
+	}
+}
+
+// WithComposeCompositesAsJSON 设置文本和logfmt格式下是否将切片、数组和map字段值输出为紧凑JSON，
+// 如tags=["a","b"]，而不是Go语法的[a b]，标量值不受影响
+func WithComposeCompositesAsJSON(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.CompositesAsJSON = enabled
+	}
+}
+
+// WithFloatPrecision 设置文本和logfmt格式下浮点数字段保留的小数位数，避免%v输出的科学计数法和多余的小数位，
+// digits为负数时使用不带科学计数法的最短表示，JSON格式保留完整精度的数值
+func WithFloatPrecision(digits int) Option {
+	return func(opt *LoggerOptions) {
+		opt.FloatPrecision = &digits
+	}
+}
+
+// WithTimeFormat 设置文本和logfmt格式下时间戳和time.Time字段值的格式（time.Format的layout），
+// JSON格式中的time.Time字段值始终为RFC3339格式
+func WithTimeFormat(layout string) Option {
+	return func(opt *LoggerOptions) {
+		opt.TimeFormat = layout
+	}
+}
+
+// WithBytesEncoding 设置文本和logfmt格式下[]byte字段值的编码方式，支持BytesEncodingBase64（默认）和BytesEncodingHex，
+// 避免输出为[104 105]形式的数字切片，JSON格式始终与encoding/json一致使用base64
+func WithBytesEncoding(encoding string) Option {
+	return func(opt *LoggerOptions) {
+		opt.BytesEncoding = encoding
+	}
+}
+
+// WithCloudEventsSource 设置cloudevents格式输出的事件来源（source属性），默认使用日志名称
+func WithCloudEventsSource(source string) Option {
+	return func(opt *LoggerOptions) {
+		opt.CloudEventsSource = source
+	}
+}
+
+// WithCloudEventsType 设置cloudevents格式输出的事件类型（type属性），默认为DefaultCloudEventsType
+func WithCloudEventsType(eventType string) Option {
+	return func(opt *LoggerOptions) {
+		opt.CloudEventsType = eventType
+	}
+}
+
+// WithGCPProjectID 设置gcp格式输出使用的GCP项目ID，trace_id字段据此输出为projects/项目ID/traces/追踪ID，
+// Cloud Logging只有在完整形式下才能将日志与Cloud Trace关联
+func WithGCPProjectID(projectID string) Option {
+	return func(opt *LoggerOptions) {
+		opt.GCPProjectID = projectID
+	}
+}
+
+// StackPredicate 条件堆栈判断函数，msg为未脱敏的日志消息，fields为合并处理后的字段，不应修改
+type StackPredicate func(level LogLevel, msg string, fields []Field) bool
+
+// WithConditionalStacktrace 设置只在predicate返回true时为日志添加调用方的stacktrace字段（最多32个栈帧），
+// 如消息包含deadlock或带有critical=true字段的日志，避免为每条错误日志付出获取堆栈的开销。
+// 开启WithSingleLineStacktrace时堆栈合并为一行
+func WithConditionalStacktrace(predicate func(level LogLevel, msg string, fields []Field) bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.StackPredicate = predicate
+	}
+}
+
+// EntryHook 日志钩子，在每条日志的字段合并处理完成后、输出之前调用，ctx为日志使用的上下文，msg为脱敏后的消息，
+// fields为展开分组后的字段，钩子不应修改fields，也不应在返回后继续持有
+type EntryHook func(ctx context.Context, level LogLevel, msg string, fields []Field)
+
+// WithEntryHook 添加日志钩子，如将日志同时记录为追踪的span事件，可多次调用添加多个，按添加顺序调用
+func WithEntryHook(hook EntryHook) Option {
+	return func(opt *LoggerOptions) {
+		opt.EntryHooks = append(opt.EntryHooks, hook)
+	}
+}
+
+// WithSingleLineStacktrace 设置是否将堆栈合并为一行：警告级日志的stack字段以及键为stack或stacktrace的字符串字段中，
+// 函数名与文件位置以\t连接，栈帧之间以 | 分隔，保证每条日志只占一行，便于Promtail等按行采集的工具处理
+func WithSingleLineStacktrace(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.SingleLineStack = enabled
+	}
+}
+
+// WithHeartbeat 启动后台goroutine，每隔interval输出一条消息为msg的信息级心跳日志，带有从1开始递增的heartbeat字段，
+// 为长时间空闲的工作进程提供存活证据。goroutine在日志实例Close时停止，msg为空时使用DefaultHeartbeatMessage
+func WithHeartbeat(interval time.Duration, msg string) Option {
+	return func(opt *LoggerOptions) {
+		opt.HeartbeatInterval = interval
+		opt.HeartbeatMessage = msg
+	}
+}
+
+// WithCrashDumpPath 设置崩溃转储文件路径，Panic在触发panic之前会将完整的日志记录和所有goroutine的堆栈
+// 同步追加到该文件，即使主输出带缓冲或已丢失，崩溃现场也能保留
+func WithCrashDumpPath(path string) Option {
+	return func(opt *LoggerOptions) {
+		opt.CrashDumpPath = path
+	}
+}
+
+// RedactPattern 脱敏规则，将匹配Pattern的内容替换为Replacement
+type RedactPattern struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// WithRedactPattern 添加脱敏规则，对日志消息和字符串字段值中匹配re的内容进行替换，
+// replacement支持regexp.ReplaceAllString的$1等引用，可多次调用添加多个
+func WithRedactPattern(re *regexp.Regexp, replacement string) Option {
+	return func(opt *LoggerOptions) {
+		opt.RedactPatterns = append(opt.RedactPatterns, RedactPattern{Pattern: re, Replacement: replacement})
+	}
+}
+
+// WithDurationUnit 设置time.Duration字段的输出单位，字段值输出为该单位下的数值，
+// 键名添加单位后缀，如WithDurationUnit(time.Millisecond)时latency输出为latency_ms
+func WithDurationUnit(unit time.Duration) Option {
+	return func(opt *LoggerOptions) {
+		opt.DurationUnit = unit
+	}
+}
+
+// LevelEncoder 日志级别编码函数，返回值作为结构化输出中级别键的值
+type LevelEncoder func(level LogLevel) interface{}
+
+// WithLevelEncoder 设置结构化输出中日志级别的表示方式，如GCP的severity或syslog数值级别
+func WithLevelEncoder(encoder LevelEncoder) Option {
+	return func(opt *LoggerOptions) {
+		opt.LevelEncoder = encoder
+	}
+}
+
+// SeverityCodeMap 日志级别到数值级别的映射
+type SeverityCodeMap map[LogLevel]int
+
+// WithNumericSeverity 设置JSON格式（包括gcp和cloudevents）下是否在级别名称之外添加整数的severity_code字段，便于按数值级别索引日志的SIEM系统使用。
+// logrus只在json格式下输出JSON，其gcp和cloudevents格式为文本输出，不添加该字段。
+// 默认使用syslog级别：DEBUG=7、INFO=6、WARN=4、ERROR=3、FATAL=2、PANIC=1，可以通过WithSeverityCodes修改
+func WithNumericSeverity(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.NumericSeverity = enabled
+	}
+}
+
+// WithSeverityCodes 按级别覆盖severity_code字段的数值，未覆盖的级别仍使用syslog级别
+func WithSeverityCodes(codes map[LogLevel]int) Option {
+	return func(opt *LoggerOptions) {
+		opt.SeverityCodes = SeverityCodeMap(codes)
+	}
+}
+
+// ErrorFormatter 错误字段格式化函数，返回值作为字段值输出
+type ErrorFormatter func(err error) interface{}
+
+// WithErrorFormatter 设置错误字段的格式化方式，作用于WithError和值为error的字段
+func WithErrorFormatter(formatter ErrorFormatter) Option {
+	return func(opt *LoggerOptions) {
+		opt.ErrorFormatter = formatter
+	}
+}
+
+// MarshalFunc 字段值转换函数，返回值代替原值交给编码器输出
+type MarshalFunc func(v interface{}) interface{}
+
+// MarshalerMap 按字段值类型索引的转换函数
+type MarshalerMap map[reflect.Type]MarshalFunc
+
+// WithMarshaler 为类型t注册转换函数，值的动态类型恰好为t的字段（包括嵌套字段）在编码前由marshal转换，
+// 可以控制Money、uuid.UUID等自定义类型在日志中的输出形式，而无需这些类型实现日志相关的接口。
+// 同一类型多次注册时后注册的生效
+func WithMarshaler(t reflect.Type, marshal MarshalFunc) Option {
+	return func(opt *LoggerOptions) {
+		if opt.Marshalers == nil {
+			opt.Marshalers = make(MarshalerMap)
+		}
+		opt.Marshalers[t] = marshal
+	}
+}
@@ -1,12 +1,15 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"github.com/sirupsen/logrus"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // LogrusLogger logrus日志库适配器
@@ -16,20 +19,35 @@ type LogrusLogger struct {
 	fields []Field
 	ctx    context.Context
 	name   string
+	format string
+	sites  []string // 开启字段追踪时各字段的添加位置
+	core   *loggerCore
+
+	wrapped bool // 是否包装了已有的logrus实例，包装的实例由调用方管理输出
+}
+
+// WithLogrusSortKeys 设置logrus JSON格式下是否对嵌套字段同样按键排序并去重（同名键后出现的值覆盖先出现的），
+// 顶层字段由encoding/json按键排序，开启后整条日志的键顺序都是确定的，便于测试中比较输出，只对logrus日志生效
+func WithLogrusSortKeys(enabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.LogrusSortKeys = enabled
+	}
 }
 
 // NewLogrusLogger 创建logrus日志实例
 func NewLogrusLogger(name string, opts ...Option) *LogrusLogger {
 	options := &LoggerOptions{
-		Level:          InfoLevel,
-		Format:         "text",
-		OutputPath:     "stdout",
-		MaxLogSize:     100,                // 默认100MB
-		MaxLogAge:      7 * 24 * time.Hour, // 默认7天
-		MaxLogFiles:    10,                 // 默认10个文件
-		CompressLogs:   false,              // 默认不压缩
-		MaxMessageSize: 0,                  // 默认不限制
-		Config:         make(map[string]interface{}),
+		Level:            InfoLevel,
+		Format:           "text",
+		OutputPath:       "stdout",
+		MaxLogSize:       100,                // 默认100MB
+		MaxLogAge:        7 * 24 * time.Hour, // 默认7天
+		MaxLogFiles:      10,                 // 默认10个文件
+		CompressLogs:     false,              // 默认不压缩
+		MaxMessageSize:   0,                  // 默认不限制
+		AlwaysLogAbove:   ErrorLevel,         // 默认错误及以上级别不受采样和限流影响
+		SanitizeNewlines: true,               // 默认转义文本格式中的换行符
+		Config:           make(map[string]interface{}),
 	}
 
 	for _, opt := range opts {
@@ -39,78 +57,98 @@ func NewLogrusLogger(name string, opts ...Option) *LogrusLogger {
 	// 创建logrus实例
 	logger := logrus.New()
 
-	// 设置日志级别
-	logrusLevel := logrus.InfoLevel
-	switch options.Level {
-	case DebugLevel:
-		logrusLevel = logrus.DebugLevel
-	case InfoLevel:
-		logrusLevel = logrus.InfoLevel
-	case WarnLevel:
-		logrusLevel = logrus.WarnLevel
-	case ErrorLevel:
-		logrusLevel = logrus.ErrorLevel
-	case FatalLevel:
-		logrusLevel = logrus.FatalLevel
-	case PanicLevel:
-		logrusLevel = logrus.PanicLevel
-	}
-	logger.SetLevel(logrusLevel)
+	// 级别由门面过滤，logrus输出所有级别，以支持上下文级别覆盖
+	logger.SetLevel(logrus.DebugLevel)
 
 	// 设置输出格式
 	if options.Format == "json" {
-		logger.SetFormatter(&logrus.JSONFormatter{
+		keys := keysFromOptions(options).withDefaults()
+		formatter := &logrus.JSONFormatter{
 			TimestampFormat: time.RFC3339,
-		})
+			DataKey:         nestedFieldsKey(options),
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime:  keys.TimeKey,
+				logrus.FieldKeyLevel: keys.LevelKey,
+				logrus.FieldKeyMsg:   keys.MessageKey,
+			},
+		}
+		if options.LevelEncoder != nil {
+			logger.SetFormatter(&logrusLevelFormatter{
+				JSONFormatter: formatter,
+				levelKey:      keys.LevelKey,
+				encoder:       options.LevelEncoder,
+			})
+		} else {
+			logger.SetFormatter(formatter)
+		}
 	} else {
 		logger.SetFormatter(&logrus.TextFormatter{
 			FullTimestamp:   true,
-			TimestampFormat: "2006-01-02 15:04:05.000",
+			TimestampFormat: textTimeLayout(options),
 		})
 	}
 
 	// 设置输出目标
-	if options.OutputPath != "stdout" {
-		// 使用lumberjack进行日志轮转
-		lumberjackLogger := &lumberjack.Logger{
-			Filename:   options.OutputPath,
-			MaxSize:    int(options.MaxLogSize),             // MB
-			MaxAge:     int(options.MaxLogAge.Hours() / 24), // 天
-			MaxBackups: options.MaxLogFiles,
-			Compress:   options.CompressLogs,
-		}
-		logger.SetOutput(lumberjackLogger)
-	}
+	core := newLoggerCore(options)
+	// logrus只在json格式下使用JSON格式化器，gcp和cloudevents格式同样按文本输出
+	core.jsonOutput = options.Format == "json"
+	logger.SetOutput(core.writer())
+	logger.ExitFunc = core.exit
 
-	return &LogrusLogger{
+	l := &LogrusLogger{
 		logger: logger,
 		level:  options.Level,
 		fields: make([]Field, 0),
 		ctx:    context.Background(),
 		name:   name,
+		format: options.Format,
+		core:   core,
 	}
+
+	l.core.startHeartbeat(func(count uint64) {
+		l.Info(l.core.heartbeatMessage(), Field{Key: "heartbeat", Value: count})
+	})
+	return l
+}
+
+// NewLoggerFromLogrus 包装已有的logrus日志实例，格式化器、输出目标和钩子仍由该实例负责，
+// 门面级别取自该实例的级别，JSON格式化器保留嵌套字段，其他格式化器展开为点分隔的键。
+// 致命级日志写入后先刷新该实例的输出，再执行WithFatalHooks设置的钩子，
+// 未设置WithExitFunc时通过该实例的Exit退出，保留其ExitFunc和退出处理函数
+func NewLoggerFromLogrus(name string, l *logrus.Logger, opts ...Option) *LogrusLogger {
+	if l == nil {
+		l = logrus.StandardLogger()
+	}
+	format := "text"
+	switch l.Formatter.(type) {
+	case *logrus.JSONFormatter, *logrusLevelFormatter:
+		format = "json"
+	}
+	options := wrappedLoggerOptions(fromLogrusLevel(l.GetLevel()), format, opts)
+	if options.ExitFunc == nil {
+		options.ExitFunc = l.Exit
+	}
+
+	ll := &LogrusLogger{
+		logger:  l,
+		level:   options.Level,
+		fields:  make([]Field, 0),
+		ctx:     context.Background(),
+		name:    name,
+		format:  options.Format,
+		core:    newLoggerCoreWithOutput(options, io.Discard),
+		wrapped: true,
+	}
+
+	ll.core.startHeartbeat(func(count uint64) {
+		ll.Info(ll.core.heartbeatMessage(), Field{Key: "heartbeat", Value: count})
+	})
+	return ll
 }
 
 // SetLevel 设置日志级别
 func (l *LogrusLogger) SetLevel(level LogLevel) {
 	l.level = level
-	// 更新logrus的日志级别
-	logrusLevel := logrus.InfoLevel
-	switch level {
-	case DebugLevel:
-		logrusLevel = logrus.DebugLevel
-	case InfoLevel:
-		logrusLevel = logrus.InfoLevel
-	case WarnLevel:
-		logrusLevel = logrus.WarnLevel
-	case ErrorLevel:
-		logrusLevel = logrus.ErrorLevel
-	case FatalLevel:
-		logrusLevel = logrus.FatalLevel
-	case PanicLevel:
-		logrusLevel = logrus.PanicLevel
-	}
-	l.logger.SetLevel(logrusLevel)
 }
 
 // GetLevel 获取当前日志级别
@@ -118,113 +156,218 @@ func (l *LogrusLogger) GetLevel() LogLevel {
 	return l.level
 }
 
-// toLogrusFields 将自定义字段转换为logrus字段
-func (l *LogrusLogger) toLogrusFields(fields []Field) logrus.Fields {
+// logrusFields 将合并后的字段转换为logrus字段
+func (l *LogrusLogger) logrusFields(allFields []Field) logrus.Fields {
 	logrusFields := make(logrus.Fields)
 
-	// 添加已有的字段
-	for _, field := range l.fields {
-		logrusFields[field.Key] = field.Value
+	// 文本格式下将嵌套字段展开为点分隔的键，JSON格式保留嵌套对象
+	if l.format != "json" {
+		for _, field := range flattenFields(allFields) {
+			// 按统一的文本规则格式化，logrus对字符串值只做必要的加引号处理，但原样输出键
+			options := l.core.options
+			key := field.Key
+			if options.SanitizeNewlines {
+				key = newlineEscaper.Replace(key)
+			}
+			logrusFields[key] = formatTextValue(field.Value, options.CompositesAsJSON, options.FloatPrecision, options.BytesEncoding, textTimeLayout(options))
+		}
+		return logrusFields
 	}
 
-	// 添加新的字段
-	for _, field := range fields {
-		logrusFields[field.Key] = field.Value
+	// logrus.Fields是map，同名字段后出现的覆盖先出现的
+	for _, field := range allFields {
+		if l.core.options.LogrusSortKeys {
+			logrusFields[field.Key] = sortedJSONValue(field.Value)
+			continue
+		}
+		logrusFields[field.Key] = jsonValue(field.Value)
 	}
 
 	return logrusFields
 }
 
+// sortedJSONValue 将嵌套字段递归转换为map，使encoding/json按键排序输出，同名键后出现的值覆盖先出现的
+func sortedJSONValue(value interface{}) interface{} {
+	group, ok := value.(fieldGroup)
+	if !ok {
+		return jsonValue(value)
+	}
+	m := make(map[string]interface{}, len(group))
+	for _, field := range group {
+		m[field.Key] = sortedJSONValue(field.Value)
+	}
+	return m
+}
+
+// entry 创建带有字段和时间的logrus日志记录
+func (l *LogrusLogger) entry(ctx context.Context, level LogLevel, msg string, fields []Field) *logrus.Entry {
+	// logrus.Fields复制了字段值，合并字段使用的缓冲区可以立即复用
+	merged := getEntry()
+	defer putEntry(merged)
+	merged.Fields = l.core.appendMergedFields(merged.Fields, level, msg, l.fields, l.sites, ctx, fields)
+	return l.logger.WithFields(l.logrusFields(merged.Fields)).WithTime(l.core.now())
+}
+
 // Debug 输出调试级日志
 func (l *LogrusLogger) Debug(msg string, fields ...Field) {
-	if l.level <= DebugLevel {
-		l.logger.WithFields(l.toLogrusFields(fields)).Debug(msg)
+	if levelEnabled(l.level, l.ctx, DebugLevel) && l.core.allow(DebugLevel, l.fields, l.ctx, fields) {
+		l.entry(l.ctx, DebugLevel, msg, fields).Debug(l.core.redactMessage(msg))
 	}
 }
 
 // Debugf 输出格式化的调试级日志
 func (l *LogrusLogger) Debugf(format string, args ...interface{}) {
-	if l.level <= DebugLevel {
-		l.logger.Debugf(format, args...)
+	if levelEnabled(l.level, l.ctx, DebugLevel) && l.core.allow(DebugLevel, l.fields, l.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		l.entry(l.ctx, DebugLevel, msg, nil).Debug(l.core.redactMessage(msg))
 	}
 }
 
 // Info 输出信息级日志
 func (l *LogrusLogger) Info(msg string, fields ...Field) {
-	if l.level <= InfoLevel {
-		l.logger.WithFields(l.toLogrusFields(fields)).Info(msg)
+	if levelEnabled(l.level, l.ctx, InfoLevel) && l.core.allow(InfoLevel, l.fields, l.ctx, fields) {
+		l.entry(l.ctx, InfoLevel, msg, fields).Info(l.core.redactMessage(msg))
 	}
 }
 
 // Infof 输出格式化的信息级日志
 func (l *LogrusLogger) Infof(format string, args ...interface{}) {
-	if l.level <= InfoLevel {
-		l.logger.Infof(format, args...)
+	if levelEnabled(l.level, l.ctx, InfoLevel) && l.core.allow(InfoLevel, l.fields, l.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		l.entry(l.ctx, InfoLevel, msg, nil).Info(l.core.redactMessage(msg))
 	}
 }
 
 // Warn 输出警告级日志
 func (l *LogrusLogger) Warn(msg string, fields ...Field) {
-	if l.level <= WarnLevel {
-		l.logger.WithFields(l.toLogrusFields(fields)).Warn(msg)
+	if levelEnabled(l.level, l.ctx, WarnLevel) && l.core.allow(WarnLevel, l.fields, l.ctx, fields) {
+		l.entry(l.ctx, WarnLevel, msg, fields).Warn(l.core.redactMessage(msg))
 	}
 }
 
 // Warnf 输出格式化的警告级日志
 func (l *LogrusLogger) Warnf(format string, args ...interface{}) {
-	if l.level <= WarnLevel {
-		l.logger.Warnf(format, args...)
+	if levelEnabled(l.level, l.ctx, WarnLevel) && l.core.allow(WarnLevel, l.fields, l.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		l.entry(l.ctx, WarnLevel, msg, nil).Warn(l.core.redactMessage(msg))
 	}
 }
 
 // Error 输出错误级日志
 func (l *LogrusLogger) Error(msg string, fields ...Field) {
-	if l.level <= ErrorLevel {
-		l.logger.WithFields(l.toLogrusFields(fields)).Error(msg)
+	if levelEnabled(l.level, l.ctx, ErrorLevel) && l.core.allow(ErrorLevel, l.fields, l.ctx, fields) {
+		l.entry(l.ctx, ErrorLevel, msg, fields).Error(l.core.redactMessage(msg))
 	}
 }
 
 // Errorf 输出格式化的错误级日志
 func (l *LogrusLogger) Errorf(format string, args ...interface{}) {
-	if l.level <= ErrorLevel {
-		l.logger.Errorf(format, args...)
+	if levelEnabled(l.level, l.ctx, ErrorLevel) && l.core.allow(ErrorLevel, l.fields, l.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		l.entry(l.ctx, ErrorLevel, msg, nil).Error(l.core.redactMessage(msg))
 	}
 }
 
 // Fatal 输出致命级日志并退出程序
 func (l *LogrusLogger) Fatal(msg string, fields ...Field) {
-	if l.level <= FatalLevel {
-		l.logger.WithFields(l.toLogrusFields(fields)).Fatal(msg)
-		os.Exit(1)
+	if levelEnabled(l.level, l.ctx, FatalLevel) && l.core.allow(FatalLevel, l.fields, l.ctx, fields) {
+		l.fatal(l.entry(l.ctx, FatalLevel, msg, fields), l.core.redactMessage(msg))
 	}
 }
 
 // Fatalf 输出格式化的致命级日志并退出程序
 func (l *LogrusLogger) Fatalf(format string, args ...interface{}) {
-	if l.level <= FatalLevel {
-		l.logger.Fatalf(format, args...)
-		os.Exit(1)
+	if levelEnabled(l.level, l.ctx, FatalLevel) && l.core.allow(FatalLevel, l.fields, l.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		l.fatal(l.entry(l.ctx, FatalLevel, msg, nil), l.core.redactMessage(msg))
 	}
 }
 
+// fatal 输出致命级日志并退出程序。包装的logrus实例不使用其ExitFunc直接退出，
+// 而是刷新其输出后通过日志核心执行门面的致命钩子再退出
+func (l *LogrusLogger) fatal(entry *logrus.Entry, msg string) {
+	if !l.wrapped {
+		entry.Fatal(msg)
+		return
+	}
+	entry.Log(logrus.FatalLevel, msg)
+	syncOutput(l.logger.Out)
+	l.core.exit(1)
+}
+
 // Panic 输出恐慌级日志并触发panic
 func (l *LogrusLogger) Panic(msg string, fields ...Field) {
-	if l.level <= PanicLevel {
-		l.logger.WithFields(l.toLogrusFields(fields)).Panic(msg)
+	if levelEnabled(l.level, l.ctx, PanicLevel) && l.core.allow(PanicLevel, l.fields, l.ctx, fields) {
+		l.logPanic(l.ctx, msg, fields)
 	}
 }
 
 // Panicf 输出格式化的恐慌级日志并触发panic
 func (l *LogrusLogger) Panicf(format string, args ...interface{}) {
-	if l.level <= PanicLevel {
-		l.logger.Panicf(format, args...)
+	if levelEnabled(l.level, l.ctx, PanicLevel) && l.core.allow(PanicLevel, l.fields, l.ctx, nil) {
+		l.logPanic(l.ctx, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// logPanic 输出恐慌级日志并触发panic，配置了崩溃转储文件时先写入崩溃转储
+func (l *LogrusLogger) logPanic(ctx context.Context, msg string, fields []Field) {
+	allFields := l.core.mergeFields(PanicLevel, msg, l.fields, l.sites, ctx, fields)
+	msg = l.core.redactMessage(msg)
+	if l.core.crashDumpEnabled() {
+		l.core.writeCrashDump(l.core.crashEntry(l.name, msg, allFields))
+	}
+	l.logger.WithFields(l.logrusFields(allFields)).WithTime(l.core.now()).Panic(msg)
+}
+
+// DebugCtx 使用ctx中提取的字段输出调试级日志
+func (l *LogrusLogger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(l.level, ctx, DebugLevel) && l.core.allow(DebugLevel, l.fields, ctx, fields) {
+		l.entry(ctx, DebugLevel, msg, fields).Debug(l.core.redactMessage(msg))
+	}
+}
+
+// InfoCtx 使用ctx中提取的字段输出信息级日志
+func (l *LogrusLogger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(l.level, ctx, InfoLevel) && l.core.allow(InfoLevel, l.fields, ctx, fields) {
+		l.entry(ctx, InfoLevel, msg, fields).Info(l.core.redactMessage(msg))
+	}
+}
+
+// WarnCtx 使用ctx中提取的字段输出警告级日志
+func (l *LogrusLogger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(l.level, ctx, WarnLevel) && l.core.allow(WarnLevel, l.fields, ctx, fields) {
+		l.entry(ctx, WarnLevel, msg, fields).Warn(l.core.redactMessage(msg))
+	}
+}
+
+// ErrorCtx 使用ctx中提取的字段输出错误级日志
+func (l *LogrusLogger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(l.level, ctx, ErrorLevel) && l.core.allow(ErrorLevel, l.fields, ctx, fields) {
+		l.entry(ctx, ErrorLevel, msg, fields).Error(l.core.redactMessage(msg))
+	}
+}
+
+// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+func (l *LogrusLogger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(l.level, ctx, FatalLevel) && l.core.allow(FatalLevel, l.fields, ctx, fields) {
+		l.fatal(l.entry(ctx, FatalLevel, msg, fields), l.core.redactMessage(msg))
+	}
+}
+
+// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+func (l *LogrusLogger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(l.level, ctx, PanicLevel) && l.core.allow(PanicLevel, l.fields, ctx, fields) {
+		l.logPanic(ctx, msg, fields)
 	}
 }
 
 // WithFields 添加字段到日志
 func (l *LogrusLogger) WithFields(fields ...Field) Logger {
 	newLogger := *l
+	newLogger.fields = make([]Field, 0, len(l.fields)+len(fields))
+	newLogger.fields = append(newLogger.fields, l.fields...)
 	newLogger.fields = append(newLogger.fields, fields...)
+	newLogger.sites = l.core.traceFields(l.sites, fields)
 	return &newLogger
 }
 
@@ -233,6 +376,31 @@ func (l *LogrusLogger) WithField(key string, value interface{}) Logger {
 	return l.WithFields(Field{Key: key, Value: value})
 }
 
+// Clone 以新的名称复制日志实例，保留级别、字段、上下文和输出配置
+func (l *LogrusLogger) Clone(name string) Logger {
+	newLogger := *l
+	newLogger.name = name
+	newLogger.fields = append([]Field(nil), l.fields...)
+	newLogger.sites = append([]string(nil), l.sites...)
+	newLogger.core = l.core.clone()
+
+	// 包装的logrus实例由调用方管理输出，只有门面创建的实例需要写入新核心
+	if !l.wrapped {
+		logger := logrus.New()
+		logger.SetLevel(l.logger.GetLevel())
+		logger.SetFormatter(l.logger.Formatter)
+		logger.SetOutput(newLogger.core.writer())
+		logger.ExitFunc = newLogger.core.exit
+		newLogger.logger = logger
+	}
+	return &newLogger
+}
+
+// WithStruct 将结构体的导出字段展开为 prefix.FieldName 形式的字段添加到日志
+func (l *LogrusLogger) WithStruct(prefix string, v interface{}) Logger {
+	return l.WithFields(StructFields(prefix, v)...)
+}
+
 // WithContext 添加上下文到日志
 func (l *LogrusLogger) WithContext(ctx context.Context) Logger {
 	newLogger := *l
@@ -240,8 +408,11 @@ func (l *LogrusLogger) WithContext(ctx context.Context) Logger {
 	return &newLogger
 }
 
-// WithError 添加错误信息到日志
+// WithError 添加错误信息到日志，err为nil时返回当前日志实例
 func (l *LogrusLogger) WithError(err error) Logger {
+	if err == nil {
+		return l
+	}
 	return l.WithField("error", err)
 }
 
@@ -280,10 +451,66 @@ func (l *LogrusLogger) IsPanicEnabled() bool {
 	return l.level <= PanicLevel
 }
 
-// Sync 刷新日志缓冲区
+// IsEnabled 检查level级别是否启用，与实际输出日志时一样考虑上下文中的级别覆盖
+func (l *LogrusLogger) IsEnabled(level LogLevel) bool {
+	return levelEnabled(l.level, l.ctx, level)
+}
+
+// SetOutput 替换日志输出目标，派生的日志实例同时生效，原输出目标不会被关闭
+func (l *LogrusLogger) SetOutput(w io.Writer) {
+	l.core.setOutput(w)
+}
+
+// Stats 获取日志输出统计
+func (l *LogrusLogger) Stats() LoggerStats {
+	stats := l.core.stats.snapshot()
+	stats.FieldSuppressed = l.core.fieldSuppressed()
+	return stats
+}
+
+// ResetSampling 清空按字段值限流的计数窗口，被抑制的消息可以立即再次输出，派生的日志实例同时生效。
+// 适合在每个请求或测试开始时调用，避免上一阶段的限流状态影响下一阶段，统计中累计的抑制条数不受影响
+func (l *LogrusLogger) ResetSampling() {
+	l.core.resetSampling()
+}
+
+// StartTimer 将uptime字段的起始时间重置为当前时间，派生的日志实例同时生效
+func (l *LogrusLogger) StartTimer() {
+	l.core.startTimer()
+}
+
+// Describe 获取日志实例的有效配置信息
+func (l *LogrusLogger) Describe() LoggerInfo {
+	return l.core.describe("logrus", l.name, l.level)
+}
+
+// Sync 输出采样汇总并刷新日志缓冲区
 func (l *LogrusLogger) Sync() error {
-	// logrus没有Sync方法，返回nil
-	return nil
+	if level, fields, ok := l.core.samplingSummary(); ok && l.level <= level {
+		entry := l.entry(l.ctx, level, samplingSummaryMessage, fields)
+		switch level {
+		case DebugLevel:
+			entry.Debug(samplingSummaryMessage)
+		case InfoLevel:
+			entry.Info(samplingSummaryMessage)
+		case WarnLevel:
+			entry.Warn(samplingSummaryMessage)
+		default:
+			entry.Error(samplingSummaryMessage)
+		}
+	}
+
+	// logrus本身不刷新标准输出，这里保持一致
+	if l.core.currentOutput() == os.Stdout {
+		return nil
+	}
+	return l.core.sync()
+}
+
+// Close 刷新并关闭日志输出，标准输出不会被关闭
+func (l *LogrusLogger) Close() error {
+	l.Sync()
+	return l.core.close()
 }
 
 // LogrusLoggerProvider logrus日志提供者
@@ -329,3 +556,51 @@ func (p *LogrusLoggerProvider) CreateWithConfig(name string, config map[string]i
 		WithConfig(config),
 	)
 }
+
+// logrusLevelFormatter 使用自定义级别编码函数替换logrus JSON输出中的级别
+type logrusLevelFormatter struct {
+	*logrus.JSONFormatter
+	levelKey string
+	encoder  LevelEncoder
+}
+
+// Format 格式化日志记录
+func (f *logrusLevelFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data, err := f.JSONFormatter.Format(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	// logrus的JSON输出按键名排序，重新编码不会改变字段顺序
+	var record map[string]json.RawMessage
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	}
+	record[f.levelKey] = marshalJSONValue(f.encoder(fromLogrusLevel(entry.Level)))
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(!f.DisableHTMLEscape)
+	if err := encoder.Encode(record); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fromLogrusLevel 将logrus日志级别转换为日志门面级别
+func fromLogrusLevel(level logrus.Level) LogLevel {
+	switch level {
+	case logrus.TraceLevel, logrus.DebugLevel:
+		return DebugLevel
+	case logrus.WarnLevel:
+		return WarnLevel
+	case logrus.ErrorLevel:
+		return ErrorLevel
+	case logrus.FatalLevel:
+		return FatalLevel
+	case logrus.PanicLevel:
+		return PanicLevel
+	default:
+		return InfoLevel
+	}
+}
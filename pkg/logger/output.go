@@ -0,0 +1,391 @@
+package logger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// WriteSyncer 支持刷新的输出目标
+type WriteSyncer interface {
+	io.Writer
+	// Sync 将缓冲的数据写入底层输出
+	Sync() error
+}
+
+// OutputSettable 支持在运行时替换输出目标的日志实例
+type OutputSettable interface {
+	// SetOutput 替换日志输出目标
+	SetOutput(w io.Writer)
+}
+
+// newOutput 根据配置创建日志输出目标，配置了多个目标时同时写入
+func newOutput(options *LoggerOptions) io.Writer {
+	return newOutputWith(options, func(outputs []io.Writer) io.Writer {
+		return multiOutput(outputs)
+	})
+}
+
+// newOutputWith 根据配置创建日志输出目标，配置了多个目标时使用combine组合，
+// 供后端以自身的方式组合多个输出目标
+func newOutputWith(options *LoggerOptions, combine func(outputs []io.Writer) io.Writer) io.Writer {
+	paths := options.OutputPaths
+	if len(paths) == 0 && options.Writer == nil {
+		paths = []string{options.OutputPath}
+	}
+
+	outputs := make([]io.Writer, 0, len(paths)+1)
+	for _, path := range paths {
+		outputs = append(outputs, openOutputPath(path, options))
+	}
+	if options.Writer != nil {
+		outputs = append(outputs, NewSynchronizedWriter(options.Writer))
+	}
+
+	var output io.Writer
+	if len(outputs) == 1 {
+		output = outputs[0]
+	} else {
+		output = combine(outputs)
+	}
+
+	if options.BreakerThreshold > 0 {
+		output = newCircuitBreakerWriter(output, options.BreakerFallback, options.BreakerThreshold, options.BreakerCooldown, options.Clock)
+	}
+
+	if options.BufferedWriterSize > 0 {
+		output = newBufferedWriter(output, options.BufferedWriterSize)
+	}
+
+	return output
+}
+
+// isTerminal 判断输出目标是否为终端
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// outputIsTerminal 判断配置的所有输出目标是否都是终端，文件和自定义输出不是终端，未配置输出时按标准输出判断
+func outputIsTerminal(options *LoggerOptions) bool {
+	paths := options.OutputPaths
+	if len(paths) == 0 && options.Writer == nil {
+		path := options.OutputPath
+		if path == "" {
+			path = "stdout"
+		}
+		paths = []string{path}
+	}
+
+	for _, path := range paths {
+		switch path {
+		case "stdout":
+			if !isTerminal(os.Stdout) {
+				return false
+			}
+		case "stderr":
+			if !isTerminal(os.Stderr) {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return options.Writer == nil || isTerminal(options.Writer)
+}
+
+// warnedOutputPaths 已输出过回退警告的日志文件路径，同一路径只警告一次
+var warnedOutputPaths sync.Map
+
+// warnOutputFallback 输出日志文件无法打开、回退到标准输出的警告，同一路径只警告一次，
+// 避免按名称创建日志实例、Clone等反复使用同一个错误路径时重复输出
+func warnOutputFallback(path string, err error) {
+	if _, warned := warnedOutputPaths.LoadOrStore(path, true); !warned {
+		internalWarnf("cannot open log output %q, falling back to stdout: %v", path, err)
+	}
+}
+
+// openOutputPath 根据路径创建单个输出目标，支持stdout和stderr，
+// 文件无法打开时输出警告并回退到标准输出，日志级别和格式等配置保持不变
+func openOutputPath(path string, options *LoggerOptions) io.Writer {
+	switch path {
+	case "stdout":
+		return os.Stdout
+	case "stderr":
+		return os.Stderr
+	}
+
+	if options.CompressedOutput {
+		// 边写边压缩，压缩流无法按大小轮转，直接追加到.gz文件
+		gz := newGzipFileWriter(path)
+		if err := checkOutputPath(gz.path); err != nil {
+			warnOutputFallback(gz.path, err)
+			return os.Stdout
+		}
+		return gz
+	}
+
+	if err := checkOutputPath(path); err != nil {
+		warnOutputFallback(path, err)
+		return os.Stdout
+	}
+
+	// 使用lumberjack进行日志轮转
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    int(options.MaxLogSize),             // MB
+		MaxAge:     int(options.MaxLogAge.Hours() / 24), // 天
+		MaxBackups: options.MaxLogFiles,
+		Compress:   options.CompressLogs,
+	}
+}
+
+// checkOutputPath 检查日志文件能否创建和写入
+func checkOutputPath(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// multiOutput 同时写入多个输出目标
+type multiOutput []io.Writer
+
+// Write 写入所有输出目标，返回第一个错误
+func (m multiOutput) Write(p []byte) (int, error) {
+	var firstErr error
+	for _, w := range m {
+		if _, err := w.Write(p); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return len(p), nil
+}
+
+// Sync 刷新所有输出目标，返回合并后的全部错误，便于调用方区分各个输出目标的错误
+func (m multiOutput) Sync() error {
+	var errs []error
+	for _, w := range m {
+		if err := syncOutput(w); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close 关闭所有输出目标，返回第一个错误
+func (m multiOutput) Close() error {
+	var firstErr error
+	for _, w := range m {
+		if err := closeOutput(w); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// syncOutput 刷新输出目标，标准输出和标准错误输出没有需要fsync的数据，与不支持刷新的输出一样直接返回nil
+func syncOutput(output io.Writer) error {
+	if output == os.Stdout || output == os.Stderr {
+		return nil
+	}
+	if ws, ok := output.(WriteSyncer); ok {
+		return ws.Sync()
+	}
+	return nil
+}
+
+// filterBenignSyncErrors 忽略终端、管道等不支持fsync的输出目标返回的EINVAL和ENOTTY错误，
+// 如标准输出上的"sync /dev/stdout: inappropriate ioctl for device"，其他I/O错误原样返回
+func filterBenignSyncErrors(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// 多个输出目标的错误会被合并，需要逐个过滤
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var remaining []error
+		for _, e := range joined.Unwrap() {
+			if e = filterBenignSyncErrors(e); e != nil {
+				remaining = append(remaining, e)
+			}
+		}
+		return errors.Join(remaining...)
+	}
+
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+	return err
+}
+
+// closeOutput 关闭输出目标，标准输出和不支持关闭的输出直接返回nil
+func closeOutput(output io.Writer) error {
+	if output == os.Stdout || output == os.Stderr {
+		return nil
+	}
+	if c, ok := output.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// SynchronizedWriter 串行化写入的输出，每次Write在互斥锁内作为一次完整的写入交给底层输出，
+// 多个goroutine并发输出时日志行不会互相穿插。WithWriter和SetOutput提供的自定义输出会自动使用它包装
+type SynchronizedWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+// NewSynchronizedWriter 创建串行化写入w的输出，w已经是*SynchronizedWriter时直接返回
+func NewSynchronizedWriter(w io.Writer) *SynchronizedWriter {
+	if s, ok := w.(*SynchronizedWriter); ok {
+		return s
+	}
+	return &SynchronizedWriter{writer: w}
+}
+
+// Write 在互斥锁内将p一次写入底层输出
+func (s *SynchronizedWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writer.Write(p)
+}
+
+// Sync 刷新底层输出，不支持刷新的输出直接返回nil
+func (s *SynchronizedWriter) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return syncOutput(s.writer)
+}
+
+// Close 关闭底层输出，标准输出和不支持关闭的输出直接返回nil
+func (s *SynchronizedWriter) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return closeOutput(s.writer)
+}
+
+// bufferedWriter 带缓冲的并发安全输出
+type bufferedWriter struct {
+	mu     sync.Mutex
+	writer *bufio.Writer
+	output io.Writer
+}
+
+// newBufferedWriter 创建带缓冲的输出，size为缓冲区大小（字节）
+func newBufferedWriter(w io.Writer, size int) *bufferedWriter {
+	return &bufferedWriter{
+		writer: bufio.NewWriterSize(w, size),
+		output: w,
+	}
+}
+
+// Write 写入缓冲区
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.writer.Write(p)
+}
+
+// Sync 将缓冲区中的数据写入底层输出
+func (b *bufferedWriter) Sync() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.writer.Flush(); err != nil {
+		return err
+	}
+	return syncOutput(b.output)
+}
+
+// Close 写入缓冲区中的数据并关闭底层输出
+func (b *bufferedWriter) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.writer.Flush(); err != nil {
+		return err
+	}
+	return closeOutput(b.output)
+}
+
+// gzipFileWriter 边写边进行gzip压缩的文件输出
+type gzipFileWriter struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	writer *gzip.Writer
+}
+
+// newGzipFileWriter 创建gzip压缩文件输出，路径没有.gz后缀时自动添加，文件在首次写入时打开
+func newGzipFileWriter(path string) *gzipFileWriter {
+	if !strings.HasSuffix(path, ".gz") {
+		path += ".gz"
+	}
+	return &gzipFileWriter{path: path}
+}
+
+// Write 压缩并写入文件，已存在的文件以追加方式写入新的gzip成员
+func (g *gzipFileWriter) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.writer == nil {
+		file, err := os.OpenFile(g.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return 0, err
+		}
+		g.file = file
+		g.writer = gzip.NewWriter(file)
+	}
+	return g.writer.Write(p)
+}
+
+// Sync 将压缩缓冲区中的数据写入文件
+func (g *gzipFileWriter) Sync() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.writer == nil {
+		return nil
+	}
+	if err := g.writer.Flush(); err != nil {
+		return err
+	}
+	return g.file.Sync()
+}
+
+// Close 写入gzip结尾并关闭文件，之后的写入会以新的gzip成员追加
+func (g *gzipFileWriter) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.writer == nil {
+		return nil
+	}
+	err := g.writer.Close()
+	if closeErr := g.file.Close(); err == nil {
+		err = closeErr
+	}
+	g.writer = nil
+	g.file = nil
+	return err
+}
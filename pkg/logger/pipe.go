@@ -0,0 +1,191 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPipeLineSize 管道中单行日志记录的最大字节数
+const maxPipeLineSize = 4 * 1024 * 1024
+
+// entryJSON 日志记录在管道中传输的JSON格式，字段以数组保存以保持顺序
+type entryJSON struct {
+	Time    time.Time   `json:"time"`
+	Level   string      `json:"level"`
+	Name    string      `json:"logger,omitempty"`
+	Message string      `json:"msg"`
+	Fields  []fieldJSON `json:"fields,omitempty"`
+}
+
+// fieldJSON 字段的JSON格式
+type fieldJSON struct {
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// MarshalJSON 将日志记录编码为JSON，嵌套字段被展开，非基础类型的字段值转换为文本
+func (e Entry) MarshalJSON() ([]byte, error) {
+	fields := journalFields(e.Fields)
+	encoded := entryJSON{
+		Time:    e.Time,
+		Level:   e.Level.String(),
+		Name:    e.Name,
+		Message: e.Message,
+		Fields:  make([]fieldJSON, len(fields)),
+	}
+	for i, field := range fields {
+		encoded.Fields[i] = fieldJSON{Key: field.Key, Value: field.Value}
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON 从JSON解码日志记录，整数字段值解码为int64，其他数值解码为float64
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded entryJSON
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+	level, ok := levelFromName(decoded.Level)
+	if !ok {
+		return fmt.Errorf("unknown log level %q", decoded.Level)
+	}
+
+	fields := make([]Field, len(decoded.Fields))
+	for i, field := range decoded.Fields {
+		fields[i] = Field{Key: field.Key, Value: jsonFieldValue(field.Value)}
+	}
+	*e = newEntry(decoded.Time, level, decoded.Name, decoded.Message, fields)
+	return nil
+}
+
+// levelFromName 根据LogLevel.String()的结果获取日志级别
+func levelFromName(name string) (LogLevel, bool) {
+	for level := DebugLevel; level <= PanicLevel; level++ {
+		if strings.EqualFold(level.String(), name) {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+// jsonFieldValue 将json.Number转换为int64或float64，嵌套的数组和对象中的数值同样转换
+func jsonFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = jsonFieldValue(v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = jsonFieldValue(v[k])
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// PipeWriter 将日志记录以JSON行写入管道，配合PipeReader将工作进程的结构化日志转发给中心日志实例
+type PipeWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewPipeWriter 创建写入w的管道日志写入器，w通常为管道、套接字或子进程的标准输出
+func NewPipeWriter(w io.Writer) *PipeWriter {
+	return &PipeWriter{w: w}
+}
+
+// WriteEntry 将一条日志记录编码为一行JSON写入管道，并发调用时每条记录完整写入
+func (p *PipeWriter) WriteEntry(entry Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err = p.w.Write(data)
+	return err
+}
+
+// Logger 创建将日志记录写入管道的日志实例，级别过滤、常量字段等选项在写入前生效，写入失败时输出内部警告
+func (p *PipeWriter) Logger(name string, level LogLevel, opts ...Option) *FuncLogger {
+	return NewFuncLogger(name, level, func(level LogLevel, msg string, fields []Field) {
+		if err := p.WriteEntry(newEntry(time.Now(), level, name, msg, fields)); err != nil {
+			internalWarnf("failed to write pipe entry: %v", err)
+		}
+	}, opts...)
+}
+
+// PipeReader 从管道读取PipeWriter写入的日志记录，并通过另一个日志实例重新输出
+type PipeReader struct {
+	r      io.Reader
+	logger Logger
+}
+
+// NewPipeReader 创建从r读取日志记录并通过l重新输出的管道日志读取器
+func NewPipeReader(r io.Reader, l Logger) *PipeReader {
+	return &PipeReader{r: r, logger: l}
+}
+
+// Run 读取并重新输出日志记录，直到r结束或读取失败，r正常结束时返回nil。
+// 原日志名称以source字段输出，致命级和恐慌级记录按错误级输出，避免中心进程退出，无法解析的行输出内部警告后跳过
+func (p *PipeReader) Run() error {
+	scanner := bufio.NewScanner(p.r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxPipeLineSize)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			internalWarnf("failed to decode pipe entry: %v", err)
+			continue
+		}
+		p.relog(entry)
+	}
+
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		return err
+	}
+	return nil
+}
+
+// relog 通过中心日志实例输出一条日志记录
+func (p *PipeReader) relog(entry Entry) {
+	l := p.logger
+	if entry.Name != "" {
+		l = l.WithField("source", entry.Name)
+	}
+	if len(entry.Fields) > 0 {
+		l = l.WithFields(entry.Fields...)
+	}
+
+	level := entry.Level
+	if level > ErrorLevel {
+		level = ErrorLevel
+	}
+	logAtLevel(l, level, entry.Message)
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"sync/atomic"
+)
+
+// LoggerStats 日志输出统计
+type LoggerStats struct {
+	Emitted    uint64 // 成功写入的日志条数
+	Dropped    uint64 // 因写入失败等原因丢弃的日志条数
+	Sampled    uint64 // 被采样丢弃的日志条数
+	Suppressed uint64 // 被限流或去重抑制的日志条数
+
+	FieldSuppressed map[string]uint64 // 按字段值限流时以"键=值"汇总的抑制条数，未配置时为nil
+}
+
+// StatsReporter 支持输出统计的日志实例
+type StatsReporter interface {
+	// Stats 获取日志输出统计
+	Stats() LoggerStats
+}
+
+// loggerStats 日志输出统计计数器，使用原子操作更新
+type loggerStats struct {
+	emitted    uint64
+	dropped    uint64
+	sampled    uint64
+	suppressed uint64
+}
+
+// snapshot 获取统计快照
+func (s *loggerStats) snapshot() LoggerStats {
+	return LoggerStats{
+		Emitted:    atomic.LoadUint64(&s.emitted),
+		Dropped:    atomic.LoadUint64(&s.dropped),
+		Sampled:    atomic.LoadUint64(&s.sampled),
+		Suppressed: atomic.LoadUint64(&s.suppressed),
+	}
+}
@@ -3,34 +3,38 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"strings"
 	"time"
-
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // StdLogger 标准库log适配器
 type StdLogger struct {
-	level  LogLevel
-	fields []Field
-	ctx    context.Context
-	logger *log.Logger
-	name   string
+	level   LogLevel
+	fields  []Field
+	ctx     context.Context
+	logger  *log.Logger
+	encoder Encoder
+	name    string
+	sites   []string // 开启字段追踪时各字段的添加位置
+	core    *loggerCore
 }
 
 // NewStdLogger 创建标准库log实例
 func NewStdLogger(name string, opts ...Option) *StdLogger {
 	options := &LoggerOptions{
-		Level:          InfoLevel,
-		Format:         "text",
-		OutputPath:     "stdout",
-		MaxLogSize:     100,                // 默认100MB
-		MaxLogAge:      7 * 24 * time.Hour, // 默认7天
-		MaxLogFiles:    10,                 // 默认10个文件
-		CompressLogs:   false,              // 默认不压缩
-		MaxMessageSize: 0,                  // 默认不限制
-		Config:         make(map[string]interface{}),
+		Level:            InfoLevel,
+		Format:           "text",
+		OutputPath:       "stdout",
+		MaxLogSize:       100,                // 默认100MB
+		MaxLogAge:        7 * 24 * time.Hour, // 默认7天
+		MaxLogFiles:      10,                 // 默认10个文件
+		CompressLogs:     false,              // 默认不压缩
+		MaxMessageSize:   0,                  // 默认不限制
+		AlwaysLogAbove:   ErrorLevel,         // 默认错误及以上级别不受采样和限流影响
+		SanitizeNewlines: true,               // 默认转义文本格式中的换行符
+		Config:           make(map[string]interface{}),
 	}
 
 	for _, opt := range opts {
@@ -38,32 +42,25 @@ func NewStdLogger(name string, opts ...Option) *StdLogger {
 	}
 
 	// 配置输出
-	var output interface {
-		Write(p []byte) (n int, err error)
-	}
-	if options.OutputPath == "stdout" {
-		output = os.Stdout
-	} else {
-		// 使用lumberjack进行日志轮转
-		output = &lumberjack.Logger{
-			Filename:   options.OutputPath,
-			MaxSize:    int(options.MaxLogSize),             // MB
-			MaxAge:     int(options.MaxLogAge.Hours() / 24), // 天
-			MaxBackups: options.MaxLogFiles,
-			Compress:   options.CompressLogs,
-		}
+	core := newLoggerCore(options)
+
+	// 创建标准库log实例，时间戳由编码器统一输出
+	logger := log.New(core.writer(), "", 0)
+
+	s := &StdLogger{
+		level:   options.Level,
+		fields:  make([]Field, 0),
+		ctx:     context.Background(),
+		logger:  logger,
+		encoder: newEncoder(options),
+		name:    name,
+		core:    core,
 	}
 
-	// 创建标准库log实例
-	logger := log.New(output, "", log.LstdFlags)
-
-	return &StdLogger{
-		level:  options.Level,
-		fields: make([]Field, 0),
-		ctx:    context.Background(),
-		logger: logger,
-		name:   name,
-	}
+	s.core.startHeartbeat(func(count uint64) {
+		s.Info(s.core.heartbeatMessage(), Field{Key: "heartbeat", Value: count})
+	})
+	return s
 }
 
 // SetLevel 设置日志级别
@@ -76,118 +73,166 @@ func (s *StdLogger) GetLevel() LogLevel {
 	return s.level
 }
 
-// formatMessage 格式化日志消息
-func (s *StdLogger) formatMessage(level LogLevel, msg string, fields []Field) string {
-	allFields := append(s.fields, fields...)
-
-	fieldStr := ""
-	for _, field := range allFields {
-		fieldStr += fmt.Sprintf(" %s=%v", field.Key, field.Value)
+// formatMessage 使用编码器格式化日志消息
+func (s *StdLogger) formatMessage(ctx context.Context, level LogLevel, msg string, fields []Field) string {
+	entry := getEntry()
+	defer putEntry(entry)
+	*entry = newEntry(s.core.now(), level, s.name, s.core.redactMessage(msg), s.core.appendMergedFields(entry.Fields, level, msg, s.fields, s.sites, ctx, fields))
+	line, err := s.encoder.Encode(*entry)
+	if err != nil {
+		return fmt.Sprintf("%s [%s] [%s] %s encode_error=%v", entry.Time.Format(DefaultTextTimeLayout), level.String(), s.name, msg, err)
 	}
+	return strings.TrimSuffix(string(line), "\n")
+}
 
-	return fmt.Sprintf("[%s] [%s] %s%s", level.String(), s.name, msg, fieldStr)
+// log 输出一条日志，致命级日志退出程序，恐慌级日志触发panic
+func (s *StdLogger) log(ctx context.Context, level LogLevel, msg string, fields []Field) {
+	line := s.formatMessage(ctx, level, msg, fields)
+	s.logger.Println(line)
+
+	switch level {
+	case FatalLevel:
+		s.core.exit(1)
+	case PanicLevel:
+		s.Sync()
+		s.core.writeCrashDump(line)
+		panic(line)
+	}
 }
 
 // Debug 输出调试级日志
 func (s *StdLogger) Debug(msg string, fields ...Field) {
-	if s.level <= DebugLevel {
-		s.logger.Println(s.formatMessage(DebugLevel, msg, fields))
+	if levelEnabled(s.level, s.ctx, DebugLevel) && s.core.allow(DebugLevel, s.fields, s.ctx, fields) {
+		s.log(s.ctx, DebugLevel, msg, fields)
 	}
 }
 
 // Debugf 输出格式化的调试级日志
 func (s *StdLogger) Debugf(format string, args ...interface{}) {
-	if s.level <= DebugLevel {
-		msg := fmt.Sprintf(format, args...)
-		s.logger.Println(s.formatMessage(DebugLevel, msg, nil))
+	if levelEnabled(s.level, s.ctx, DebugLevel) && s.core.allow(DebugLevel, s.fields, s.ctx, nil) {
+		s.log(s.ctx, DebugLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// DebugCtx 使用ctx中提取的字段输出调试级日志
+func (s *StdLogger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(s.level, ctx, DebugLevel) && s.core.allow(DebugLevel, s.fields, ctx, fields) {
+		s.log(ctx, DebugLevel, msg, fields)
 	}
 }
 
 // Info 输出信息级日志
 func (s *StdLogger) Info(msg string, fields ...Field) {
-	if s.level <= InfoLevel {
-		s.logger.Println(s.formatMessage(InfoLevel, msg, fields))
+	if levelEnabled(s.level, s.ctx, InfoLevel) && s.core.allow(InfoLevel, s.fields, s.ctx, fields) {
+		s.log(s.ctx, InfoLevel, msg, fields)
 	}
 }
 
 // Infof 输出格式化的信息级日志
 func (s *StdLogger) Infof(format string, args ...interface{}) {
-	if s.level <= InfoLevel {
-		msg := fmt.Sprintf(format, args...)
-		s.logger.Println(s.formatMessage(InfoLevel, msg, nil))
+	if levelEnabled(s.level, s.ctx, InfoLevel) && s.core.allow(InfoLevel, s.fields, s.ctx, nil) {
+		s.log(s.ctx, InfoLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// InfoCtx 使用ctx中提取的字段输出信息级日志
+func (s *StdLogger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(s.level, ctx, InfoLevel) && s.core.allow(InfoLevel, s.fields, ctx, fields) {
+		s.log(ctx, InfoLevel, msg, fields)
 	}
 }
 
 // Warn 输出警告级日志
 func (s *StdLogger) Warn(msg string, fields ...Field) {
-	if s.level <= WarnLevel {
-		s.logger.Println(s.formatMessage(WarnLevel, msg, fields))
+	if levelEnabled(s.level, s.ctx, WarnLevel) && s.core.allow(WarnLevel, s.fields, s.ctx, fields) {
+		s.log(s.ctx, WarnLevel, msg, fields)
 	}
 }
 
 // Warnf 输出格式化的警告级日志
 func (s *StdLogger) Warnf(format string, args ...interface{}) {
-	if s.level <= WarnLevel {
-		msg := fmt.Sprintf(format, args...)
-		s.logger.Println(s.formatMessage(WarnLevel, msg, nil))
+	if levelEnabled(s.level, s.ctx, WarnLevel) && s.core.allow(WarnLevel, s.fields, s.ctx, nil) {
+		s.log(s.ctx, WarnLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// WarnCtx 使用ctx中提取的字段输出警告级日志
+func (s *StdLogger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(s.level, ctx, WarnLevel) && s.core.allow(WarnLevel, s.fields, ctx, fields) {
+		s.log(ctx, WarnLevel, msg, fields)
 	}
 }
 
 // Error 输出错误级日志
 func (s *StdLogger) Error(msg string, fields ...Field) {
-	if s.level <= ErrorLevel {
-		s.logger.Println(s.formatMessage(ErrorLevel, msg, fields))
+	if levelEnabled(s.level, s.ctx, ErrorLevel) && s.core.allow(ErrorLevel, s.fields, s.ctx, fields) {
+		s.log(s.ctx, ErrorLevel, msg, fields)
 	}
 }
 
 // Errorf 输出格式化的错误级日志
 func (s *StdLogger) Errorf(format string, args ...interface{}) {
-	if s.level <= ErrorLevel {
-		msg := fmt.Sprintf(format, args...)
-		s.logger.Println(s.formatMessage(ErrorLevel, msg, nil))
+	if levelEnabled(s.level, s.ctx, ErrorLevel) && s.core.allow(ErrorLevel, s.fields, s.ctx, nil) {
+		s.log(s.ctx, ErrorLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// ErrorCtx 使用ctx中提取的字段输出错误级日志
+func (s *StdLogger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(s.level, ctx, ErrorLevel) && s.core.allow(ErrorLevel, s.fields, ctx, fields) {
+		s.log(ctx, ErrorLevel, msg, fields)
 	}
 }
 
 // Fatal 输出致命级日志并退出程序
 func (s *StdLogger) Fatal(msg string, fields ...Field) {
-	if s.level <= FatalLevel {
-		s.logger.Println(s.formatMessage(FatalLevel, msg, fields))
-		os.Exit(1)
+	if levelEnabled(s.level, s.ctx, FatalLevel) && s.core.allow(FatalLevel, s.fields, s.ctx, fields) {
+		s.log(s.ctx, FatalLevel, msg, fields)
 	}
 }
 
 // Fatalf 输出格式化的致命级日志并退出程序
 func (s *StdLogger) Fatalf(format string, args ...interface{}) {
-	if s.level <= FatalLevel {
-		msg := fmt.Sprintf(format, args...)
-		s.logger.Println(s.formatMessage(FatalLevel, msg, nil))
-		os.Exit(1)
+	if levelEnabled(s.level, s.ctx, FatalLevel) && s.core.allow(FatalLevel, s.fields, s.ctx, nil) {
+		s.log(s.ctx, FatalLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+func (s *StdLogger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(s.level, ctx, FatalLevel) && s.core.allow(FatalLevel, s.fields, ctx, fields) {
+		s.log(ctx, FatalLevel, msg, fields)
 	}
 }
 
 // Panic 输出恐慌级日志并触发panic
 func (s *StdLogger) Panic(msg string, fields ...Field) {
-	if s.level <= PanicLevel {
-		msg := s.formatMessage(PanicLevel, msg, fields)
-		s.logger.Println(msg)
-		panic(msg)
+	if levelEnabled(s.level, s.ctx, PanicLevel) && s.core.allow(PanicLevel, s.fields, s.ctx, fields) {
+		s.log(s.ctx, PanicLevel, msg, fields)
 	}
 }
 
 // Panicf 输出格式化的恐慌级日志并触发panic
 func (s *StdLogger) Panicf(format string, args ...interface{}) {
-	if s.level <= PanicLevel {
-		msg := fmt.Sprintf(format, args...)
-		fullMsg := s.formatMessage(PanicLevel, msg, nil)
-		s.logger.Println(fullMsg)
-		panic(fullMsg)
+	if levelEnabled(s.level, s.ctx, PanicLevel) && s.core.allow(PanicLevel, s.fields, s.ctx, nil) {
+		s.log(s.ctx, PanicLevel, fmt.Sprintf(format, args...), nil)
+	}
+}
+
+// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+func (s *StdLogger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(s.level, ctx, PanicLevel) && s.core.allow(PanicLevel, s.fields, ctx, fields) {
+		s.log(ctx, PanicLevel, msg, fields)
 	}
 }
 
 // WithFields 添加字段到日志
 func (s *StdLogger) WithFields(fields ...Field) Logger {
 	newLogger := *s
+	newLogger.fields = make([]Field, 0, len(s.fields)+len(fields))
+	newLogger.fields = append(newLogger.fields, s.fields...)
 	newLogger.fields = append(newLogger.fields, fields...)
+	newLogger.sites = s.core.traceFields(s.sites, fields)
 	return &newLogger
 }
 
@@ -196,6 +241,22 @@ func (s *StdLogger) WithField(key string, value interface{}) Logger {
 	return s.WithFields(Field{Key: key, Value: value})
 }
 
+// Clone 以新的名称复制日志实例，保留级别、字段、上下文和输出配置
+func (s *StdLogger) Clone(name string) Logger {
+	newLogger := *s
+	newLogger.name = name
+	newLogger.fields = append([]Field(nil), s.fields...)
+	newLogger.sites = append([]string(nil), s.sites...)
+	newLogger.core = s.core.clone()
+	newLogger.logger = log.New(newLogger.core.writer(), "", 0)
+	return &newLogger
+}
+
+// WithStruct 将结构体的导出字段展开为 prefix.FieldName 形式的字段添加到日志
+func (s *StdLogger) WithStruct(prefix string, v interface{}) Logger {
+	return s.WithFields(StructFields(prefix, v)...)
+}
+
 // WithContext 添加上下文到日志
 func (s *StdLogger) WithContext(ctx context.Context) Logger {
 	newLogger := *s
@@ -203,8 +264,11 @@ func (s *StdLogger) WithContext(ctx context.Context) Logger {
 	return &newLogger
 }
 
-// WithError 添加错误信息到日志
+// WithError 添加错误信息到日志，err为nil时返回当前日志实例
 func (s *StdLogger) WithError(err error) Logger {
+	if err == nil {
+		return s
+	}
 	return s.WithField("error", err)
 }
 
@@ -243,10 +307,52 @@ func (s *StdLogger) IsPanicEnabled() bool {
 	return s.level <= PanicLevel
 }
 
-// Sync 刷新日志缓冲区
+// IsEnabled 检查level级别是否启用，与实际输出日志时一样考虑上下文中的级别覆盖
+func (s *StdLogger) IsEnabled(level LogLevel) bool {
+	return levelEnabled(s.level, s.ctx, level)
+}
+
+// SetOutput 替换日志输出目标，派生的日志实例同时生效，原输出目标不会被关闭
+func (s *StdLogger) SetOutput(w io.Writer) {
+	s.core.setOutput(w)
+}
+
+// Stats 获取日志输出统计
+func (s *StdLogger) Stats() LoggerStats {
+	stats := s.core.stats.snapshot()
+	stats.FieldSuppressed = s.core.fieldSuppressed()
+	return stats
+}
+
+// ResetSampling 清空按字段值限流的计数窗口，被抑制的消息可以立即再次输出，派生的日志实例同时生效。
+// 适合在每个请求或测试开始时调用，避免上一阶段的限流状态影响下一阶段，统计中累计的抑制条数不受影响
+func (s *StdLogger) ResetSampling() {
+	s.core.resetSampling()
+}
+
+// StartTimer 将uptime字段的起始时间重置为当前时间，派生的日志实例同时生效
+func (s *StdLogger) StartTimer() {
+	s.core.startTimer()
+}
+
+// Describe 获取日志实例的有效配置信息
+func (s *StdLogger) Describe() LoggerInfo {
+	return s.core.describe("std", s.name, s.level)
+}
+
+// Sync 输出采样汇总并刷新日志缓冲区
 func (s *StdLogger) Sync() error {
-	// 标准库log没有Sync方法，返回nil
-	return nil
+	if level, fields, ok := s.core.samplingSummary(); ok && s.level <= level {
+		s.log(s.ctx, level, samplingSummaryMessage, fields)
+	}
+	// 标准库log没有Sync方法，刷新底层输出
+	return s.core.sync()
+}
+
+// Close 刷新并关闭日志输出，标准输出不会被关闭
+func (s *StdLogger) Close() error {
+	s.Sync()
+	return s.core.close()
 }
 
 // StdLoggerProvider 标准库log提供者
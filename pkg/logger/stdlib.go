@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"io"
+	"log"
+	"strings"
+)
+
+// stdlibWriter 将写入的内容作为指定级别的日志输出
+type stdlibWriter struct {
+	logger Logger
+	level  LogLevel
+}
+
+// StdlibWriter 创建将每次写入转换为level级别日志的io.Writer，写入内容末尾的换行符会被去除，
+// 可用于接收io.Writer的第三方库
+func StdlibWriter(l Logger, level LogLevel) io.Writer {
+	return &stdlibWriter{logger: l, level: level}
+}
+
+// StdlibLogger 创建输出到日志门面的标准库*log.Logger，可用于http.Server.ErrorLog等场景
+func StdlibLogger(l Logger, level LogLevel) *log.Logger {
+	return log.New(StdlibWriter(l, level), "", 0)
+}
+
+// Write 将内容作为一条日志输出
+func (w *stdlibWriter) Write(p []byte) (int, error) {
+	logAtLevel(w.logger, w.level, strings.TrimRight(string(p), "\r\n"))
+	return len(p), nil
+}
+
+// logAtLevel 以指定级别输出一条日志
+func logAtLevel(l Logger, level LogLevel, msg string) {
+	switch level {
+	case DebugLevel:
+		l.Debug(msg)
+	case WarnLevel:
+		l.Warn(msg)
+	case ErrorLevel:
+		l.Error(msg)
+	case FatalLevel:
+		l.Fatal(msg)
+	case PanicLevel:
+		l.Panic(msg)
+	default:
+		l.Info(msg)
+	}
+}
+
+// severityTokens 行首严重级别标记与日志级别的对应关系，致命级标记按错误级输出，避免第三方库的输出导致程序退出
+var severityTokens = map[string]LogLevel{
+	"TRACE":    DebugLevel,
+	"DEBUG":    DebugLevel,
+	"INFO":     InfoLevel,
+	"NOTICE":   InfoLevel,
+	"WARN":     WarnLevel,
+	"WARNING":  WarnLevel,
+	"ERR":      ErrorLevel,
+	"ERROR":    ErrorLevel,
+	"CRIT":     ErrorLevel,
+	"CRITICAL": ErrorLevel,
+	"FATAL":    ErrorLevel,
+	"PANIC":    ErrorLevel,
+}
+
+// leveledWriter 按行首的严重级别标记输出日志
+type leveledWriter struct {
+	logger Logger
+}
+
+// LeveledWriter 创建按行解析严重级别的io.Writer，用于桥接只输出纯文本的第三方库。
+// 行首的ERROR:、[WARN]或大写的WARN等标记决定日志级别并从消息中去除，没有标记的行以Info级别输出，
+// FATAL、PANIC等标记按Error级别输出，不会导致程序退出
+func LeveledWriter(l Logger) io.Writer {
+	return &leveledWriter{logger: l}
+}
+
+// Write 将内容按行拆分，每行按其严重级别输出一条日志，空行被忽略
+func (w *leveledWriter) Write(p []byte) (int, error) {
+	for _, line := range strings.Split(string(p), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		level, msg := parseSeverity(line)
+		logAtLevel(w.logger, level, msg)
+	}
+	return len(p), nil
+}
+
+// parseSeverity 解析行首的严重级别标记，支持 LEVEL: msg、[LEVEL] msg 和大写的 LEVEL msg 三种形式，
+// 无法识别时返回Info级别和原始内容
+func parseSeverity(line string) (LogLevel, string) {
+	rest := strings.TrimLeft(line, " \t")
+	bracketed := strings.HasPrefix(rest, "[")
+	if bracketed {
+		rest = rest[1:]
+	}
+
+	end := strings.IndexAny(rest, ":] \t")
+	if end <= 0 {
+		return InfoLevel, line
+	}
+	token := rest[:end]
+	level, ok := severityTokens[strings.ToUpper(token)]
+	if !ok {
+		return InfoLevel, line
+	}
+
+	rest = rest[end:]
+	switch {
+	case bracketed:
+		if !strings.HasPrefix(rest, "]") {
+			return InfoLevel, line
+		}
+		rest = strings.TrimPrefix(rest[1:], ":")
+	case strings.HasPrefix(rest, ":"):
+		rest = rest[1:]
+	case token != strings.ToUpper(token):
+		// 没有分隔符时只接受大写标记，避免将"Error connecting..."这类普通句子误判为级别
+		return InfoLevel, line
+	}
+	return level, strings.TrimLeft(rest, " \t")
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Stopwatch 记录多个命名检查点的计时器，用于在多阶段操作中输出各阶段耗时字段（如db_ms、render_ms），
+// 代替在每个阶段手动调用time.Since，可以并发使用
+type Stopwatch struct {
+	clock Clock
+	start time.Time
+
+	mu          sync.Mutex
+	checkpoints map[string]time.Time
+}
+
+// NewStopwatch 创建从当前时间开始计时的计时器，clock为nil时使用系统时钟
+func NewStopwatch(clock Clock) *Stopwatch {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &Stopwatch{
+		clock:       clock,
+		start:       clock.Now(),
+		checkpoints: make(map[string]time.Time),
+	}
+}
+
+// Checkpoint 以当前时间记录名为name的检查点，同名检查点被覆盖
+func (s *Stopwatch) Checkpoint(name string) {
+	now := s.clock.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkpoints[name] = now
+}
+
+// SinceCheckpoint 返回自检查点name以来经过的毫秒数字段，键为name_ms，检查点不存在时从计时器创建时开始计算
+func (s *Stopwatch) SinceCheckpoint(name string) Field {
+	now := s.clock.Now()
+	s.mu.Lock()
+	start, ok := s.checkpoints[name]
+	s.mu.Unlock()
+	if !ok {
+		start = s.start
+	}
+	return Field{Key: name + "_ms", Value: now.Sub(start).Milliseconds()}
+}
+
+// Elapsed 返回自计时器创建以来经过的毫秒数字段，键为elapsed_ms
+func (s *Stopwatch) Elapsed() Field {
+	return Field{Key: "elapsed_ms", Value: s.clock.Now().Sub(s.start).Milliseconds()}
+}
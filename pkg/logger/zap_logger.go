@@ -2,65 +2,104 @@ package logger
 
 import (
 	"context"
-	"os"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // ZapLogger zap日志库适配器
 type ZapLogger struct {
 	logger *zap.Logger
+	base   *zap.Logger // 未设置名称的zap.Logger，用于Clone
 	level  LogLevel
 	fields []Field
 	ctx    context.Context
 	name   string
+	sites  []string // 开启字段追踪时各字段的添加位置
+	core   *loggerCore
+
+	wrapped bool // 是否包装了已有的zap实例，包装的实例由调用方管理输出
+}
+
+// WithZapWriteSyncer 设置zap日志直接使用的zapcore.WriteSyncer（如lumberjack、zapcore.BufferedWriteSyncer或测试用的syncer），
+// 设置后优先于OutputPath、OutputPaths和Writer，只对zap日志生效
+func WithZapWriteSyncer(ws zapcore.WriteSyncer) Option {
+	return func(opt *LoggerOptions) {
+		opt.ZapWriteSyncer = ws
+	}
+}
+
+// WithDisableStacktrace 设置是否禁止zap在日志中附加stacktrace字段，只对zap日志生效。
+// 依赖WithWarnStackDepth等门面的堆栈字段时可以开启，避免同一条日志出现两份堆栈；门面自身的stack字段不受影响
+func WithDisableStacktrace(disabled bool) Option {
+	return func(opt *LoggerOptions) {
+		opt.DisableStacktrace = disabled
+	}
 }
 
 // NewZapLogger 创建zap日志实例
 func NewZapLogger(name string, opts ...Option) *ZapLogger {
 	options := &LoggerOptions{
-		Level:          InfoLevel,
-		Format:         "json",
-		OutputPath:     "stdout",
-		MaxLogSize:     100,                // 默认100MB
-		MaxLogAge:      7 * 24 * time.Hour, // 默认7天
-		MaxLogFiles:    10,                 // 默认10个文件
-		CompressLogs:   false,              // 默认不压缩
-		MaxMessageSize: 0,                  // 默认不限制
-		Config:         make(map[string]interface{}),
+		Level:            InfoLevel,
+		Format:           "json",
+		OutputPath:       "stdout",
+		MaxLogSize:       100,                // 默认100MB
+		MaxLogAge:        7 * 24 * time.Hour, // 默认7天
+		MaxLogFiles:      10,                 // 默认10个文件
+		CompressLogs:     false,              // 默认不压缩
+		MaxMessageSize:   0,                  // 默认不限制
+		AlwaysLogAbove:   ErrorLevel,         // 默认错误及以上级别不受采样和限流影响
+		SanitizeNewlines: true,               // 默认转义文本格式中的换行符
+		Config:           make(map[string]interface{}),
 	}
 
 	for _, opt := range opts {
 		opt(options)
 	}
+	if options.ZapWriteSyncer != nil {
+		// 直接使用提供的WriteSyncer，不再处理OutputPath、OutputPaths和Writer
+		options.OutputPaths = nil
+		options.Writer = options.ZapWriteSyncer
+	}
 
-	// 配置zap
-	zapLevel := zapcore.InfoLevel
-	switch options.Level {
-	case DebugLevel:
-		zapLevel = zapcore.DebugLevel
-	case InfoLevel:
-		zapLevel = zapcore.InfoLevel
-	case WarnLevel:
-		zapLevel = zapcore.WarnLevel
-	case ErrorLevel:
-		zapLevel = zapcore.ErrorLevel
-	case FatalLevel:
-		zapLevel = zapcore.FatalLevel
-	case PanicLevel:
-		zapLevel = zapcore.PanicLevel
+	// 配置输出
+	logCore := newLoggerCoreWithOutput(options, newOutputWith(options, newZapMultiOutput))
+	base := newZapBase(options, logCore)
+
+	// 添加名称字段
+	logger := base.Named(name)
+
+	z := &ZapLogger{
+		logger: logger,
+		base:   base,
+		level:  options.Level,
+		fields: make([]Field, 0),
+		ctx:    context.Background(),
+		name:   name,
+		core:   logCore,
 	}
 
+	z.core.startHeartbeat(func(count uint64) {
+		z.Info(z.core.heartbeatMessage(), Field{Key: "heartbeat", Value: count})
+	})
+	return z
+}
+
+// newZapBase 创建写入logCore输出目标的未命名zap.Logger
+func newZapBase(options *LoggerOptions, logCore *loggerCore) *zap.Logger {
 	// 配置编码器
+	keys := keysFromOptions(options).withDefaults()
 	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "time",
-		LevelKey:       "level",
-		NameKey:        "logger",
+		TimeKey:        keys.TimeKey,
+		LevelKey:       keys.LevelKey,
+		NameKey:        keys.NameKey,
 		CallerKey:      "caller",
-		MessageKey:     "msg",
+		MessageKey:     keys.MessageKey,
 		StacktraceKey:  "stacktrace",
 		LineEnding:     zapcore.DefaultLineEnding,
 		EncodeLevel:    zapcore.LowercaseLevelEncoder,
@@ -68,53 +107,69 @@ func NewZapLogger(name string, opts ...Option) *ZapLogger {
 		EncodeDuration: zapcore.SecondsDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
+	if options.DurationUnit > 0 {
+		encoderConfig.EncodeDuration = zapDurationEncoder(options.DurationUnit)
+	}
+	if options.LevelEncoder != nil {
+		encoderConfig.EncodeLevel = zapLevelEncoder(options.LevelEncoder)
+	}
 
 	// 配置输出
-	var core zapcore.Core
-	if options.OutputPath == "stdout" {
-		// 输出到标准输出
-		core = zapcore.NewCore(
-			zapcore.NewJSONEncoder(encoderConfig),
-			zapcore.AddSync(os.Stdout),
-			zapLevel,
-		)
-	} else {
-		// 输出到文件，使用lumberjack进行轮转
-		lumberjackLogger := &lumberjack.Logger{
-			Filename:   options.OutputPath,
-			MaxSize:    int(options.MaxLogSize),             // MB
-			MaxAge:     int(options.MaxLogAge.Hours() / 24), // 天
-			MaxBackups: options.MaxLogFiles,
-			Compress:   options.CompressLogs,
-		}
-
-		core = zapcore.NewCore(
-			zapcore.NewJSONEncoder(encoderConfig),
-			zapcore.AddSync(lumberjackLogger),
-			zapLevel,
-		)
-	}
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderConfig),
+		zapcore.AddSync(logCore.writer()),
+		zapcore.DebugLevel, // 级别由门面过滤，以支持SetLevel和上下文级别覆盖
+	)
 
 	// 构建logger
-	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	zapOpts := []zap.Option{zap.AddCaller(), zap.AddCallerSkip(1), zap.WithClock(zapClock{logCore}), zap.WithFatalHook(zapFatalHook{core: logCore})}
+	if options.DisableStacktrace {
+		zapOpts = append(zapOpts, zap.AddStacktrace(zapNoStacktrace{}))
+	}
+	return zap.New(core, zapOpts...)
+}
 
-	// 添加名称字段
-	logger = logger.Named(name)
+// NewLoggerFromZap 包装已有的zap日志实例，输出目标、编码和采样仍由该实例负责，
+// 门面负责级别过滤和字段处理，opts可用于配置字段处理等门面选项，输出相关选项不生效。
+// 设置了WithFatalHooks或WithExitFunc时，致命级日志写入后先刷新该实例，再执行门面的致命钩子并通过门面退出，
+// 否则仍使用该实例自身的致命钩子退出
+func NewLoggerFromZap(name string, l *zap.Logger, level LogLevel, opts ...Option) *ZapLogger {
+	if l == nil {
+		l = zap.NewNop()
+	}
+	options := wrappedLoggerOptions(level, "json", opts)
+	logCore := newLoggerCoreWithOutput(options, io.Discard)
 
-	return &ZapLogger{
-		logger: logger,
-		level:  options.Level,
-		fields: make([]Field, 0),
-		ctx:    context.Background(),
-		name:   name,
+	// 门面方法多了一层调用，跳过该层以保留原实例的调用位置
+	zapOpts := []zap.Option{zap.AddCallerSkip(1)}
+	if options.DisableStacktrace {
+		zapOpts = append(zapOpts, zap.AddStacktrace(zapNoStacktrace{}))
+	}
+	if len(options.FatalHooks) > 0 || options.ExitFunc != nil {
+		zapOpts = append(zapOpts, zap.WithFatalHook(zapFatalHook{core: logCore, sync: l.Sync}))
+	}
+	base := l.WithOptions(zapOpts...)
+
+	z := &ZapLogger{
+		logger:  base.Named(name),
+		base:    base,
+		level:   options.Level,
+		fields:  make([]Field, 0),
+		ctx:     context.Background(),
+		name:    name,
+		core:    logCore,
+		wrapped: true,
 	}
+
+	z.core.startHeartbeat(func(count uint64) {
+		z.Info(z.core.heartbeatMessage(), Field{Key: "heartbeat", Value: count})
+	})
+	return z
 }
 
 // SetLevel 设置日志级别
 func (z *ZapLogger) SetLevel(level LogLevel) {
 	z.level = level
-	// 更新zap的日志级别
-	// 注意：zap的AtomicLevel需要通过Core来设置，这里简化处理
 }
 
 // GetLevel 获取当前日志级别
@@ -122,119 +177,297 @@ func (z *ZapLogger) GetLevel() LogLevel {
 	return z.level
 }
 
-// toZapFields 将自定义字段转换为zap字段
-func (z *ZapLogger) toZapFields(fields []Field) []zap.Field {
-	zapFields := make([]zap.Field, 0, len(z.fields)+len(fields))
+// toZapFields 将自定义字段和上下文字段转换为zap字段
+func (z *ZapLogger) toZapFields(ctx context.Context, level LogLevel, msg string, fields []Field) []zap.Field {
+	return z.zapFields(z.core.mergeFields(level, msg, z.fields, z.sites, ctx, fields))
+}
+
+// zapFields 将合并后的字段转换为zap字段
+func (z *ZapLogger) zapFields(allFields []Field) []zap.Field {
+	return z.appendZapFields(make([]zap.Field, 0, len(allFields)+1), allFields)
+}
+
+// zapFieldBuffer 池化的字段缓冲区，保存合并后的字段及其转换得到的zap字段
+type zapFieldBuffer struct {
+	merged []Field
+	fields []zap.Field
+}
+
+// zapFieldPool zap字段缓冲区池
+var zapFieldPool = sync.Pool{
+	New: func() interface{} {
+		return &zapFieldBuffer{merged: make([]Field, 0, 16), fields: make([]zap.Field, 0, 16)}
+	},
+}
+
+// pooledZapFields 使用池化的缓冲区合并字段并转换为zap字段。zap在Debug等方法返回前已完成编码，
+// 调用方写出日志后通过release归还缓冲区
+func (z *ZapLogger) pooledZapFields(ctx context.Context, level LogLevel, msg string, fields []Field) *zapFieldBuffer {
+	buf := zapFieldPool.Get().(*zapFieldBuffer)
+	buf.merged = z.core.appendMergedFields(buf.merged, level, msg, z.fields, z.sites, ctx, fields)
+	buf.fields = z.appendZapFields(buf.fields[:0], buf.merged)
+	return buf
+}
 
-	// 添加已有的字段
-	for _, field := range z.fields {
-		zapFields = append(zapFields, zap.Any(field.Key, field.Value))
+// release 清除字段值的引用后将缓冲区放回池中，过大的缓冲区直接丢弃
+func (b *zapFieldBuffer) release() {
+	if cap(b.merged) > maxPooledFields || cap(b.fields) > maxPooledFields {
+		return
+	}
+	clear(b.merged)
+	clear(b.fields)
+	b.merged = b.merged[:0]
+	b.fields = b.fields[:0]
+	zapFieldPool.Put(b)
+}
+
+// appendZapFields 将合并后的字段转换为zap字段并追加到zapFields
+func (z *ZapLogger) appendZapFields(zapFields []zap.Field, allFields []Field) []zap.Field {
+	// 之后的字段都写入该命名空间对应的嵌套对象
+	if key := nestedFieldsKey(z.core.options); key != "" {
+		zapFields = append(zapFields, zap.Namespace(key))
 	}
 
-	// 添加新的字段
-	for _, field := range fields {
-		zapFields = append(zapFields, zap.Any(field.Key, field.Value))
+	for _, field := range allFields {
+		zapFields = append(zapFields, zapField(field.Key, field.Value))
 	}
 
 	return zapFields
 }
 
+// zapField 将字段转换为zap字段，error和fmt.Stringer使用专用的字段类型，避免反射其内部结构，
+// 实现了json.Marshaler的值按其JSON编码输出，time.Time输出为RFC3339格式，zap原生支持的其他类型（如time.Duration）仍由zap.Any处理
+func zapField(key string, value interface{}) zap.Field {
+	switch v := value.(type) {
+	case time.Time:
+		return zap.String(key, v.Format(time.RFC3339Nano))
+	case zapcore.ObjectMarshaler, zapcore.ArrayMarshaler, time.Duration:
+		return zap.Any(key, value)
+	case error:
+		return zap.NamedError(key, v)
+	case json.Marshaler:
+		return zap.Reflect(key, v)
+	case fmt.Stringer:
+		return zap.Stringer(key, v)
+	default:
+		return zap.Any(key, value)
+	}
+}
+
+// zapFatalHook 致命级日志写入后通过日志核心退出程序
+type zapFatalHook struct {
+	core *loggerCore
+	sync func() error // 包装已有zap实例时刷新该实例，输出不经过日志核心
+}
+
+// OnWrite 日志写入后退出程序
+func (h zapFatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	if h.sync != nil {
+		h.sync()
+	}
+	h.core.exit(1)
+}
+
+// zapClock 将日志核心的时钟适配为zapcore.Clock
+type zapClock struct {
+	core *loggerCore
+}
+
+// Now 获取当前时间
+func (c zapClock) Now() time.Time {
+	return c.core.now()
+}
+
+// NewTicker 创建定时器
+func (c zapClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+// MarshalLogObject 将嵌套字段编码为zap对象
+func (g fieldGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range g {
+		zapField(field.Key, field.Value).AddTo(enc)
+	}
+	return nil
+}
+
 // Debug 输出调试级日志
 func (z *ZapLogger) Debug(msg string, fields ...Field) {
-	if z.level <= DebugLevel {
-		z.logger.Debug(msg, z.toZapFields(fields)...)
+	if levelEnabled(z.level, z.ctx, DebugLevel) && z.core.allow(DebugLevel, z.fields, z.ctx, fields) {
+		zf := z.pooledZapFields(z.ctx, DebugLevel, msg, fields)
+		z.logger.Debug(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Debugf 输出格式化的调试级日志
 func (z *ZapLogger) Debugf(format string, args ...interface{}) {
-	if z.level <= DebugLevel {
-		z.logger.Sugar().Debugf(format, args...)
+	if levelEnabled(z.level, z.ctx, DebugLevel) && z.core.allow(DebugLevel, z.fields, z.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		zf := z.pooledZapFields(z.ctx, DebugLevel, msg, nil)
+		z.logger.Debug(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Info 输出信息级日志
 func (z *ZapLogger) Info(msg string, fields ...Field) {
-	if z.level <= InfoLevel {
-		z.logger.Info(msg, z.toZapFields(fields)...)
+	if levelEnabled(z.level, z.ctx, InfoLevel) && z.core.allow(InfoLevel, z.fields, z.ctx, fields) {
+		zf := z.pooledZapFields(z.ctx, InfoLevel, msg, fields)
+		z.logger.Info(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Infof 输出格式化的信息级日志
 func (z *ZapLogger) Infof(format string, args ...interface{}) {
-	if z.level <= InfoLevel {
-		z.logger.Sugar().Infof(format, args...)
+	if levelEnabled(z.level, z.ctx, InfoLevel) && z.core.allow(InfoLevel, z.fields, z.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		zf := z.pooledZapFields(z.ctx, InfoLevel, msg, nil)
+		z.logger.Info(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Warn 输出警告级日志
 func (z *ZapLogger) Warn(msg string, fields ...Field) {
-	if z.level <= WarnLevel {
-		z.logger.Warn(msg, z.toZapFields(fields)...)
+	if levelEnabled(z.level, z.ctx, WarnLevel) && z.core.allow(WarnLevel, z.fields, z.ctx, fields) {
+		zf := z.pooledZapFields(z.ctx, WarnLevel, msg, fields)
+		z.logger.Warn(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Warnf 输出格式化的警告级日志
 func (z *ZapLogger) Warnf(format string, args ...interface{}) {
-	if z.level <= WarnLevel {
-		z.logger.Sugar().Warnf(format, args...)
+	if levelEnabled(z.level, z.ctx, WarnLevel) && z.core.allow(WarnLevel, z.fields, z.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		zf := z.pooledZapFields(z.ctx, WarnLevel, msg, nil)
+		z.logger.Warn(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Error 输出错误级日志
 func (z *ZapLogger) Error(msg string, fields ...Field) {
-	if z.level <= ErrorLevel {
-		z.logger.Error(msg, z.toZapFields(fields)...)
+	if levelEnabled(z.level, z.ctx, ErrorLevel) && z.core.allow(ErrorLevel, z.fields, z.ctx, fields) {
+		zf := z.pooledZapFields(z.ctx, ErrorLevel, msg, fields)
+		z.logger.Error(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Errorf 输出格式化的错误级日志
 func (z *ZapLogger) Errorf(format string, args ...interface{}) {
-	if z.level <= ErrorLevel {
-		z.logger.Sugar().Errorf(format, args...)
+	if levelEnabled(z.level, z.ctx, ErrorLevel) && z.core.allow(ErrorLevel, z.fields, z.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		zf := z.pooledZapFields(z.ctx, ErrorLevel, msg, nil)
+		z.logger.Error(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
 	}
 }
 
 // Fatal 输出致命级日志并退出程序
 func (z *ZapLogger) Fatal(msg string, fields ...Field) {
-	if z.level <= FatalLevel {
-		z.logger.Fatal(msg, z.toZapFields(fields)...)
-		os.Exit(1)
+	if levelEnabled(z.level, z.ctx, FatalLevel) && z.core.allow(FatalLevel, z.fields, z.ctx, fields) {
+		z.logger.Fatal(z.core.redactMessage(msg), z.toZapFields(z.ctx, FatalLevel, msg, fields)...)
 	}
 }
 
 // Fatalf 输出格式化的致命级日志并退出程序
 func (z *ZapLogger) Fatalf(format string, args ...interface{}) {
-	if z.level <= FatalLevel {
-		z.logger.Sugar().Fatalf(format, args...)
-		os.Exit(1)
+	if levelEnabled(z.level, z.ctx, FatalLevel) && z.core.allow(FatalLevel, z.fields, z.ctx, nil) {
+		msg := fmt.Sprintf(format, args...)
+		z.logger.Fatal(z.core.redactMessage(msg), z.toZapFields(z.ctx, FatalLevel, msg, nil)...)
 	}
 }
 
 // Panic 输出恐慌级日志并触发panic
 func (z *ZapLogger) Panic(msg string, fields ...Field) {
-	if z.level <= PanicLevel {
-		z.logger.Panic(msg, z.toZapFields(fields)...)
+	if levelEnabled(z.level, z.ctx, PanicLevel) && z.core.allow(PanicLevel, z.fields, z.ctx, fields) {
+		msg, zapFields := z.panicFields(z.ctx, msg, fields)
+		z.logger.Panic(msg, zapFields...)
 	}
 }
 
 // Panicf 输出格式化的恐慌级日志并触发panic
 func (z *ZapLogger) Panicf(format string, args ...interface{}) {
-	if z.level <= PanicLevel {
-		z.logger.Sugar().Panicf(format, args...)
+	if levelEnabled(z.level, z.ctx, PanicLevel) && z.core.allow(PanicLevel, z.fields, z.ctx, nil) {
+		msg, zapFields := z.panicFields(z.ctx, fmt.Sprintf(format, args...), nil)
+		z.logger.Panic(msg, zapFields...)
+	}
+}
+
+// panicFields 准备恐慌级日志的消息和zap字段，配置了崩溃转储文件时先写入崩溃转储。
+// zap.Logger.Panic由各Panic方法直接调用，以保持caller字段指向调用方
+func (z *ZapLogger) panicFields(ctx context.Context, msg string, fields []Field) (string, []zap.Field) {
+	allFields := z.core.mergeFields(PanicLevel, msg, z.fields, z.sites, ctx, fields)
+	msg = z.core.redactMessage(msg)
+	if z.core.crashDumpEnabled() {
+		z.core.writeCrashDump(z.core.crashEntry(z.name, msg, allFields))
+	}
+	return msg, z.zapFields(allFields)
+}
+
+// DebugCtx 使用ctx中提取的字段输出调试级日志
+func (z *ZapLogger) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(z.level, ctx, DebugLevel) && z.core.allow(DebugLevel, z.fields, ctx, fields) {
+		zf := z.pooledZapFields(ctx, DebugLevel, msg, fields)
+		z.logger.Debug(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
+	}
+}
+
+// InfoCtx 使用ctx中提取的字段输出信息级日志
+func (z *ZapLogger) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(z.level, ctx, InfoLevel) && z.core.allow(InfoLevel, z.fields, ctx, fields) {
+		zf := z.pooledZapFields(ctx, InfoLevel, msg, fields)
+		z.logger.Info(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
+	}
+}
+
+// WarnCtx 使用ctx中提取的字段输出警告级日志
+func (z *ZapLogger) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(z.level, ctx, WarnLevel) && z.core.allow(WarnLevel, z.fields, ctx, fields) {
+		zf := z.pooledZapFields(ctx, WarnLevel, msg, fields)
+		z.logger.Warn(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
+	}
+}
+
+// ErrorCtx 使用ctx中提取的字段输出错误级日志
+func (z *ZapLogger) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(z.level, ctx, ErrorLevel) && z.core.allow(ErrorLevel, z.fields, ctx, fields) {
+		zf := z.pooledZapFields(ctx, ErrorLevel, msg, fields)
+		z.logger.Error(z.core.redactMessage(msg), zf.fields...)
+		zf.release()
+	}
+}
+
+// FatalCtx 使用ctx中提取的字段输出致命级日志并退出程序
+func (z *ZapLogger) FatalCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(z.level, ctx, FatalLevel) && z.core.allow(FatalLevel, z.fields, ctx, fields) {
+		z.logger.Fatal(z.core.redactMessage(msg), z.toZapFields(ctx, FatalLevel, msg, fields)...)
+	}
+}
+
+// PanicCtx 使用ctx中提取的字段输出恐慌级日志并触发panic
+func (z *ZapLogger) PanicCtx(ctx context.Context, msg string, fields ...Field) {
+	if levelEnabled(z.level, ctx, PanicLevel) && z.core.allow(PanicLevel, z.fields, ctx, fields) {
+		msg, zapFields := z.panicFields(ctx, msg, fields)
+		z.logger.Panic(msg, zapFields...)
 	}
 }
 
 // WithFields 添加字段到日志
 func (z *ZapLogger) WithFields(fields ...Field) Logger {
 	newLogger := *z
+	// 字段在每次输出时统一处理，不附加到zap.Logger，避免重复输出
+	newLogger.fields = make([]Field, 0, len(z.fields)+len(fields))
+	newLogger.fields = append(newLogger.fields, z.fields...)
 	newLogger.fields = append(newLogger.fields, fields...)
-	// 创建新的zap.Logger实例
-	zapFields := make([]zap.Field, 0, len(newLogger.fields))
-	for _, field := range newLogger.fields {
-		zapFields = append(zapFields, zap.Any(field.Key, field.Value))
-	}
-	newLogger.logger = z.logger.With(zapFields...)
+	newLogger.sites = z.core.traceFields(z.sites, fields)
 	return &newLogger
 }
 
@@ -243,6 +476,25 @@ func (z *ZapLogger) WithField(key string, value interface{}) Logger {
 	return z.WithFields(Field{Key: key, Value: value})
 }
 
+// Clone 以新的名称复制日志实例，保留级别、字段、上下文和输出配置
+func (z *ZapLogger) Clone(name string) Logger {
+	newLogger := *z
+	newLogger.name = name
+	newLogger.fields = append([]Field(nil), z.fields...)
+	newLogger.sites = append([]string(nil), z.sites...)
+	newLogger.core = z.core.clone()
+	if !z.wrapped {
+		newLogger.base = newZapBase(newLogger.core.options, newLogger.core)
+	}
+	newLogger.logger = newLogger.base.Named(name)
+	return &newLogger
+}
+
+// WithStruct 将结构体的导出字段展开为 prefix.FieldName 形式的字段添加到日志
+func (z *ZapLogger) WithStruct(prefix string, v interface{}) Logger {
+	return z.WithFields(StructFields(prefix, v)...)
+}
+
 // WithContext 添加上下文到日志
 func (z *ZapLogger) WithContext(ctx context.Context) Logger {
 	newLogger := *z
@@ -250,8 +502,11 @@ func (z *ZapLogger) WithContext(ctx context.Context) Logger {
 	return &newLogger
 }
 
-// WithError 添加错误信息到日志
+// WithError 添加错误信息到日志，err为nil时返回当前日志实例
 func (z *ZapLogger) WithError(err error) Logger {
+	if err == nil {
+		return z
+	}
 	return z.WithField("error", err)
 }
 
@@ -290,9 +545,84 @@ func (z *ZapLogger) IsPanicEnabled() bool {
 	return z.level <= PanicLevel
 }
 
-// Sync 刷新日志缓冲区
+// IsEnabled 检查level级别是否启用，与实际输出日志时一样考虑上下文中的级别覆盖
+func (z *ZapLogger) IsEnabled(level LogLevel) bool {
+	return levelEnabled(z.level, z.ctx, level)
+}
+
+// SetOutput 替换日志输出目标，派生的日志实例同时生效，原输出目标不会被关闭
+func (z *ZapLogger) SetOutput(w io.Writer) {
+	z.core.setOutput(w)
+}
+
+// Stats 获取日志输出统计
+func (z *ZapLogger) Stats() LoggerStats {
+	stats := z.core.stats.snapshot()
+	stats.FieldSuppressed = z.core.fieldSuppressed()
+	return stats
+}
+
+// ResetSampling 清空按字段值限流的计数窗口，被抑制的消息可以立即再次输出，派生的日志实例同时生效。
+// 适合在每个请求或测试开始时调用，避免上一阶段的限流状态影响下一阶段，统计中累计的抑制条数不受影响
+func (z *ZapLogger) ResetSampling() {
+	z.core.resetSampling()
+}
+
+// StartTimer 将uptime字段的起始时间重置为当前时间，派生的日志实例同时生效
+func (z *ZapLogger) StartTimer() {
+	z.core.startTimer()
+}
+
+// Describe 获取日志实例的有效配置信息
+func (z *ZapLogger) Describe() LoggerInfo {
+	return z.core.describe("zap", z.name, z.level)
+}
+
+// Sync 输出采样汇总并刷新日志缓冲区
 func (z *ZapLogger) Sync() error {
-	return z.logger.Sync()
+	if level, fields, ok := z.core.samplingSummary(); ok && z.level <= level {
+		zapFields := z.toZapFields(z.ctx, level, samplingSummaryMessage, fields)
+		switch level {
+		case DebugLevel:
+			z.logger.Debug(samplingSummaryMessage, zapFields...)
+		case InfoLevel:
+			z.logger.Info(samplingSummaryMessage, zapFields...)
+		case WarnLevel:
+			z.logger.Warn(samplingSummaryMessage, zapFields...)
+		default:
+			z.logger.Error(samplingSummaryMessage, zapFields...)
+		}
+	}
+	return filterBenignSyncErrors(z.logger.Sync())
+}
+
+// zapMultiOutput 使用zapcore.NewMultiWriteSyncer同时写入多个输出目标，并支持关闭各个输出目标
+type zapMultiOutput struct {
+	zapcore.WriteSyncer
+	outputs []io.Writer
+}
+
+// newZapMultiOutput 将多个输出目标组合为zap的MultiWriteSyncer，不支持刷新的输出目标的Sync为空操作
+func newZapMultiOutput(outputs []io.Writer) io.Writer {
+	syncers := make([]zapcore.WriteSyncer, len(outputs))
+	for i, output := range outputs {
+		syncers[i] = zapcore.AddSync(output)
+	}
+	return &zapMultiOutput{
+		WriteSyncer: zapcore.NewMultiWriteSyncer(syncers...),
+		outputs:     outputs,
+	}
+}
+
+// Close 关闭所有输出目标，返回第一个错误
+func (m *zapMultiOutput) Close() error {
+	return multiOutput(m.outputs).Close()
+}
+
+// Close 刷新并关闭日志输出，标准输出不会被关闭
+func (z *ZapLogger) Close() error {
+	z.Sync()
+	return z.core.close()
 }
 
 // ZapLoggerProvider zap日志提供者
@@ -338,3 +668,66 @@ func (p *ZapLoggerProvider) CreateWithConfig(name string, config map[string]inte
 		WithConfig(config),
 	)
 }
+
+// zapNoStacktrace 对任何级别都不附加stacktrace的zap级别判断
+type zapNoStacktrace struct{}
+
+// Enabled 实现zapcore.LevelEnabler接口
+func (zapNoStacktrace) Enabled(zapcore.Level) bool { return false }
+
+// zapLevelEncoder 将自定义级别编码函数适配为zap的级别编码器
+func zapLevelEncoder(encoder LevelEncoder) zapcore.LevelEncoder {
+	return func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		switch value := encoder(fromZapLevel(level)).(type) {
+		case string:
+			enc.AppendString(value)
+		case int:
+			enc.AppendInt(value)
+		case int64:
+			enc.AppendInt64(value)
+		case uint:
+			enc.AppendUint(value)
+		case float64:
+			enc.AppendFloat64(value)
+		case bool:
+			enc.AppendBool(value)
+		default:
+			if arr, ok := enc.(zapcore.ArrayEncoder); ok {
+				if err := arr.AppendReflected(value); err == nil {
+					return
+				}
+			}
+			enc.AppendString(formatValue(value))
+		}
+	}
+}
+
+// fromZapLevel 将zap日志级别转换为日志门面级别
+func fromZapLevel(level zapcore.Level) LogLevel {
+	switch level {
+	case zapcore.DebugLevel:
+		return DebugLevel
+	case zapcore.WarnLevel:
+		return WarnLevel
+	case zapcore.ErrorLevel:
+		return ErrorLevel
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return PanicLevel
+	case zapcore.FatalLevel:
+		return FatalLevel
+	default:
+		return InfoLevel
+	}
+}
+
+// zapDurationEncoder 将时长编码为指定单位下的数值
+func zapDurationEncoder(unit time.Duration) zapcore.DurationEncoder {
+	return func(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+		switch value := durationValue(d, unit).(type) {
+		case int64:
+			enc.AppendInt64(value)
+		case float64:
+			enc.AppendFloat64(value)
+		}
+	}
+}
@@ -0,0 +1,714 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/adapters"
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// TestLokiWriterPush 测试Loki推送的请求体结构和标签
+func TestLokiWriterPush(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		bodies [][]byte
+		path   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, body)
+		path = r.URL.Path
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	writer := adapters.NewLokiWriter(server.URL, map[string]string{"job": "api", "service": "user"},
+		adapters.WithLokiFlushInterval(0),
+	)
+	writer.Write([]byte(`{"msg":"first"}` + "\n"))
+	writer.Write([]byte(`{"msg":"second"}` + "\n"))
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(bodies) != 1 {
+		t.Fatalf("Expected 1 push, got %d", len(bodies))
+	}
+	if path != "/loki/api/v1/push" {
+		t.Errorf("Expected push path, got %s", path)
+	}
+
+	var payload struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(bodies[0], &payload); err != nil {
+		t.Fatalf("Invalid payload: %v", err)
+	}
+	if len(payload.Streams) != 1 {
+		t.Fatalf("Expected 1 stream, got %d", len(payload.Streams))
+	}
+
+	stream := payload.Streams[0]
+	if stream.Stream["job"] != "api" || stream.Stream["service"] != "user" || len(stream.Stream) != 2 {
+		t.Errorf("Unexpected labels: %v", stream.Stream)
+	}
+	if len(stream.Values) != 2 {
+		t.Fatalf("Expected 2 values, got %d", len(stream.Values))
+	}
+	if len(stream.Values[0]) != 2 || stream.Values[0][1] != `{"msg":"first"}` {
+		t.Errorf("Unexpected value: %v", stream.Values[0])
+	}
+}
+
+// TestLokiWriterDropsOnFailure 测试推送持续失败时重试并计数丢弃
+func TestLokiWriterDropsOnFailure(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	writer := adapters.NewLokiWriter(server.URL, map[string]string{"job": "api"},
+		adapters.WithLokiFlushInterval(0),
+		adapters.WithLokiRetry(2, time.Millisecond),
+	)
+	writer.Write([]byte("line 1\n"))
+	writer.Write([]byte("line 2\n"))
+
+	if err := writer.Sync(); err == nil {
+		t.Error("Expected Sync to fail")
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("Expected 3 attempts, got %d", got)
+	}
+	if writer.Dropped() != 2 {
+		t.Errorf("Expected 2 dropped, got %d", writer.Dropped())
+	}
+}
+
+// mockCloudWatchClient 记录请求的CloudWatch Logs客户端，日志流创建前推送返回ResourceNotFoundException
+type mockCloudWatchClient struct {
+	mu             sync.Mutex
+	streamCreated  bool
+	createCalls    int
+	batches        [][]string
+	tokens         []string
+	rejectNextWith *string // 非空时下一次推送返回InvalidSequenceTokenException
+}
+
+func (c *mockCloudWatchClient) PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.streamCreated {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("stream does not exist")}
+	}
+	if c.rejectNextWith != nil {
+		expected := c.rejectNextWith
+		c.rejectNextWith = nil
+		return nil, &types.InvalidSequenceTokenException{ExpectedSequenceToken: expected}
+	}
+
+	messages := make([]string, len(params.LogEvents))
+	for i, event := range params.LogEvents {
+		messages[i] = aws.ToString(event.Message)
+	}
+	c.batches = append(c.batches, messages)
+	c.tokens = append(c.tokens, aws.ToString(params.SequenceToken))
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String(fmt.Sprintf("token-%d", len(c.batches)))}, nil
+}
+
+func (c *mockCloudWatchClient) CreateLogStream(ctx context.Context, params *cloudwatchlogs.CreateLogStreamInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.createCalls++
+	if aws.ToString(params.LogGroupName) != "app" || aws.ToString(params.LogStreamName) != "web-1" {
+		return nil, fmt.Errorf("unexpected stream %s/%s", aws.ToString(params.LogGroupName), aws.ToString(params.LogStreamName))
+	}
+	c.streamCreated = true
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+// TestCloudWatchWriterCreatesStream 测试日志流不存在时自动创建，并按返回的序列号继续推送
+func TestCloudWatchWriterCreatesStream(t *testing.T) {
+	client := &mockCloudWatchClient{}
+	writer := adapters.NewCloudWatchWriter("app", "web-1", aws.Config{},
+		adapters.WithCloudWatchClient(client),
+		adapters.WithCloudWatchFlushInterval(0),
+	)
+
+	writer.Write([]byte(`{"msg":"first"}` + "\n"))
+	writer.Write([]byte(`{"msg":"second"}` + "\n"))
+	if err := writer.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	// 序列号失效时使用服务端返回的序列号重试
+	client.rejectNextWith = aws.String("expected-token")
+	writer.Write([]byte(`{"msg":"third"}` + "\n"))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if client.createCalls != 1 {
+		t.Errorf("Expected the stream to be created once, got %d", client.createCalls)
+	}
+	if len(client.batches) != 2 || len(client.batches[0]) != 2 || client.batches[0][1] != `{"msg":"second"}` {
+		t.Fatalf("Unexpected batches %q", client.batches)
+	}
+	if client.tokens[0] != "" || client.tokens[1] != "expected-token" {
+		t.Errorf("Unexpected sequence tokens %q", client.tokens)
+	}
+	if writer.Dropped() != 0 {
+		t.Errorf("Expected nothing dropped, got %d", writer.Dropped())
+	}
+}
+
+// TestCloudWatchWriterBatchLimits 测试按单批条数和字节数限制拆分推送
+func TestCloudWatchWriterBatchLimits(t *testing.T) {
+	client := &mockCloudWatchClient{streamCreated: true}
+	writer := adapters.NewCloudWatchWriter("app", "web-1", aws.Config{},
+		adapters.WithCloudWatchClient(client),
+		adapters.WithCloudWatchFlushInterval(0),
+	)
+
+	// 达到10000条时立即推送
+	for i := 0; i < 10001; i++ {
+		writer.Write([]byte(fmt.Sprintf("line %d\n", i)))
+	}
+	if len(client.batches) != 1 || len(client.batches[0]) != 10000 {
+		t.Fatalf("Expected one full batch of 10000 events, got %d batches", len(client.batches))
+	}
+	writer.Sync()
+
+	// 每条300KB，单批最多容纳3条
+	large := strings.Repeat("x", 300*1024)
+	for i := 0; i < 4; i++ {
+		writer.Write([]byte(large + "\n"))
+	}
+	writer.Close()
+
+	var sizes []int
+	for _, batch := range client.batches {
+		sizes = append(sizes, len(batch))
+	}
+	if fmt.Sprint(sizes) != "[10000 1 3 1]" {
+		t.Errorf("Unexpected batch sizes %v", sizes)
+	}
+}
+
+// TestProtoField 测试protobuf消息字段在JSON输出中为protojson对象
+func TestProtoField(t *testing.T) {
+	msg, err := structpb.NewStruct(map[string]interface{}{"user": "alice", "count": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	for name, log := range loggers {
+		log.Info("rpc", adapters.ProtoField("req", msg))
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		req, ok := data["req"].(map[string]interface{})
+		if !ok || req["user"] != "alice" || req["count"] != float64(2) {
+			t.Errorf("%s: expected protojson object, got %v", name, data["req"])
+		}
+	}
+}
+
+// TestBaggageExtractor 测试只提取允许列表中的baggage条目
+func TestBaggageExtractor(t *testing.T) {
+	tenant, _ := baggage.NewMember("tenant", "acme")
+	plan, _ := baggage.NewMember("plan", "gold")
+	secret, _ := baggage.NewMember("session", "s3cr3t")
+	bag, err := baggage.New(tenant, plan, secret)
+	if err != nil {
+		t.Fatalf("baggage.New failed: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app",
+		logger.WithFormat("json"),
+		logger.WithOutputPath(path),
+		logger.WithContextExtractor(adapters.BaggageExtractor("tenant", "plan", "region")),
+	)
+	log.InfoCtx(baggage.ContextWithBaggage(context.Background(), bag), "checkout")
+	log.Sync()
+
+	data := decodeJSONLine(t, readLines(t, path)[0])
+	if data["tenant"] != "acme" || data["plan"] != "gold" {
+		t.Errorf("Expected allowed baggage entries, got %v", data)
+	}
+	if _, ok := data["session"]; ok {
+		t.Errorf("Disallowed baggage entry leaked: %v", data)
+	}
+	if _, ok := data["region"]; ok {
+		t.Errorf("Missing baggage entry should be skipped: %v", data)
+	}
+}
+
+// TestTraceExtractor 测试trace_sampled字段与span上下文的采样标志一致，没有span上下文时不添加追踪字段
+func TestTraceExtractor(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+
+	cases := []struct {
+		name    string
+		flags   trace.TraceFlags
+		sampled bool
+	}{
+		{name: "sampled", flags: trace.FlagsSampled, sampled: true},
+		{name: "unsampled", flags: 0, sampled: false},
+	}
+
+	for _, tc := range cases {
+		rec := &recorder{}
+		log := logger.NewFuncLogger("app", logger.DebugLevel, rec.emit, logger.WithContextExtractor(adapters.TraceExtractor()))
+		sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID, TraceFlags: tc.flags})
+		log.WithContext(trace.ContextWithSpanContext(context.Background(), sc)).Info("checkout")
+
+		fields := rec.calls[0].fields
+		if fieldValue(fields, "trace_sampled") != tc.sampled {
+			t.Errorf("%s: expected trace_sampled=%v, got %v", tc.name, tc.sampled, fieldValue(fields, "trace_sampled"))
+		}
+		if fieldValue(fields, "trace_id") != traceID.String() || fieldValue(fields, "span_id") != spanID.String() {
+			t.Errorf("%s: unexpected trace fields %+v", tc.name, fields)
+		}
+	}
+
+	rec := &recorder{}
+	log := logger.NewFuncLogger("app", logger.DebugLevel, rec.emit, logger.WithContextExtractor(adapters.TraceExtractor()))
+	log.WithContext(context.Background()).Info("no span")
+	if len(rec.calls[0].fields) != 0 {
+		t.Errorf("Expected no trace fields without a span context, got %+v", rec.calls[0].fields)
+	}
+}
+
+// spanEvent 记录的span事件
+type spanEvent struct {
+	name  string
+	attrs []attribute.KeyValue
+}
+
+// recordingSpan 记录AddEvent调用的span
+type recordingSpan struct {
+	noop.Span
+	events []spanEvent
+}
+
+func (s *recordingSpan) IsRecording() bool { return true }
+
+func (s *recordingSpan) AddEvent(name string, opts ...trace.EventOption) {
+	cfg := trace.NewEventConfig(opts...)
+	s.events = append(s.events, spanEvent{name: name, attrs: cfg.Attributes()})
+}
+
+// TestSpanEventHook 测试级别不低于minLevel的日志在上下文中的span上添加事件，字段转换为事件属性
+func TestSpanEventHook(t *testing.T) {
+	span := &recordingSpan{}
+	ctx := trace.ContextWithSpan(context.Background(), span)
+
+	rec := &recorder{}
+	log := logger.NewFuncLogger("app", logger.DebugLevel, rec.emit, logger.WithEntryHook(adapters.SpanEventHook(logger.ErrorLevel)))
+	log.InfoCtx(ctx, "cache miss")
+	log.WithField("order_id", "o-1").ErrorCtx(ctx, "payment failed",
+		logger.Field{Key: "attempt", Value: 3},
+		logger.Field{Key: "err", Value: fmt.Errorf("card declined")},
+		logger.Group("http", logger.Field{Key: "status", Value: 502}),
+	)
+	log.Error("no span")
+
+	if len(span.events) != 1 {
+		t.Fatalf("Expected 1 span event, got %d", len(span.events))
+	}
+	event := span.events[0]
+	if event.name != "payment failed" {
+		t.Errorf("Expected event named after the message, got %q", event.name)
+	}
+
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range event.attrs {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["level"].AsString() != "ERROR" {
+		t.Errorf("Expected level=ERROR, got %v", attrs["level"].Emit())
+	}
+	if attrs["order_id"].AsString() != "o-1" || attrs["attempt"].AsInt64() != 3 {
+		t.Errorf("Unexpected attributes %v", event.attrs)
+	}
+	if attrs["err"].AsString() != "card declined" || attrs["http.status"].AsInt64() != 502 {
+		t.Errorf("Unexpected attributes %v", event.attrs)
+	}
+	if len(rec.calls) != 3 {
+		t.Errorf("Expected all entries to be logged, got %d", len(rec.calls))
+	}
+}
+
+// fakeTB 记录Log和Fatal调用的testing.TB
+type fakeTB struct {
+	testing.TB
+	logs   []string
+	fatals []string
+}
+
+func (f *fakeTB) Name() string { return "TestFake" }
+func (f *fakeTB) Helper()      {}
+
+func (f *fakeTB) Log(args ...interface{}) { f.logs = append(f.logs, fmt.Sprint(args...)) }
+
+func (f *fakeTB) Logf(format string, args ...interface{}) {
+	f.logs = append(f.logs, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeTB) Fatal(args ...interface{}) { f.fatals = append(f.fatals, fmt.Sprint(args...)) }
+
+// TestTestLogger 测试日志通过tb.Log输出，致命级日志调用tb.Fatal
+func TestTestLogger(t *testing.T) {
+	tb := &fakeTB{}
+	log := adapters.NewTestLogger(tb)
+
+	log.Debug("loading fixtures")
+	log.WithField("user", "alice").Info("created")
+	log.Errorf("retry %d", 2)
+	log.Fatal("cannot continue", logger.Field{Key: "code", Value: 3})
+
+	if len(tb.logs) != 3 {
+		t.Fatalf("Expected 3 logs, got %q", tb.logs)
+	}
+	for i, expected := range []string{"[DEBUG] [TestFake] loading fixtures", "[INFO] [TestFake] created user=alice", "[ERROR] [TestFake] retry 2"} {
+		if !strings.HasSuffix(tb.logs[i], expected) {
+			t.Errorf("Expected log %d to end with %q, got %q", i, expected, tb.logs[i])
+		}
+	}
+	if len(tb.fatals) != 1 || !strings.HasSuffix(tb.fatals[0], "[FATAL] [TestFake] cannot continue code=3") {
+		t.Errorf("Expected Fatal to call tb.Fatal, got %q", tb.fatals)
+	}
+
+	// 与真实测试配合使用
+	adapters.NewTestLogger(t).Info("shown only on failure or with -v")
+}
+
+// fieldValue 获取字段列表中指定键的最后一个值
+func fieldValue(fields []logger.Field, key string) interface{} {
+	var value interface{}
+	for _, field := range fields {
+		if field.Key == key {
+			value = field.Value
+		}
+	}
+	return value
+}
+
+// TestGinRequestID 测试请求携带的请求ID被沿用，没有时生成UUID，请求ID通过响应头返回并出现在请求日志中
+func TestGinRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	cases := []struct {
+		name     string
+		header   string
+		incoming string
+		opts     []adapters.GinOption
+	}{
+		{name: "present", header: "X-Request-ID", incoming: "req-123"},
+		{name: "absent", header: "X-Request-ID"},
+		{name: "custom header", header: "X-Correlation-ID", incoming: "corr-9", opts: []adapters.GinOption{adapters.WithGinRequestIDHeader("X-Correlation-ID")}},
+	}
+
+	for _, tc := range cases {
+		rec := &recorder{}
+		log := logger.NewFuncLogger("gin", logger.DebugLevel, rec.emit)
+		r := gin.New()
+		adapters.UseWithGin(r, log, tc.opts...)
+		r.GET("/orders", func(c *gin.Context) {
+			adapters.GinRequestLogger(c).Info("handler")
+			log.InfoCtx(c.Request.Context(), "service")
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		if tc.incoming != "" {
+			req.Header.Set(tc.header, tc.incoming)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		id := w.Header().Get(tc.header)
+		if tc.incoming != "" && id != tc.incoming {
+			t.Errorf("%s: expected echoed request id %q, got %q", tc.name, tc.incoming, id)
+		}
+		if tc.incoming == "" && !uuidPattern.MatchString(id) {
+			t.Errorf("%s: expected a generated UUID, got %q", tc.name, id)
+		}
+
+		if len(rec.calls) != 3 {
+			t.Fatalf("%s: expected 3 log calls, got %+v", tc.name, rec.calls)
+		}
+		for _, call := range rec.calls {
+			if got := fieldValue(call.fields, "request_id"); got != id {
+				t.Errorf("%s: expected request_id %q on %q, got %v", tc.name, id, call.msg, got)
+			}
+		}
+	}
+}
+
+// TestGinLogHeaders 测试配置的请求头和响应头以headers字段输出，敏感头被脱敏
+func TestGinLogHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		name     string
+		opts     []adapters.GinOption
+		request  map[string]string
+		response map[string]string
+	}{
+		{
+			name: "default redaction",
+			opts: []adapters.GinOption{adapters.WithGinLogHeaders("user-agent", "Authorization", "Cookie", "Content-Type", "Set-Cookie", "X-Missing")},
+			request: map[string]string{
+				"User-Agent":    "client/1.0",
+				"Authorization": "[REDACTED]",
+				"Cookie":        "[REDACTED]",
+			},
+			response: map[string]string{
+				"Content-Type": "application/json",
+				"Set-Cookie":   "[REDACTED]",
+			},
+		},
+		{
+			name: "custom redaction",
+			opts: []adapters.GinOption{
+				adapters.WithGinLogHeaders("Authorization", "X-Api-Key"),
+				adapters.WithGinRedactHeaders("x-api-key"),
+			},
+			request: map[string]string{
+				"Authorization": "Bearer token",
+				"X-Api-Key":     "[REDACTED]",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		rec := &recorder{}
+		log := logger.NewFuncLogger("gin", logger.DebugLevel, rec.emit)
+		r := gin.New()
+		adapters.UseWithGin(r, log, tc.opts...)
+		r.GET("/orders", func(c *gin.Context) {
+			c.Header("Set-Cookie", "session=secret")
+			c.Header("Content-Type", "application/json")
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.Header.Set("User-Agent", "client/1.0")
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Set("Cookie", "session=secret")
+		req.Header.Set("X-Api-Key", "key-123")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+
+		if len(rec.calls) != 1 {
+			t.Fatalf("%s: expected 1 log call, got %+v", tc.name, rec.calls)
+		}
+		headers, ok := fieldValue(rec.calls[0].fields, "headers").(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s: expected a headers field, got %+v", tc.name, rec.calls[0].fields)
+		}
+		for section, expected := range map[string]map[string]string{"request": tc.request, "response": tc.response} {
+			got, _ := headers[section].(map[string]string)
+			if len(got) != len(expected) {
+				t.Errorf("%s: expected %s headers %v, got %v", tc.name, section, expected, got)
+			}
+			for k, v := range expected {
+				if got[k] != v {
+					t.Errorf("%s: expected %s header %s=%q, got %q", tc.name, section, k, v, got[k])
+				}
+			}
+		}
+	}
+
+	rec := &recorder{}
+	r := gin.New()
+	adapters.UseWithGin(r, logger.NewFuncLogger("gin", logger.DebugLevel, rec.emit))
+	r.GET("/orders", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	if len(rec.calls) != 1 || fieldValue(rec.calls[0].fields, "headers") != nil {
+		t.Errorf("Expected no headers field by default, got %+v", rec.calls)
+	}
+}
+
+// TestGinLogQuery 测试访问日志以query字段记录查询参数，敏感参数被脱敏，超过上限的参数被省略，uri不再包含原始查询串
+func TestGinLogQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rec := &recorder{}
+	r := gin.New()
+	adapters.UseWithGin(r, logger.NewFuncLogger("gin", logger.DebugLevel, rec.emit), adapters.WithGinLogQuery(3))
+	r.GET("/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search?q=go&tag=a&tag=b&Token=secret&api_key=key-123&z=1", nil))
+	if len(rec.calls) != 1 {
+		t.Fatalf("Expected 1 log call, got %+v", rec.calls)
+	}
+	fields := rec.calls[0].fields
+	query, ok := fieldValue(fields, "query").(map[string]string)
+	if !ok {
+		t.Fatalf("Expected a query field, got %+v", fields)
+	}
+	expected := map[string]string{"Token": "[REDACTED]", "api_key": "[REDACTED]", "q": "go"}
+	if len(query) != len(expected) {
+		t.Errorf("Expected query %v, got %v", expected, query)
+	}
+	for k, v := range expected {
+		if query[k] != v {
+			t.Errorf("Expected query param %s=%q, got %q", k, v, query[k])
+		}
+	}
+	if truncated := fieldValue(fields, "query_truncated"); truncated != 2 {
+		t.Errorf("Expected 2 truncated query params, got %v", truncated)
+	}
+	if uri := fieldValue(fields, "uri"); uri != "/search" || strings.Contains(rec.calls[0].msg, "secret") {
+		t.Errorf("Expected uri and message without the raw query, got uri=%v msg=%q", uri, rec.calls[0].msg)
+	}
+
+	rec.calls = nil
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic?token=secret", nil))
+	for _, call := range rec.calls {
+		if uri := fieldValue(call.fields, "uri"); uri != "/panic" {
+			t.Errorf("%q: expected uri without the raw query, got %v", call.msg, uri)
+		}
+	}
+
+	// 自定义脱敏参数，未开启时不记录query字段
+	rec.calls = nil
+	r = gin.New()
+	adapters.UseWithGin(r, logger.NewFuncLogger("gin", logger.DebugLevel, rec.emit), adapters.WithGinLogQuery(0), adapters.WithGinRedactQuery("session"))
+	r.GET("/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search?token=t1&session=s1", nil))
+	query, _ = fieldValue(rec.calls[0].fields, "query").(map[string]string)
+	if query["token"] != "t1" || query["session"] != "[REDACTED]" {
+		t.Errorf("Expected only the custom param to be redacted, got %v", query)
+	}
+
+	rec.calls = nil
+	r = gin.New()
+	adapters.UseWithGin(r, logger.NewFuncLogger("gin", logger.DebugLevel, rec.emit))
+	r.GET("/search", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/search?q=go", nil))
+	if fieldValue(rec.calls[0].fields, "query") != nil || fieldValue(rec.calls[0].fields, "uri") != "/search?q=go" {
+		t.Errorf("Expected no query field by default, got %+v", rec.calls[0].fields)
+	}
+}
+
+// TestGinRoute 测试访问日志和恢复日志同时包含具体的uri和路由模板route
+func TestGinRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rec := &recorder{}
+	r := gin.New()
+	adapters.UseWithGin(r, logger.NewFuncLogger("gin", logger.DebugLevel, rec.emit))
+	r.GET("/users/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/orders/:id/items", func(c *gin.Context) { panic("boom") })
+
+	for _, uri := range []string{"/users/42?verbose=1", "/orders/7/items", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, uri, nil))
+	}
+
+	expected := []struct{ uri, route string }{
+		{"/users/42?verbose=1", "/users/:id"},
+		{"/orders/7/items", "/orders/:id/items"}, // 恢复日志
+		{"/orders/7/items", "/orders/:id/items"}, // 访问日志
+		{"/missing", ""},
+	}
+	if len(rec.calls) != len(expected) {
+		t.Fatalf("Expected %d log calls, got %+v", len(expected), rec.calls)
+	}
+	for i, e := range expected {
+		fields := rec.calls[i].fields
+		if fieldValue(fields, "uri") != e.uri || fieldValue(fields, "route") != e.route {
+			t.Errorf("%q: expected uri=%q route=%q, got uri=%v route=%v", rec.calls[i].msg, e.uri, e.route, fieldValue(fields, "uri"), fieldValue(fields, "route"))
+		}
+	}
+}
+
+// TestRequestField 测试RequestField输出请求的紧凑摘要，不包含查询串和敏感请求头
+func TestRequestField(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "http://api.example.com/orders?token=secret", nil)
+	req.RemoteAddr = "10.0.0.1:5123"
+	req.Header.Set("User-Agent", "client/1.0")
+	req.Header.Set("X-Tenant", "acme")
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+
+	var buf bytes.Buffer
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithWriter(&buf))
+	log.Error("request failed", adapters.RequestField("request", req, "X-Tenant", "Authorization"))
+
+	line := strings.TrimSpace(buf.String())
+	if strings.Contains(line, "secret") {
+		t.Errorf("Expected no sensitive values in the summary, got %s", line)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &data); err != nil {
+		t.Fatalf("Failed to decode %q: %v", line, err)
+	}
+	summary, _ := data["request"].(map[string]interface{})
+	expected := map[string]interface{}{
+		"method":      "POST",
+		"host":        "api.example.com",
+		"path":        "/orders",
+		"remote_addr": "10.0.0.1:5123",
+	}
+	for k, v := range expected {
+		if summary[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, summary)
+		}
+	}
+	headers, _ := summary["headers"].(map[string]interface{})
+	if len(headers) != 2 || headers["User-Agent"] != "client/1.0" || headers["X-Tenant"] != "acme" {
+		t.Errorf("Expected only the selected non-sensitive headers, got %v", headers)
+	}
+
+	if field := adapters.RequestField("request", nil); field.Key != "request" || field.Value != nil {
+		t.Errorf("Expected a nil value for a nil request, got %+v", field)
+	}
+}
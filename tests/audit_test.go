@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+)
+
+// TestAuditLogger 测试审计日志固定为AuditLevel级别、带有audit=true标记，并且不受日志级别、采样和限流影响
+func TestAuditLogger(t *testing.T) {
+	rec := &recorder{}
+	base := logger.NewFuncLogger("app", logger.DebugLevel, rec.emit,
+		logger.WithProbabilisticLevel(logger.WarnLevel, 0),
+		logger.WithFieldRateLimit("key_id", 1),
+	)
+	audit := logger.NewAuditLogger(base)
+
+	for i := 0; i < 5; i++ {
+		audit.Warn("key rotation failed", logger.Field{Key: "key_id", Value: "k1"})
+		audit.Audit("key rotated", logger.Field{Key: "key_id", Value: "k1"})
+	}
+	audit.WithField("user", "alice").(*logger.AuditLogger).AuditCtx(context.Background(), "config reloaded")
+
+	if len(rec.calls) != 6 {
+		t.Fatalf("expected only the 6 audit entries to survive sampling and rate limiting, got %d: %+v", len(rec.calls), rec.calls)
+	}
+	for _, call := range rec.calls {
+		if call.level != logger.AuditLevel {
+			t.Errorf("expected audit level %s, got %s", logger.AuditLevel, call.level)
+		}
+		if v := fieldValue(call.fields, "audit"); v != true {
+			t.Errorf("expected audit=true marker, got %v in %+v", v, call.fields)
+		}
+	}
+	if last := rec.calls[5]; last.msg != "config reloaded" || fieldValue(last.fields, "user") != "alice" {
+		t.Errorf("expected derived audit logger to keep its fields, got %+v", last)
+	}
+}
+
+// TestAuditLoggerAboveLevel 测试日志实例级别高于AuditLevel时审计日志仍然输出
+func TestAuditLoggerAboveLevel(t *testing.T) {
+	rec := &recorder{}
+	audit := logger.NewAuditLogger(logger.NewFuncLogger("app", logger.FatalLevel, rec.emit))
+
+	audit.Error("dropped")
+	audit.Audit("secret rotated", logger.Field{Key: "secret", Value: "db"})
+
+	if len(rec.calls) != 1 || rec.calls[0].msg != "secret rotated" {
+		t.Fatalf("expected only the audit entry, got %+v", rec.calls)
+	}
+}
+
+// TestAuditLoggerWrappedLogrus 测试包装的logrus实例级别为错误级时审计日志仍然通过logrus自身的级别过滤
+func TestAuditLoggerWrappedLogrus(t *testing.T) {
+	base, hook := logrustest.NewNullLogger()
+	base.SetLevel(logrus.ErrorLevel)
+	base.SetFormatter(&logrus.JSONFormatter{})
+	audit := logger.NewAuditLogger(logger.NewLoggerFromLogrus("app", base))
+
+	audit.Warn("dropped")
+	audit.Audit("key rotated", logger.Field{Key: "key_id", Value: "k1"})
+
+	entries := hook.AllEntries()
+	if len(entries) != 1 {
+		t.Fatalf("expected only the audit entry to reach logrus, got %d", len(entries))
+	}
+	if entry := entries[0]; entry.Message != "key rotated" || entry.Data["audit"] != true || entry.Data["key_id"] != "k1" {
+		t.Errorf("unexpected audit entry: %s %v", entry.Message, entry.Data)
+	}
+}
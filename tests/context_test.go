@@ -0,0 +1,262 @@
+package tests
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestCtxMethods 测试*Ctx方法合并上下文字段且不修改日志实例
+func TestCtxMethods(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	ctx := logger.ContextWithFields(context.Background(), logger.Field{Key: "request_id", Value: "abc"})
+
+	for name, log := range loggers {
+		log.InfoCtx(ctx, "with ctx", logger.Field{Key: "user", Value: "bob"})
+		log.Info("without ctx")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 2 {
+			t.Fatalf("%s: expected 2 lines, got %d", name, len(lines))
+		}
+
+		first := decodeJSONLine(t, lines[0])
+		if first["request_id"] != "abc" || first["user"] != "bob" {
+			t.Errorf("%s: expected ctx and call fields, got %v", name, first)
+		}
+
+		second := decodeJSONLine(t, lines[1])
+		if _, ok := second["request_id"]; ok {
+			t.Errorf("%s: ctx fields leaked into logger: %v", name, second)
+		}
+	}
+}
+
+// TestContextExtractor 测试自定义上下文字段提取器
+func TestContextExtractor(t *testing.T) {
+	type tenantKey struct{}
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app",
+		logger.WithFormat("json"),
+		logger.WithOutputPath(path),
+		logger.WithContextExtractor(func(ctx context.Context) []logger.Field {
+			if tenant, ok := ctx.Value(tenantKey{}).(string); ok {
+				return []logger.Field{{Key: "tenant", Value: tenant}}
+			}
+			return nil
+		}),
+	)
+
+	log.WarnCtx(context.WithValue(context.Background(), tenantKey{}, "acme"), "quota")
+	log.Sync()
+
+	data := decodeJSONLine(t, readLines(t, path)[0])
+	if data["tenant"] != "acme" {
+		t.Errorf("Expected tenant field from extractor, got %v", data)
+	}
+}
+
+// TestContextKeys 测试WithContextKeys按键从上下文取值作为字段，不存在的键不输出
+func TestContextKeys(t *testing.T) {
+	type appKey string
+
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithContextKeys(appKey("tenant"), appKey("region"), appKey("missing"))}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	ctx := context.WithValue(context.Background(), appKey("tenant"), "acme")
+	ctx = context.WithValue(ctx, appKey("region"), "eu-west-1")
+
+	for name, log := range loggers {
+		log.WithContext(ctx).Info("with keys")
+		log.InfoCtx(ctx, "ctx method")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 2 {
+			t.Fatalf("%s: expected 2 lines, got %q", name, lines)
+		}
+		for _, line := range lines {
+			data := decodeJSONLine(t, line)
+			if data["tenant"] != "acme" || data["region"] != "eu-west-1" {
+				t.Errorf("%s: expected tenant and region fields, got %v", name, data)
+			}
+			if _, ok := data["missing"]; ok {
+				t.Errorf("%s: expected missing key to be omitted, got %v", name, data)
+			}
+		}
+	}
+}
+
+// TestContextWithLevel 测试上下文中的Debug级别让单个请求输出调试日志，且只能降低级别
+func TestContextWithLevel(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	debugCtx := logger.ContextWithLevel(context.Background(), logger.DebugLevel)
+	errorCtx := logger.ContextWithLevel(context.Background(), logger.ErrorLevel)
+
+	for name, log := range loggers {
+		log.Debug("dropped")
+		log.WithContext(debugCtx).Debug("request debug")
+		log.DebugCtx(debugCtx, "ctx debug")
+		log.WithContext(errorCtx).Info("info kept")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 3 {
+			t.Fatalf("%s: expected 3 lines, got %q", name, lines)
+		}
+		for i, msg := range []string{"request debug", "ctx debug", "info kept"} {
+			if data := decodeJSONLine(t, lines[i]); data["msg"] != msg {
+				t.Errorf("%s: expected %q at line %d, got %v", name, msg, i, data["msg"])
+			}
+		}
+		if log.IsDebugEnabled() {
+			t.Errorf("%s: expected the logger itself to stay at Info", name)
+		}
+	}
+}
+
+// TestContextWithOperation 测试上下文中的操作名称作为operation字段输出到派生日志实例，内层名称覆盖外层
+func TestContextWithOperation(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	ctx := logger.ContextWithOperation(context.Background(), "GET /orders/:id")
+	ctx = logger.ContextWithFields(ctx, logger.Field{Key: "request_id", Value: "r-1"})
+	inner := logger.ContextWithOperation(ctx, "db.query")
+
+	for name, log := range loggers {
+		derived := log.WithContext(ctx).WithField("step", 1)
+		derived.Info("loading order")
+		derived.WithField("step", 2).Warn("cache miss")
+		log.InfoCtx(inner, "query")
+		log.Info("no context")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 4 {
+			t.Fatalf("%s: expected 4 lines, got %q", name, lines)
+		}
+		for i, expected := range []interface{}{"GET /orders/:id", "GET /orders/:id", "db.query", nil} {
+			if data := decodeJSONLine(t, lines[i]); data["operation"] != expected {
+				t.Errorf("%s: expected operation=%v on line %d, got %v", name, expected, i, data)
+			}
+		}
+		if data := decodeJSONLine(t, lines[2]); data["request_id"] != "r-1" {
+			t.Errorf("%s: expected context fields to be kept, got %v", name, data)
+		}
+	}
+
+	if operation, ok := logger.OperationFromContext(inner); !ok || operation != "db.query" {
+		t.Errorf("Expected db.query, got %q", operation)
+	}
+	if _, ok := logger.OperationFromContext(context.Background()); ok {
+		t.Error("Expected no operation on an empty context")
+	}
+}
+
+// TestContextWithTenantAndUser 测试上下文中的租户ID和用户ID通过WithContext和*Ctx方法输出为tenant_id和user_id字段
+func TestContextWithTenantAndUser(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	ctx := logger.ContextWithTenant(context.Background(), "acme")
+	ctx = logger.ContextWithUser(ctx, "u-42")
+	other := logger.ContextWithTenant(ctx, "globex")
+
+	for name, log := range loggers {
+		log.WithContext(ctx).Info("with context")
+		log.InfoCtx(other, "ctx method")
+		log.Info("no context")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 3 {
+			t.Fatalf("%s: expected 3 lines, got %q", name, lines)
+		}
+		expected := []struct{ tenant, user interface{} }{{"acme", "u-42"}, {"globex", "u-42"}, {nil, nil}}
+		for i, e := range expected {
+			if data := decodeJSONLine(t, lines[i]); data["tenant_id"] != e.tenant || data["user_id"] != e.user {
+				t.Errorf("%s: expected tenant_id=%v user_id=%v on line %d, got %v", name, e.tenant, e.user, i, data)
+			}
+		}
+	}
+
+	if tenantID, ok := logger.TenantFromContext(other); !ok || tenantID != "globex" {
+		t.Errorf("Expected globex, got %q", tenantID)
+	}
+	if userID, ok := logger.UserFromContext(ctx); !ok || userID != "u-42" {
+		t.Errorf("Expected u-42, got %q", userID)
+	}
+	if _, ok := logger.UserFromContext(context.Background()); ok {
+		t.Error("Expected no user on an empty context")
+	}
+}
+
+// TestContextErrField 测试上下文已取消或超时时添加ctx_err字段，未取消时不添加
+func TestContextErrField(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithContextErrField(true)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	for name, log := range loggers {
+		log.WithContext(context.Background()).Info("active")
+		log.WithContext(canceled).Info("canceled")
+		log.InfoCtx(expired, "expired")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 3 {
+			t.Fatalf("%s: expected 3 lines, got %q", name, lines)
+		}
+		for i, expected := range []interface{}{nil, "context canceled", "context deadline exceeded"} {
+			if data := decodeJSONLine(t, lines[i]); data["ctx_err"] != expected {
+				t.Errorf("%s: expected ctx_err=%v on line %d, got %v", name, expected, i, data)
+			}
+		}
+	}
+}
@@ -0,0 +1,780 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// testEntry 构造固定时间的日志记录
+func testEntry() logger.Entry {
+	return logger.Entry{
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC),
+		Level:   logger.InfoLevel,
+		Name:    "app",
+		Message: "hello world",
+		Fields: []logger.Field{
+			{Key: "user", Value: "alice"},
+			{Key: "count", Value: 3},
+		},
+	}
+}
+
+// readLines 读取文件中的所有非空行
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s failed: %v", path, err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+// TestTextEncoder 测试文本编码器输出
+func TestTextEncoder(t *testing.T) {
+	line, err := (&logger.TextEncoder{}).Encode(testEntry())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	expected := "2024-01-02 03:04:05.006 [INFO] [app] hello world user=alice count=3\n"
+	if string(line) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(line))
+	}
+}
+
+// TestJSONEncoder 测试JSON编码器输出
+func TestJSONEncoder(t *testing.T) {
+	line, err := (&logger.JSONEncoder{}).Encode(testEntry())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	expected := `{"time":"2024-01-02T03:04:05.006Z","level":"INFO","logger":"app","msg":"hello world","user":"alice","count":3}` + "\n"
+	if string(line) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(line))
+	}
+}
+
+// TestLogfmtEncoder 测试logfmt编码器输出
+func TestLogfmtEncoder(t *testing.T) {
+	line, err := (&logger.LogfmtEncoder{}).Encode(testEntry())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	expected := `time=2024-01-02T03:04:05.006Z level=INFO logger=app msg="hello world" user=alice count=3` + "\n"
+	if string(line) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(line))
+	}
+}
+
+// TestConsoleAndStdConsistentFields 测试console和std适配器的字段渲染一致
+func TestConsoleAndStdConsistentFields(t *testing.T) {
+	dir := t.TempDir()
+	consolePath := filepath.Join(dir, "console.log")
+	stdPath := filepath.Join(dir, "std.log")
+
+	consoleLogger := logger.NewConsoleLogger("app", logger.WithOutputPath(consolePath))
+	stdLogger := logger.NewStdLogger("app", logger.WithOutputPath(stdPath))
+
+	fields := []logger.Field{{Key: "user", Value: "alice"}, {Key: "count", Value: 3}}
+	consoleLogger.WithField("module", "auth").Info("hello", fields...)
+	stdLogger.WithField("module", "auth").Info("hello", fields...)
+
+	consoleLines := readLines(t, consolePath)
+	stdLines := readLines(t, stdPath)
+	if len(consoleLines) != 1 || len(stdLines) != 1 {
+		t.Fatalf("Expected one line each, got %d and %d", len(consoleLines), len(stdLines))
+	}
+
+	// 去掉时间戳后比较
+	trim := func(line string) string {
+		return line[len(logger.DefaultTextTimeLayout):]
+	}
+	if trim(consoleLines[0]) != trim(stdLines[0]) {
+		t.Errorf("Expected consistent rendering, got %q and %q", consoleLines[0], stdLines[0])
+	}
+	if !strings.HasSuffix(consoleLines[0], "[INFO] [app] hello module=auth user=alice count=3") {
+		t.Errorf("Unexpected rendering: %q", consoleLines[0])
+	}
+}
+
+// fixedClock 返回固定时间的时钟
+type fixedClock struct {
+	t time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.t }
+
+// TestWithClock 测试注入的时钟决定输出中的时间
+func TestWithClock(t *testing.T) {
+	dir := t.TempDir()
+	clock := logger.WithClock(fixedClock{t: time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)})
+
+	textLoggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "console.log")), clock),
+		"std":     logger.NewStdLogger("app", logger.WithOutputPath(filepath.Join(dir, "std.log")), clock),
+	}
+	for name, log := range textLoggers {
+		log.Info("hello world")
+		log.Sync()
+
+		expected := "2024-01-02 03:04:05.006 [INFO] [app] hello world"
+		if line := readLines(t, filepath.Join(dir, name+".log"))[0]; line != expected {
+			t.Errorf("%s: expected %q, got %q", name, expected, line)
+		}
+	}
+
+	zapLog := logger.NewZapLogger("app", logger.WithOutputPath(filepath.Join(dir, "zap.log")), clock)
+	zapLog.Info("hello world")
+	zapLog.Sync()
+
+	if line := readLines(t, filepath.Join(dir, "zap.log"))[0]; !strings.Contains(line, `"time":"2024-01-02T03:04:05.006Z"`) {
+		t.Errorf("zap: unexpected time in %q", line)
+	}
+}
+
+// TestCustomKeys 测试自定义JSON输出中的级别和消息键名
+func TestCustomKeys(t *testing.T) {
+	dir := t.TempDir()
+	keys := []logger.Option{logger.WithFormat("json"), logger.WithLevelKey("severity"), logger.WithMessageKey("message")}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(keys, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(keys, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(keys, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(keys, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		log.Info("hello")
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		if data["message"] != "hello" || data["severity"] == nil {
+			t.Errorf("%s: expected custom keys, got %v", name, data)
+		}
+		if _, ok := data["msg"]; ok {
+			t.Errorf("%s: unexpected default msg key in %v", name, data)
+		}
+		if _, ok := data["level"]; ok {
+			t.Errorf("%s: unexpected default level key in %v", name, data)
+		}
+	}
+}
+
+// TestLevelEncoder 测试GCP风格的级别编码
+func TestLevelEncoder(t *testing.T) {
+	dir := t.TempDir()
+	severity := func(level logger.LogLevel) interface{} {
+		if level == logger.WarnLevel {
+			return "WARNING"
+		}
+		return level.String()
+	}
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithLevelKey("severity"), logger.WithLevelEncoder(severity)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		log.Warn("disk almost full")
+		log.Error("disk full")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 2 {
+			t.Fatalf("%s: expected 2 lines, got %d", name, len(lines))
+		}
+		for i, expected := range []string{"WARNING", "ERROR"} {
+			if data := decodeJSONLine(t, lines[i]); data["severity"] != expected {
+				t.Errorf("%s: expected severity %q, got %v", name, expected, data)
+			}
+		}
+	}
+}
+
+// TestNumericSeverity 测试JSON格式下同时输出级别名称和severity_code数值级别，并支持覆盖映射
+func TestNumericSeverity(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{
+		logger.WithFormat("json"),
+		logger.WithLevel(logger.DebugLevel),
+		logger.WithNumericSeverity(true),
+		logger.WithSeverityCodes(map[logger.LogLevel]int{logger.WarnLevel: 5}),
+	}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	expected := []struct {
+		level string
+		code  float64
+	}{{"DEBUG", 7}, {"INFO", 6}, {"WARN", 5}, {"ERROR", 3}}
+	for name, log := range loggers {
+		log.Debug("debug")
+		log.Info("info")
+		log.Warn("warn")
+		log.Error("error")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != len(expected) {
+			t.Fatalf("%s: expected %d lines, got %d", name, len(expected), len(lines))
+		}
+		for i, e := range expected {
+			data := decodeJSONLine(t, lines[i])
+			// logrus的警告级别名称为warning
+			if level, _ := data["level"].(string); !strings.HasPrefix(strings.ToUpper(level), e.level) || data["severity_code"] != e.code {
+				t.Errorf("%s: expected level %s with severity_code %v, got %v", name, e.level, e.code, data)
+			}
+		}
+	}
+
+	// gcp和cloudevents格式同样输出JSON，也添加severity_code，cloudevents格式下位于data中
+	for _, format := range []string{"gcp", "cloudevents"} {
+		path := filepath.Join(dir, format+".log")
+		log := logger.NewConsoleLogger("app", logger.WithFormat(format), logger.WithOutputPath(path), logger.WithNumericSeverity(true))
+		log.Error("error")
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, path)[0])
+		if format == "cloudevents" {
+			data, _ = data["data"].(map[string]interface{})
+		}
+		if data["severity_code"] != float64(3) {
+			t.Errorf("%s: expected severity_code 3, got %v", format, data)
+		}
+	}
+
+	// logrus的gcp和cloudevents格式按文本输出，不添加severity_code
+	for _, format := range []string{"gcp", "cloudevents"} {
+		path := filepath.Join(dir, "logrus-"+format+".log")
+		log := logger.NewLogrusLogger("app", logger.WithFormat(format), logger.WithOutputPath(path), logger.WithNumericSeverity(true))
+		log.Error("error")
+		log.Sync()
+		if line := readLines(t, path)[0]; strings.Contains(line, "severity_code") {
+			t.Errorf("logrus %s: expected no severity_code in text output, got %q", format, line)
+		}
+	}
+
+	// 文本格式不添加severity_code
+	path := filepath.Join(dir, "text.log")
+	log := logger.NewConsoleLogger("app", logger.WithOutputPath(path), logger.WithNumericSeverity(true))
+	log.Info("text")
+	log.Sync()
+	if line := readLines(t, path)[0]; strings.Contains(line, "severity_code") {
+		t.Errorf("Expected no severity_code in text output, got %q", line)
+	}
+}
+
+// TestCompositesAsJSON 测试文本格式下切片和map字段输出为紧凑JSON
+func TestCompositesAsJSON(t *testing.T) {
+	dir := t.TempDir()
+	textPath := filepath.Join(dir, "text.log")
+	logfmtPath := filepath.Join(dir, "logfmt.log")
+	fields := []logger.Field{
+		{Key: "tags", Value: []string{"a", "b"}},
+		{Key: "limits", Value: map[string]int{"cpu": 2}},
+		{Key: "count", Value: 3},
+	}
+
+	textLog := logger.NewConsoleLogger("app", logger.WithOutputPath(textPath), logger.WithComposeCompositesAsJSON(true))
+	textLog.Info("deployed", fields...)
+	textLog.Sync()
+
+	line := readLines(t, textPath)[0]
+	if !strings.HasSuffix(line, `deployed tags=["a","b"] limits={"cpu":2} count=3`) {
+		t.Errorf("Unexpected text rendering: %q", line)
+	}
+
+	logfmtLog := logger.NewConsoleLogger("app", logger.WithFormat("logfmt"), logger.WithOutputPath(logfmtPath), logger.WithComposeCompositesAsJSON(true))
+	logfmtLog.Info("deployed", fields...)
+	logfmtLog.Sync()
+
+	line = readLines(t, logfmtPath)[0]
+	if !strings.Contains(line, `tags="[\"a\",\"b\"]"`) {
+		t.Errorf("Unexpected logfmt rendering: %q", line)
+	}
+
+	defaultLog := logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "default.log")))
+	defaultLog.Info("deployed", fields...)
+	defaultLog.Sync()
+
+	if line := readLines(t, filepath.Join(dir, "default.log"))[0]; !strings.Contains(line, "tags=[a b]") {
+		t.Errorf("Expected default %%v rendering, got %q", line)
+	}
+}
+
+// TestSanitizeNewlines 测试文本格式下消息、字段键和字段值中的换行符被转义，不会伪造日志行
+func TestSanitizeNewlines(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"logfmt":  logger.NewConsoleLogger("app", logger.WithFormat("logfmt"), logger.WithOutputPath(filepath.Join(dir, "logfmt.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	for name, log := range loggers {
+		log.Info("login failed\n2024-01-01 00:00:00.000 [INFO] [app] login ok",
+			logger.Field{Key: "user", Value: "eve\r\nadmin"},
+			logger.Field{Key: "x\n2024-01-01 00:00:00.000 [INFO] [app] forged", Value: "1"})
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 1 {
+			t.Errorf("%s: expected 1 line, got %d: %q", name, len(lines), lines)
+		}
+	}
+
+	if line := readLines(t, filepath.Join(dir, "console.log"))[0]; !strings.Contains(line, `login failed\n2024`) || !strings.Contains(line, `user=eve\r\nadmin`) ||
+		!strings.Contains(line, `x\n2024-01-01 00:00:00.000 [INFO] [app] forged=1`) {
+		t.Errorf("Expected escaped newlines, got %q", line)
+	}
+
+	rawPath := filepath.Join(dir, "raw.log")
+	raw := logger.NewConsoleLogger("app", logger.WithOutputPath(rawPath), logger.WithSanitizeNewlines(false))
+	raw.Info("line one\nline two")
+	raw.Sync()
+	if lines := readLines(t, rawPath); len(lines) != 2 {
+		t.Errorf("Expected raw newlines when disabled, got %q", lines)
+	}
+}
+
+// TestFloatPrecision 测试文本和logfmt格式按配置的小数位数输出浮点数，JSON格式保留完整精度
+func TestFloatPrecision(t *testing.T) {
+	dir := t.TempDir()
+	fields := []logger.Field{
+		{Key: "ratio", Value: 1.0000000001},
+		{Key: "tiny", Value: 0.00000123},
+	}
+
+	for _, format := range []string{"text", "logfmt"} {
+		path := filepath.Join(dir, format+".log")
+		log := logger.NewConsoleLogger("app", logger.WithFormat(format), logger.WithOutputPath(path), logger.WithFloatPrecision(3))
+		log.Info("measured", fields...)
+		log.Sync()
+
+		line := readLines(t, path)[0]
+		if !strings.Contains(line, "ratio=1.000") || !strings.Contains(line, "tiny=0.000") {
+			t.Errorf("Expected 3 digits in %s output, got %q", format, line)
+		}
+	}
+
+	logrusPath := filepath.Join(dir, "logrus.log")
+	logrusLog := logger.NewLogrusLogger("app", logger.WithFormat("text"), logger.WithOutputPath(logrusPath), logger.WithFloatPrecision(2))
+	logrusLog.Info("measured", fields...)
+	logrusLog.Sync()
+
+	if line := readLines(t, logrusPath)[0]; !strings.Contains(line, "ratio=1.00") {
+		t.Errorf("Expected 2 digits in logrus text output, got %q", line)
+	}
+
+	jsonPath := filepath.Join(dir, "json.log")
+	jsonLog := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(jsonPath), logger.WithFloatPrecision(3))
+	jsonLog.Info("measured", fields...)
+	jsonLog.Sync()
+
+	if line := readLines(t, jsonPath)[0]; !strings.Contains(line, `"ratio":1.0000000001`) || !strings.Contains(line, `"tiny":0.00000123`) {
+		t.Errorf("Expected full precision in JSON output, got %q", line)
+	}
+}
+
+// TestCloudEventsEncoder 测试cloudevents格式输出符合CloudEvents v1.0结构化模式的信封
+func TestCloudEventsEncoder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	log := logger.NewConsoleLogger("app",
+		logger.WithFormat("cloudevents"),
+		logger.WithOutputPath(path),
+		logger.WithCloudEventsSource("/services/orders"),
+		logger.WithCloudEventsType("com.example.log"))
+	log.Info("order created", logger.Field{Key: "order_id", Value: 42})
+	log.Info("order paid")
+	log.Sync()
+
+	lines := readLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(lines))
+	}
+
+	var event struct {
+		SpecVersion     string                 `json:"specversion"`
+		ID              string                 `json:"id"`
+		Source          string                 `json:"source"`
+		Type            string                 `json:"type"`
+		Time            time.Time              `json:"time"`
+		DataContentType string                 `json:"datacontenttype"`
+		Data            map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatalf("Event is not valid JSON: %v", err)
+	}
+
+	if event.SpecVersion != "1.0" || event.Source != "/services/orders" || event.Type != "com.example.log" {
+		t.Errorf("Unexpected envelope: %+v", event)
+	}
+	if event.ID == "" || event.Time.IsZero() || event.DataContentType != "application/json" {
+		t.Errorf("Expected id, time and datacontenttype, got %+v", event)
+	}
+	if event.Data["msg"] != "order created" || event.Data["level"] != "INFO" || event.Data["order_id"] != float64(42) {
+		t.Errorf("Unexpected data: %v", event.Data)
+	}
+
+	var second struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil || second.ID == event.ID {
+		t.Errorf("Expected a distinct id for each event, got %q and %q", event.ID, second.ID)
+	}
+
+	line, err := (&logger.CloudEventsEncoder{}).Encode(testEntry())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !strings.Contains(string(line), `"source":"app","type":"com.landclogface.log"`) {
+		t.Errorf("Expected default source and type, got %q", line)
+	}
+}
+
+// TestGCPEncoder 测试gcp格式输出severity和message，级别映射为Cloud Logging的severity，追踪字段映射为Cloud Logging的追踪键
+func TestGCPEncoder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gcp.log")
+	log := logger.NewStdLogger("app",
+		logger.WithFormat("gcp"),
+		logger.WithOutputPath(path),
+		logger.WithLevel(logger.DebugLevel),
+		logger.WithGCPProjectID("my-project"),
+		logger.WithExitFunc(func(int) {}))
+	ctx := logger.ContextWithFields(context.Background(),
+		logger.Field{Key: "trace_id", Value: "4bf92f3577b34da6a3ce929d0e0e4736"},
+		logger.Field{Key: "span_id", Value: "00f067aa0ba902b7"},
+		logger.Field{Key: "trace_sampled", Value: true})
+	log.InfoCtx(ctx, "order created", logger.Field{Key: "order_id", Value: 42})
+	log.Debug("debug")
+	log.Warn("warn")
+	log.Error("error")
+	log.Fatal("fatal")
+	log.Sync()
+
+	lines := readLines(t, path)
+	if len(lines) != 5 {
+		t.Fatalf("Expected 5 lines, got %q", lines)
+	}
+
+	first := decodeJSONLine(t, lines[0])
+	if first["severity"] != "INFO" || first["message"] != "order created" || first["logger"] != "app" || first["order_id"] != float64(42) {
+		t.Errorf("Unexpected record: %v", first)
+	}
+	if ts, ok := first["time"].(string); !ok {
+		t.Errorf("Expected time, got %v", first["time"])
+	} else if _, err := time.Parse(time.RFC3339Nano, ts); err != nil {
+		t.Errorf("Expected RFC3339 time, got %q", ts)
+	}
+	if first["logging.googleapis.com/trace"] != "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736" ||
+		first["logging.googleapis.com/spanId"] != "00f067aa0ba902b7" || first["logging.googleapis.com/trace_sampled"] != true {
+		t.Errorf("Unexpected trace keys: %v", first)
+	}
+	for _, key := range []string{"trace_id", "span_id", "trace_sampled", "level", "msg"} {
+		if _, ok := first[key]; ok {
+			t.Errorf("Unexpected key %s in %v", key, first)
+		}
+	}
+
+	for i, severity := range []string{"DEBUG", "WARNING", "ERROR", "CRITICAL"} {
+		if got := decodeJSONLine(t, lines[i+1])["severity"]; got != severity {
+			t.Errorf("Expected severity %s, got %v", severity, got)
+		}
+	}
+
+	entry := testEntry()
+	entry.Fields = []logger.Field{{Key: "trace_id", Value: "abc"}}
+	line, err := (&logger.GCPEncoder{}).Encode(entry)
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !strings.Contains(string(line), `"logging.googleapis.com/trace":"abc"`) {
+		t.Errorf("Expected the bare trace id without a project, got %q", line)
+	}
+}
+
+// TestBytesEncoding 测试[]byte字段在文本和logfmt格式下输出为base64或十六进制，JSON格式输出为base64字符串
+func TestBytesEncoding(t *testing.T) {
+	dir := t.TempDir()
+	field := logger.Field{Key: "payload", Value: []byte("hi")}
+
+	tests := []struct {
+		name     string
+		opts     []logger.Option
+		expected string
+	}{
+		{"text", []logger.Option{logger.WithFormat("text")}, "payload=aGk="},
+		{"logfmt", []logger.Option{logger.WithFormat("logfmt")}, `payload="aGk="`},
+		{"hex", []logger.Option{logger.WithFormat("text"), logger.WithBytesEncoding(logger.BytesEncodingHex)}, "payload=6869"},
+		{"json", []logger.Option{logger.WithFormat("json")}, `"payload":"aGk="`},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name+".log")
+		log := logger.NewConsoleLogger("app", append(tt.opts, logger.WithOutputPath(path))...)
+		log.Info("received", field)
+		log.Sync()
+
+		line := readLines(t, path)[0]
+		if !strings.Contains(line, tt.expected) || strings.Contains(line, "[104 105]") {
+			t.Errorf("%s: expected %s, got %q", tt.name, tt.expected, line)
+		}
+	}
+
+	logrusPath := filepath.Join(dir, "logrus.log")
+	logrusLog := logger.NewLogrusLogger("app", logger.WithFormat("text"), logger.WithOutputPath(logrusPath))
+	logrusLog.Info("received", field)
+	logrusLog.Sync()
+
+	if line := readLines(t, logrusPath)[0]; !strings.Contains(line, "payload=\"aGk=\"") && !strings.Contains(line, "payload=aGk=") {
+		t.Errorf("Expected base64 payload in logrus text output, got %q", line)
+	}
+}
+
+// TestFlattenFields 测试JSON格式下自定义字段平铺在顶层或嵌套在指定键下
+func TestFlattenFields(t *testing.T) {
+	dir := t.TempDir()
+	field := logger.Field{Key: "user", Value: "alice"}
+	create := map[string]func(opts ...logger.Option) logger.Logger{
+		"console": func(opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger("app", opts...) },
+		"zap":     func(opts ...logger.Option) logger.Logger { return logger.NewZapLogger("app", opts...) },
+		"logrus":  func(opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger("app", opts...) },
+	}
+
+	for name, newLogger := range create {
+		flatPath := filepath.Join(dir, name+"-flat.log")
+		flat := newLogger(logger.WithFormat("json"), logger.WithOutputPath(flatPath))
+		flat.Info("login", field)
+		flat.Sync()
+
+		data := decodeJSONLine(t, readLines(t, flatPath)[0])
+		if data["user"] != "alice" || data["fields"] != nil {
+			t.Errorf("%s: expected flattened fields, got %v", name, data)
+		}
+
+		nestedPath := filepath.Join(dir, name+"-nested.log")
+		nested := newLogger(logger.WithFormat("json"), logger.WithOutputPath(nestedPath), logger.WithFlattenFields(false), logger.WithFieldsKey("attrs"))
+		nested.Info("login", field)
+		nested.Sync()
+
+		data = decodeJSONLine(t, readLines(t, nestedPath)[0])
+		attrs, ok := data["attrs"].(map[string]interface{})
+		if !ok || attrs["user"] != "alice" || data["user"] != nil {
+			t.Errorf("%s: expected fields nested under attrs, got %v", name, data)
+		}
+		if data["msg"] != "login" {
+			t.Errorf("%s: expected msg to stay at the top level, got %v", name, data)
+		}
+	}
+
+	line, err := (&logger.JSONEncoder{FieldsKey: "fields"}).Encode(testEntry())
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	expected := `{"time":"2024-01-02T03:04:05.006Z","level":"INFO","logger":"app","msg":"hello world","fields":{"user":"alice","count":3}}` + "\n"
+	if string(line) != expected {
+		t.Errorf("Expected %q, got %q", expected, string(line))
+	}
+}
+
+// TestTimeFieldFormat 测试time.Time字段值在文本和logfmt中按配置的格式输出，在JSON中为RFC3339格式
+func TestTimeFieldFormat(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2009, 11, 10, 23, 0, 0, 0, time.UTC)
+	layout := "2006/01/02 15:04"
+
+	textLoggers := map[string]logger.Logger{
+		"text":   logger.NewConsoleLogger("app", logger.WithFormat("text"), logger.WithTimeFormat(layout), logger.WithOutputPath(filepath.Join(dir, "text.log"))),
+		"logfmt": logger.NewConsoleLogger("app", logger.WithFormat("logfmt"), logger.WithTimeFormat(layout), logger.WithOutputPath(filepath.Join(dir, "logfmt.log"))),
+		"std":    logger.NewStdLogger("app", logger.WithFormat("text"), logger.WithTimeFormat(layout), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"logrus": logger.NewLogrusLogger("app", logger.WithFormat("text"), logger.WithTimeFormat(layout), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+	for name, log := range textLoggers {
+		log.WithTime(at).Info("scheduled", logger.Field{Key: "deadline", Value: at.Add(time.Hour)})
+		log.Sync()
+
+		line := readLines(t, filepath.Join(dir, name+".log"))[0]
+		if !strings.Contains(line, `time="2009/11/10 23:00"`) && !strings.Contains(line, "time=2009/11/10 23:00") {
+			t.Errorf("%s: expected the time field in the configured layout, got %q", name, line)
+		}
+		if !strings.Contains(line, "2009/11/11 00:00") || strings.Contains(line, "+0000 UTC") {
+			t.Errorf("%s: expected the deadline field in the configured layout, got %q", name, line)
+		}
+	}
+
+	jsonLoggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithTimeFormat(layout), logger.WithOutputPath(filepath.Join(dir, "console.json"))),
+		"zap":     logger.NewZapLogger("app", logger.WithOutputPath(filepath.Join(dir, "zap.json"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.json"))),
+	}
+	for name, log := range jsonLoggers {
+		log.Info("scheduled", logger.Field{Key: "deadline", Value: at})
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".json"))[0])
+		if data["deadline"] != "2009-11-10T23:00:00Z" {
+			t.Errorf("%s: expected an RFC3339 deadline, got %v", name, data["deadline"])
+		}
+	}
+}
+
+// TestLevelColors 测试按级别覆盖的ANSI颜色包裹对应级别，未覆盖的级别使用默认颜色，JSON格式和未开启颜色时不输出颜色
+func TestLevelColors(t *testing.T) {
+	colors := map[logger.LogLevel]string{
+		logger.WarnLevel:  "35",
+		logger.ErrorLevel: "\x1b[1;31m",
+	}
+
+	var buf bytes.Buffer
+	log := logger.NewConsoleLogger("app", logger.WithWriter(&buf), logger.WithLevel(logger.DebugLevel),
+		logger.WithColor(true), logger.WithLevelColors(colors))
+	log.Debug("debug")
+	log.Info("info")
+	log.Warn("warn")
+	log.Error("error")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	expected := []string{
+		"[\x1b[36mDEBUG\x1b[0m]",
+		"[\x1b[32mINFO\x1b[0m]",
+		"[\x1b[35mWARN\x1b[0m]",
+		"[\x1b[1;31mERROR\x1b[0m]",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), lines)
+	}
+	for i, want := range expected {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("Expected %q in %q", want, lines[i])
+		}
+	}
+
+	for name, opts := range map[string][]logger.Option{
+		"json":     {logger.WithFormat("json"), logger.WithColor(true)},
+		"no color": {},
+	} {
+		var plain bytes.Buffer
+		log := logger.NewConsoleLogger("app", append(opts, logger.WithWriter(&plain), logger.WithLevelColors(colors))...)
+		log.Warn("warn")
+		if strings.Contains(plain.String(), "\x1b[") {
+			t.Errorf("%s: expected no escape codes, got %q", name, plain.String())
+		}
+	}
+}
+
+// TestColorsOffForNonTerminal 测试输出到文件或缓冲区等非终端目标时，未显式WithColor(true)不输出ANSI颜色，
+// 开发环境的auto日志同样只在终端上使用颜色
+func TestColorsOffForNonTerminal(t *testing.T) {
+	dir := t.TempDir()
+	colors := map[logger.LogLevel]string{logger.WarnLevel: "35"}
+
+	var buf bytes.Buffer
+	loggers := map[string]struct {
+		log  logger.Logger
+		read func() string
+	}{
+		"console file": {
+			log:  logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "console.log")), logger.WithLevelColors(colors)),
+			read: func() string { return strings.Join(readLines(t, filepath.Join(dir, "console.log")), "\n") },
+		},
+		"std file": {
+			log:  logger.NewStdLogger("app", logger.WithOutputPath(filepath.Join(dir, "std.log")), logger.WithLevelColors(colors)),
+			read: func() string { return strings.Join(readLines(t, filepath.Join(dir, "std.log")), "\n") },
+		},
+		"auto dev file": {
+			log:  logger.NewAutoLogger("app", logger.WithEnvironment("dev"), logger.WithOutputPath(filepath.Join(dir, "auto.log"))),
+			read: func() string { return strings.Join(readLines(t, filepath.Join(dir, "auto.log")), "\n") },
+		},
+		"auto dev buffer": {
+			log:  logger.NewAutoLogger("app", logger.WithEnvironment("dev"), logger.WithWriter(&buf)),
+			read: func() string { return buf.String() },
+		},
+	}
+
+	for name, l := range loggers {
+		l.log.Warn("warn")
+		l.log.Sync()
+		out := l.read()
+		if !strings.Contains(out, "[WARN]") || strings.Contains(out, "\x1b[") {
+			t.Errorf("%s: expected plain level without escape codes, got %q", name, out)
+		}
+	}
+
+	// 显式开启颜色时仍然使用颜色
+	var colored bytes.Buffer
+	log := logger.NewAutoLogger("app", logger.WithEnvironment("dev"), logger.WithWriter(&colored), logger.WithColor(true))
+	log.Warn("warn")
+	if !strings.Contains(colored.String(), "\x1b[33mWARN\x1b[0m") {
+		t.Errorf("Expected explicit WithColor(true) to keep colors, got %q", colored.String())
+	}
+}
+
+// TestTrailingNewline 测试JSON编码器和各适配器的每条记录恰好以一个换行符结尾，值中的换行符被转义，
+// 关闭WithTrailingNewline后记录不带结尾换行符
+func TestTrailingNewline(t *testing.T) {
+	entry := testEntry()
+	entry.Message = "line1\nline2\r\n"
+	entry.Fields = []logger.Field{
+		{Key: "multi", Value: "a\nb"},
+		{Key: "nested", Value: map[string]interface{}{"text": "c\nd"}},
+		{Key: "raw", Value: json.RawMessage("{\n  \"e\": 1\n}")},
+	}
+
+	for _, format := range []string{"json", "cloudevents", "gcp"} {
+		line, err := logger.NewEncoder(format).Encode(entry)
+		if err != nil {
+			t.Fatalf("%s: Encode failed: %v", format, err)
+		}
+		if bytes.Count(line, []byte("\n")) != 1 || !bytes.HasSuffix(line, []byte("}\n")) {
+			t.Errorf("%s: Expected exactly one trailing newline, got %q", format, line)
+		}
+	}
+
+	constructors := map[string]func(name string, opts ...logger.Option) logger.Logger{
+		"console": func(name string, opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger(name, opts...) },
+		"std":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewStdLogger(name, opts...) },
+		"zap":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewZapLogger(name, opts...) },
+		"logrus":  func(name string, opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger(name, opts...) },
+	}
+
+	for name, create := range constructors {
+		var buf bytes.Buffer
+		log := create("app", logger.WithFormat("json"), logger.WithWriter(&buf))
+		log.Info("first\nsecond", logger.Field{Key: "multi", Value: "a\r\nb"})
+		log.Warn("third", logger.Field{Key: "nested", Value: map[string]interface{}{"text": "c\nd"}})
+
+		out := buf.String()
+		if strings.Count(out, "\n") != 2 || !strings.HasSuffix(out, "}\n") {
+			t.Errorf("%s: Expected one newline per record, got %q", name, out)
+		}
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+			decodeJSONLine(t, line)
+		}
+
+		buf.Reset()
+		log = create("app", logger.WithFormat("json"), logger.WithWriter(&buf), logger.WithTrailingNewline(false))
+		log.Info("first\nsecond")
+		if out := buf.String(); strings.Contains(out, "\n") || !strings.HasSuffix(out, "}") {
+			t.Errorf("%s: Expected no trailing newline, got %q", name, out)
+		}
+		decodeJSONLine(t, buf.String())
+	}
+}
@@ -0,0 +1,35 @@
+//go:build windows
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/adapters"
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// TestEventLogWriter 测试写入Windows事件日志，注册事件来源需要管理员权限
+func TestEventLogWriter(t *testing.T) {
+	const source = "LandcLogFaceTest"
+	if err := eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info); err != nil {
+		t.Skipf("cannot register event source: %v", err)
+	}
+	defer eventlog.Remove(source)
+
+	writer, err := adapters.NewEventLogWriter(source)
+	if err != nil {
+		t.Fatalf("NewEventLogWriter failed: %v", err)
+	}
+	defer writer.Close()
+
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithWriter(writer))
+	log.Info("service started")
+	log.Warn("disk almost full")
+	log.Error("disk full")
+
+	if stats := log.Stats(); stats.Dropped != 0 {
+		t.Errorf("Expected no dropped records, got %+v", stats)
+	}
+}
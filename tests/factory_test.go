@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestFallbackProviders 测试指定的提供者和默认提供者不可用时按备用提供者链的顺序创建日志实例
+func TestFallbackProviders(t *testing.T) {
+	factory := logger.NewLogFactory()
+	factory.RegisterProvider("console", logger.NewConsoleLoggerProvider())
+	factory.RegisterProvider("std", logger.NewStdLoggerProvider())
+	factory.RegisterProvider("zap", logger.NewZapLoggerProvider())
+	factory.SetDefaultProvider("missing")
+	factory.SetFallbackProviders("also-missing", "std", "zap")
+
+	factory.UnregisterProvider("console")
+	if provider := factory.CreateLogger("app").Describe().Provider; provider != "std" {
+		t.Errorf("Expected first available fallback std, got %s", provider)
+	}
+
+	factory.UnregisterProvider("std")
+	if provider := factory.CreateLoggerWithProvider("app", "console").Describe().Provider; provider != "zap" {
+		t.Errorf("Expected next fallback zap, got %s", provider)
+	}
+	config := map[string]interface{}{"provider": "logrus", "format": "json"}
+	if provider := factory.CreateLoggerWithConfig("app", config).Describe().Provider; provider != "zap" {
+		t.Errorf("Expected fallback zap for config, got %s", provider)
+	}
+
+	factory.UnregisterProvider("zap")
+	if provider := factory.CreateLogger("app").Describe().Provider; provider != "console" {
+		t.Errorf("Expected console constructor when the chain is exhausted, got %s", provider)
+	}
+}
+
+// TestMissingProviderPolicy 测试请求的提供者未注册时按策略降级：warn输出一次内部警告，error输出错误级日志，silent静默
+func TestMissingProviderPolicy(t *testing.T) {
+	var warnings bytes.Buffer
+	logger.SetErrorOutput(&warnings)
+	defer logger.SetErrorOutput(os.Stderr)
+
+	dir := t.TempDir()
+	newFactory := func(policy logger.MissingProviderPolicy) *logger.LogFactory {
+		factory := logger.NewLogFactory()
+		factory.RegisterProvider("console", logger.NewConsoleLoggerProvider())
+		factory.RegisterProvider("zap", logger.NewZapLoggerProvider())
+		factory.UnregisterProvider("zap")
+		if policy != "" {
+			factory.SetMissingProviderPolicy(policy)
+		}
+		return factory
+	}
+	create := func(factory *logger.LogFactory, file string) []string {
+		path := filepath.Join(dir, file)
+		log := factory.CreateLoggerWithConfig("app", map[string]interface{}{"provider": "zap", "format": "json", "outputPath": path})
+		if provider := log.Describe().Provider; provider != "console" {
+			t.Errorf("%s: expected degraded console logger, got %s", file, provider)
+		}
+		log.Info("ready")
+		log.Sync()
+		return readLines(t, path)
+	}
+
+	// 默认策略：只输出一次内部警告，日志带有provider_unavailable字段
+	factory := newFactory("")
+	if policy := factory.GetMissingProviderPolicy(); policy != logger.MissingProviderWarn {
+		t.Errorf("Expected default policy warn, got %s", policy)
+	}
+	create(factory, "warn1.log")
+	lines := create(factory, "warn2.log")
+	if n := strings.Count(warnings.String(), `"zap"`); n != 1 {
+		t.Errorf("Expected one warning naming zap, got %q", warnings.String())
+	}
+	if data := decodeJSONLine(t, lines[0]); data["provider_unavailable"] != "zap" {
+		t.Errorf("Expected provider_unavailable=zap, got %v", data)
+	}
+
+	// error策略：通过降级的日志实例输出错误级日志
+	warnings.Reset()
+	lines = create(newFactory(logger.MissingProviderError), "error.log")
+	if len(lines) != 2 {
+		t.Fatalf("Expected the error entry and the info entry, got %q", lines)
+	}
+	data := decodeJSONLine(t, lines[0])
+	if data["level"] != "ERROR" || data["provider_unavailable"] != "zap" || !strings.Contains(data["msg"].(string), `"zap"`) {
+		t.Errorf("Expected an error entry naming zap, got %v", data)
+	}
+	if warnings.Len() != 0 {
+		t.Errorf("Expected no internal warning with the error policy, got %q", warnings.String())
+	}
+
+	// silent策略：与之前一样静默降级
+	lines = create(newFactory(logger.MissingProviderSilent), "silent.log")
+	if _, ok := decodeJSONLine(t, lines[0])["provider_unavailable"]; ok || len(lines) != 1 || warnings.Len() != 0 {
+		t.Errorf("Expected silent degradation, got %q and warnings %q", lines, warnings.String())
+	}
+
+	if err := factory.CheckProvider("zap"); !errors.Is(err, logger.ErrProviderUnavailable) || !strings.Contains(err.Error(), "zap") {
+		t.Errorf("Expected ErrProviderUnavailable naming zap, got %v", err)
+	}
+	if err := factory.CheckProvider("console"); err != nil {
+		t.Errorf("Expected console to be available, got %v", err)
+	}
+}
+
+// countingProvider 记录创建次数的日志提供者
+type countingProvider struct {
+	created atomic.Int32
+}
+
+func (p *countingProvider) Create(name string) logger.Logger {
+	p.created.Add(1)
+	return logger.NewConsoleLogger(name)
+}
+
+func (p *countingProvider) CreateWithConfig(name string, config map[string]interface{}) logger.Logger {
+	return p.Create(name)
+}
+
+// TestLoggerCache 测试相同名称的重复请求返回同一个日志实例，并发首次请求只创建一次
+func TestLoggerCache(t *testing.T) {
+	if logger.GetLoggerWithName("db") != logger.GetLoggerWithName("db") {
+		t.Error("Expected GetLoggerWithName to return the same instance for the same name")
+	}
+	if logger.GetLoggerWithName("db") == logger.GetLoggerWithName("cache") {
+		t.Error("Expected different instances for different names")
+	}
+
+	factory := logger.NewLogFactory()
+	provider := &countingProvider{}
+	factory.RegisterProvider("counting", provider)
+	factory.SetDefaultProvider("counting")
+
+	var wg sync.WaitGroup
+	results := make([]logger.Logger, 32)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = factory.GetOrCreateLogger("db", "")
+		}(i)
+	}
+	wg.Wait()
+
+	for _, log := range results {
+		if log != results[0] {
+			t.Fatal("Expected concurrent requests to share one instance")
+		}
+	}
+	if created := provider.created.Load(); created != 1 {
+		t.Errorf("Expected the logger to be created once, got %d", created)
+	}
+
+	if factory.GetOrCreateLogger("db", "counting") != results[0] {
+		t.Error("Expected the default provider name to share the cache entry")
+	}
+
+	factory.ClearLoggerCache()
+	if factory.GetOrCreateLogger("db", "") == results[0] || provider.created.Load() != 2 {
+		t.Error("Expected a new instance after clearing the cache")
+	}
+}
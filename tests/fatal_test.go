@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestFatalHooks 测试致命级日志在退出前刷新输出并按顺序执行钩子
+func TestFatalHooks(t *testing.T) {
+	logger.SetErrorOutput(io.Discard)
+	defer logger.SetErrorOutput(os.Stderr)
+
+	dir := t.TempDir()
+	constructors := map[string]func(name string, opts ...logger.Option) logger.Logger{
+		"console": func(name string, opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger(name, opts...) },
+		"std":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewStdLogger(name, opts...) },
+		"zap":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewZapLogger(name, opts...) },
+		"logrus":  func(name string, opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger(name, opts...) },
+	}
+
+	for name, newLogger := range constructors {
+		path := filepath.Join(dir, name+".log")
+		var calls []string
+		exitCode := -1
+
+		log := newLogger("app",
+			logger.WithOutputPath(path),
+			logger.WithFatalHooks(
+				func() { calls = append(calls, "first") },
+				func() { panic("boom") },
+			),
+			logger.WithFatalHooks(func() { calls = append(calls, "second") }),
+			logger.WithExitFunc(func(code int) {
+				calls = append(calls, "exit")
+				exitCode = code
+				if lines := readLines(t, path); len(lines) != 1 || !strings.Contains(lines[0], "shutting down") {
+					t.Errorf("%s: expected log line flushed before exit, got %v", name, lines)
+				}
+			}),
+		)
+		log.Fatal("shutting down")
+
+		if strings.Join(calls, ",") != "first,second,exit" {
+			t.Errorf("%s: unexpected call order %v", name, calls)
+		}
+		if exitCode != 1 {
+			t.Errorf("%s: expected exit code 1, got %d", name, exitCode)
+		}
+	}
+}
+
+// TestFatalHooksWrapped 测试包装已有的zap和logrus实例时，致命级日志写入并刷新后同样执行门面的致命钩子和退出函数，
+// 未设置WithExitFunc的logrus实例通过自身的ExitFunc退出
+func TestFatalHooksWrapped(t *testing.T) {
+	dir := t.TempDir()
+	open := func(name string) (*os.File, string) {
+		path := filepath.Join(dir, name+".log")
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatalf("open %s failed: %v", path, err)
+		}
+		t.Cleanup(func() { file.Close() })
+		return file, path
+	}
+
+	zapFile, zapPath := open("zap")
+	zapBase := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapFile, zapcore.DebugLevel))
+
+	logrusFile, logrusPath := open("logrus")
+	logrusBase := logrus.New()
+	logrusBase.SetOutput(logrusFile)
+	logrusBase.SetLevel(logrus.ErrorLevel)
+
+	constructors := map[string]struct {
+		path   string
+		create func(opts ...logger.Option) logger.Logger
+	}{
+		"zap": {zapPath, func(opts ...logger.Option) logger.Logger {
+			return logger.NewLoggerFromZap("app", zapBase, logger.InfoLevel, opts...)
+		}},
+		"logrus": {logrusPath, func(opts ...logger.Option) logger.Logger {
+			return logger.NewLoggerFromLogrus("app", logrusBase, opts...)
+		}},
+	}
+
+	for name, c := range constructors {
+		var calls []string
+		log := c.create(
+			logger.WithFatalHooks(func() { calls = append(calls, "hook") }),
+			logger.WithExitFunc(func(code int) {
+				calls = append(calls, "exit")
+				if lines := readLines(t, c.path); len(lines) != 1 || !strings.Contains(lines[0], "shutting down") {
+					t.Errorf("%s: expected log line written before exit, got %v", name, lines)
+				}
+			}),
+		)
+		log.Fatal("shutting down")
+
+		if strings.Join(calls, ",") != "hook,exit" {
+			t.Errorf("%s: unexpected call order %v", name, calls)
+		}
+	}
+
+	// 未设置WithExitFunc时，钩子执行后使用logrus实例自身的ExitFunc
+	var calls []string
+	logrusBase.ExitFunc = func(code int) { calls = append(calls, "logrus exit") }
+	log := logger.NewLoggerFromLogrus("app", logrusBase, logger.WithFatalHooks(func() { calls = append(calls, "hook") }))
+	log.Fatal("again")
+	if strings.Join(calls, ",") != "hook,logrus exit" {
+		t.Errorf("logrus: unexpected call order %v", calls)
+	}
+}
+
+// TestCrashDumpPath 测试恐慌级日志在panic传播之前将日志记录和goroutine堆栈写入崩溃转储文件。
+// 门面没有可注入的panic函数，这里通过recover捕获panic
+func TestCrashDumpPath(t *testing.T) {
+	dir := t.TempDir()
+	constructors := map[string]func(name string, opts ...logger.Option) logger.Logger{
+		"console": func(name string, opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger(name, opts...) },
+		"std":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewStdLogger(name, opts...) },
+		"zap":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewZapLogger(name, opts...) },
+		"logrus":  func(name string, opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger(name, opts...) },
+		"func": func(name string, opts ...logger.Option) logger.Logger {
+			return logger.NewFuncLogger(name, logger.DebugLevel, func(logger.LogLevel, string, []logger.Field) {}, opts...)
+		},
+	}
+
+	for name, newLogger := range constructors {
+		path := filepath.Join(dir, name, "crash.log")
+		log := newLogger("app", logger.WithWriter(io.Discard), logger.WithCrashDumpPath(path))
+
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Panic to panic", name)
+				}
+			}()
+			log.Panic("state corrupted", logger.Field{Key: "order_id", Value: 42})
+		}()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: crash dump not written: %v", name, err)
+		}
+		dump := string(data)
+		if !strings.Contains(dump, "state corrupted") || !strings.Contains(dump, "order_id") {
+			t.Errorf("%s: expected the entry in the crash dump, got %q", name, dump)
+		}
+		if !strings.Contains(dump, "goroutine ") || !strings.Contains(dump, "TestCrashDumpPath") {
+			t.Errorf("%s: expected a goroutine dump in the crash dump, got %q", name, dump)
+		}
+	}
+}
+
+// TestZapPanicCaller 测试zap恐慌级日志的caller字段指向调用方而不是适配器内部
+func TestZapPanicCaller(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "zap.log")
+	log := logger.NewZapLogger("app", logger.WithOutputPath(path), logger.WithCrashDumpPath(filepath.Join(t.TempDir(), "crash.log")))
+
+	calls := []func(){
+		func() { log.Panic("panic") },
+		func() { log.Panicf("panic %d", 1) },
+		func() { log.PanicCtx(context.Background(), "panic ctx") },
+	}
+	for _, call := range calls {
+		func() {
+			defer func() { recover() }()
+			call()
+		}()
+	}
+	log.Sync()
+
+	for _, line := range readLines(t, path) {
+		if caller, _ := decodeJSONLine(t, line)["caller"].(string); !strings.HasPrefix(caller, "tests/fatal_test.go:") {
+			t.Errorf("Expected the caller to be the test, got %q", caller)
+		}
+	}
+}
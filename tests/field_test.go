@@ -0,0 +1,1161 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// decodeJSONLine 解析一行JSON日志
+func decodeJSONLine(t *testing.T, line string) map[string]interface{} {
+	t.Helper()
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &data); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", line, err)
+	}
+	return data
+}
+
+// httpGroup 测试使用的嵌套字段
+func httpGroup() logger.Field {
+	return logger.Group("http",
+		logger.Field{Key: "method", Value: "GET"},
+		logger.Field{Key: "status", Value: 200},
+	)
+}
+
+// TestGroupJSON 测试嵌套字段在JSON输出中为嵌套对象
+func TestGroupJSON(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	for name, log := range loggers {
+		log.Info("request", httpGroup())
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		nested, ok := data["http"].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s: expected nested http object, got %v", name, data["http"])
+			continue
+		}
+		if nested["method"] != "GET" || nested["status"] != float64(200) {
+			t.Errorf("%s: unexpected nested object %v", name, nested)
+		}
+	}
+}
+
+// TestGroupText 测试嵌套字段在文本输出中为点分隔的键
+func TestGroupText(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"logfmt":  logger.NewConsoleLogger("app", logger.WithFormat("logfmt"), logger.WithOutputPath(filepath.Join(dir, "logfmt.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	for name, log := range loggers {
+		log.Info("request", httpGroup())
+
+		line := readLines(t, filepath.Join(dir, name+".log"))[0]
+		if !strings.Contains(line, "http.method=GET") || !strings.Contains(line, "http.status=200") {
+			t.Errorf("%s: expected dotted keys, got %q", name, line)
+		}
+	}
+}
+
+// typedError 测试使用的自定义错误类型
+type typedError struct{}
+
+func (typedError) Error() string { return "boom" }
+
+// TestErrorFormatter 测试自定义错误格式化函数作用于WithError和error字段
+func TestErrorFormatter(t *testing.T) {
+	dir := t.TempDir()
+	formatter := logger.WithErrorFormatter(func(err error) interface{} {
+		return fmt.Sprintf("%T: %s", err, err.Error())
+	})
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log")), formatter),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log")), formatter),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log")), formatter),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log")), formatter),
+	}
+
+	want := "tests.typedError: boom"
+	for name, log := range loggers {
+		log.WithError(typedError{}).Error("failed", logger.Field{Key: "cause", Value: typedError{}})
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		if data["error"] != want || data["cause"] != want {
+			t.Errorf("%s: expected formatted errors %q, got error=%v cause=%v", name, want, data["error"], data["cause"])
+		}
+	}
+}
+
+// TestDefaultErrorFormat 测试未设置格式化函数时错误字段输出错误信息
+func TestDefaultErrorFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app", logger.WithOutputPath(path))
+	log.WithError(typedError{}).Error("failed")
+	log.Sync()
+
+	if line := readLines(t, path)[0]; !strings.HasSuffix(line, "error=boom") {
+		t.Errorf("Expected default error output, got %q", line)
+	}
+}
+
+// TestSequenceAndGoroutineID 测试seq字段递增且goid为正整数
+func TestSequenceAndGoroutineID(t *testing.T) {
+	dir := t.TempDir()
+	opts := func(name string) []logger.Option {
+		return []logger.Option{
+			logger.WithFormat("json"),
+			logger.WithOutputPath(filepath.Join(dir, name+".log")),
+			logger.WithGoroutineID(true),
+			logger.WithSequence(true),
+		}
+	}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", opts("console")...),
+		"std":     logger.NewStdLogger("app", opts("std")...),
+		"zap":     logger.NewZapLogger("app", opts("zap")...),
+		"logrus":  logger.NewLogrusLogger("app", opts("logrus")...),
+	}
+
+	for name, log := range loggers {
+		log.Info("first")
+		log.WithField("k", "v").Infof("second %d", 2)
+		log.Warn("third")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 3 {
+			t.Fatalf("%s: expected 3 lines, got %d", name, len(lines))
+		}
+		for i, line := range lines {
+			data := decodeJSONLine(t, line)
+			if data["seq"] != float64(i+1) {
+				t.Errorf("%s: expected seq %d, got %v", name, i+1, data["seq"])
+			}
+			if goid, ok := data["goid"].(float64); !ok || goid <= 0 {
+				t.Errorf("%s: expected positive goid, got %v", name, data["goid"])
+			}
+		}
+	}
+}
+
+// TestGlobalSequence 测试多个并发日志实例的gseq字段全局唯一，且在每个日志实例内严格递增
+func TestGlobalSequence(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{"console", "std", "zap", "logrus"}
+	create := map[string]func(name string, opts ...logger.Option) logger.Logger{
+		"console": func(name string, opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger(name, opts...) },
+		"std":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewStdLogger(name, opts...) },
+		"zap":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewZapLogger(name, opts...) },
+		"logrus":  func(name string, opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger(name, opts...) },
+	}
+
+	const perLogger = 50
+	var wg sync.WaitGroup
+	for _, name := range names {
+		log := create[name](name,
+			logger.WithFormat("json"),
+			logger.WithOutputPath(filepath.Join(dir, name+".log")),
+			logger.WithGlobalSequence(true),
+			logger.WithSequence(true),
+		)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perLogger; i++ {
+				log.WithField("i", i).Info("tick")
+			}
+			log.Sync()
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[float64]string)
+	for _, name := range names {
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != perLogger {
+			t.Fatalf("%s: expected %d lines, got %d", name, perLogger, len(lines))
+		}
+		last := 0.0
+		for i, line := range lines {
+			data := decodeJSONLine(t, line)
+			gseq, ok := data["gseq"].(float64)
+			if !ok || gseq <= last {
+				t.Errorf("%s: expected gseq greater than %v, got %v", name, last, data["gseq"])
+			}
+			if other, dup := seen[gseq]; dup {
+				t.Errorf("%s: gseq %v already used by %s", name, gseq, other)
+			}
+			seen[gseq] = name
+			last = gseq
+			// 每个日志实例的seq计数器独立
+			if data["seq"] != float64(i+1) {
+				t.Errorf("%s: expected seq %d, got %v", name, i+1, data["seq"])
+			}
+		}
+	}
+}
+
+// TestReservedKeyPolicy 测试字段键与保留键冲突时各策略的效果
+func TestReservedKeyPolicy(t *testing.T) {
+	dir := t.TempDir()
+
+	// rename: 冲突字段重命名为fields.msg
+	renamePath := filepath.Join(dir, "rename.log")
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(renamePath),
+		logger.WithReservedKeyPolicy(logger.ReservedKeyRename))
+	log.Info("hello", logger.Field{Key: "msg", Value: "field"})
+	log.Sync()
+
+	data := decodeJSONLine(t, readLines(t, renamePath)[0])
+	if data["msg"] != "hello" || data["fields.msg"] != "field" {
+		t.Errorf("rename: unexpected output %v", data)
+	}
+
+	// overwrite: 字段值覆盖保留键
+	overwritePath := filepath.Join(dir, "overwrite.log")
+	log = logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(overwritePath),
+		logger.WithReservedKeyPolicy(logger.ReservedKeyOverwrite))
+	log.Info("hello", logger.Field{Key: "msg", Value: "field"})
+	log.Sync()
+
+	data = decodeJSONLine(t, readLines(t, overwritePath)[0])
+	if data["msg"] != "field" {
+		t.Errorf("overwrite: expected msg to be overwritten, got %v", data)
+	}
+
+	// warn: 开发模式下默认输出一次内部警告
+	var warnings bytes.Buffer
+	logger.SetErrorOutput(&warnings)
+	defer logger.SetErrorOutput(os.Stderr)
+
+	warnPath := filepath.Join(dir, "warn.log")
+	log = logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(warnPath),
+		logger.WithDevelopment(true))
+	log.Info("hello", logger.Field{Key: "msg", Value: "field"})
+	log.Info("again", logger.Field{Key: "msg", Value: "field"})
+	log.Sync()
+
+	if n := strings.Count(warnings.String(), `"msg"`); n != 1 {
+		t.Errorf("warn: expected exactly one warning, got %q", warnings.String())
+	}
+}
+
+// orderedKeys 按出现顺序获取一行JSON日志的所有键
+func orderedKeys(t *testing.T, line string) []string {
+	t.Helper()
+	decoder := json.NewDecoder(strings.NewReader(line))
+	if _, err := decoder.Token(); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", line, err)
+	}
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			t.Fatalf("Invalid JSON %q: %v", line, err)
+		}
+		keys = append(keys, token.(string))
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatalf("Invalid JSON %q: %v", line, err)
+		}
+	}
+	return keys
+}
+
+// TestFieldOrder 测试字段按常量字段、WithField字段、上下文字段、调用字段的顺序输出
+func TestFieldOrder(t *testing.T) {
+	dir := t.TempDir()
+	ctx := logger.ContextWithFields(context.Background(), logger.Field{Key: "ctx", Value: 3})
+	opts := func(format, name string) []logger.Option {
+		return []logger.Option{
+			logger.WithFormat(format),
+			logger.WithOutputPath(filepath.Join(dir, name+".log")),
+			logger.WithConstFields(logger.Field{Key: "const", Value: 1}),
+		}
+	}
+
+	textLoggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", opts("text", "console")...),
+		"std":     logger.NewStdLogger("app", opts("text", "std")...),
+	}
+	for name, log := range textLoggers {
+		log.WithField("with", 2).InfoCtx(ctx, "ordered", logger.Field{Key: "call", Value: 4})
+		log.Sync()
+
+		line := readLines(t, filepath.Join(dir, name+".log"))[0]
+		if !strings.HasSuffix(line, "ordered const=1 with=2 ctx=3 call=4") {
+			t.Errorf("%s: unexpected field order %q", name, line)
+		}
+	}
+
+	jsonLoggers := map[string]logger.Logger{
+		"console-json": logger.NewConsoleLogger("app", opts("json", "console-json")...),
+		"zap":          logger.NewZapLogger("app", opts("json", "zap")...),
+	}
+	for name, log := range jsonLoggers {
+		log.WithField("with", 2).InfoCtx(ctx, "ordered", logger.Field{Key: "call", Value: 4})
+		log.Sync()
+
+		keys := orderedKeys(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		got := strings.Join(keys[len(keys)-4:], ",")
+		if got != "const,with,ctx,call" {
+			t.Errorf("%s: unexpected field order %v", name, keys)
+		}
+	}
+}
+
+// TestDedupeFields 测试开启去重后同名字段保留首次位置和最后的值
+func TestDedupeFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app",
+		logger.WithOutputPath(path),
+		logger.WithConstFields(logger.Field{Key: "env", Value: "prod"}),
+		logger.WithDedupeFields(true),
+	)
+
+	log.WithField("user", "alice").Info("dedupe", logger.Field{Key: "env", Value: "staging"}, logger.Field{Key: "user", Value: "bob"})
+	log.Sync()
+
+	line := readLines(t, path)[0]
+	if !strings.HasSuffix(line, "dedupe env=staging user=bob") {
+		t.Errorf("Unexpected deduped output %q", line)
+	}
+}
+
+// address 测试使用的嵌套结构体
+type address struct {
+	City string `log:"city"`
+	Zip  string `log:"zip,omitempty"`
+}
+
+// signupRequest 测试使用的请求结构体
+type signupRequest struct {
+	Name     string `log:"name"`
+	Password string `log:"-"`
+	Age      int    `log:"age,omitempty"`
+	Address  *address
+	Next     *signupRequest `log:"next,omitempty"`
+	internal string
+}
+
+// TestLogrusSortKeys 测试logrus JSON输出的键（包括嵌套字段）按字母排序，同名键保留后出现的值
+func TestLogrusSortKeys(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithWriter(&buf), logger.WithLogrusSortKeys(true))
+
+	log.WithFields(logger.Field{Key: "b", Value: 1}, logger.Field{Key: "a", Value: 1}).Info("sorted",
+		logger.Field{Key: "a", Value: 2},
+		logger.Group("req", logger.Field{Key: "z", Value: 1}, logger.Field{Key: "y", Value: "get"}, logger.Field{Key: "z", Value: 2}),
+	)
+
+	line := strings.TrimSpace(buf.String())
+	pattern := regexp.MustCompile(`^\{"a":2,"b":1,"level":"info","msg":"sorted","req":\{"y":"get","z":2\},"time":"[^"]+"\}$`)
+	if !pattern.MatchString(line) {
+		t.Errorf("Expected sorted, deduplicated keys, got %s", line)
+	}
+}
+
+// money 测试用的自定义金额类型
+type money struct {
+	Cents    int64
+	Currency string
+}
+
+// TestWithMarshaler 测试按类型注册的转换函数作用于JSON和文本输出，包括嵌套字段
+func TestWithMarshaler(t *testing.T) {
+	opt := logger.WithMarshaler(reflect.TypeOf(money{}), func(v interface{}) interface{} {
+		m := v.(money)
+		return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)
+	})
+
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", opt, logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"zap":     logger.NewZapLogger("app", opt, logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", opt, logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+	for name, log := range loggers {
+		log.Info("charged", logger.Field{Key: "amount", Value: money{Cents: 1250, Currency: "USD"}},
+			logger.Group("refund", logger.Field{Key: "amount", Value: money{Cents: 99, Currency: "EUR"}}))
+		log.Sync()
+
+		entry := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		if entry["amount"] != "12.50 USD" {
+			t.Errorf("%s: expected custom rendering, got %v", name, entry["amount"])
+		}
+		if refund, _ := entry["refund"].(map[string]interface{}); refund["amount"] != "0.99 EUR" {
+			t.Errorf("%s: expected custom rendering in nested fields, got %v", name, entry["refund"])
+		}
+	}
+
+	var buf bytes.Buffer
+	log := logger.NewConsoleLogger("app", opt, logger.WithWriter(&buf))
+	log.Info("charged", logger.Field{Key: "amount", Value: money{Cents: 1250, Currency: "USD"}}, logger.Field{Key: "other", Value: &money{Cents: 1}})
+	if line := buf.String(); !strings.Contains(line, "amount=12.50 USD other=") || strings.Contains(line, "Cents:1250") {
+		t.Errorf("Expected custom rendering in text output, got %q", line)
+	}
+}
+
+// TestWithStruct 测试结构体展开为带前缀的字段
+func TestWithStruct(t *testing.T) {
+	req := &signupRequest{
+		Name:     "alice",
+		Password: "secret",
+		Address:  &address{City: "Hangzhou"},
+		internal: "hidden",
+	}
+	req.Next = req
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app", logger.WithOutputPath(path))
+	log.WithStruct("req", req).Info("signup")
+	log.Sync()
+
+	line := readLines(t, path)[0]
+	if !strings.HasSuffix(line, "signup req.name=alice req.Address.city=Hangzhou req.next=<cycle>") {
+		t.Errorf("Unexpected struct fields %q", line)
+	}
+	for _, hidden := range []string{"Password", "secret", "age", "zip", "internal"} {
+		if strings.Contains(line, hidden) {
+			t.Errorf("Expected %q to be omitted, got %q", hidden, line)
+		}
+	}
+}
+
+// point 实现了fmt.Stringer的测试类型
+type point struct {
+	X, Y int
+}
+
+func (p point) String() string { return fmt.Sprintf("(%d,%d)", p.X, p.Y) }
+
+// TestStringerFields 测试实现了fmt.Stringer的字段值输出String()的结果
+func TestStringerFields(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+		"text":    logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "text.log"))),
+	}
+
+	for name, log := range loggers {
+		log.Info("moved", logger.Field{Key: "pos", Value: point{X: 1, Y: 2}}, logger.Field{Key: "err", Value: typedError{}})
+		log.Sync()
+
+		line := readLines(t, filepath.Join(dir, name+".log"))[0]
+		if name == "text" {
+			if !strings.HasSuffix(line, "pos=(1,2) err=boom") {
+				t.Errorf("%s: unexpected output %q", name, line)
+			}
+			continue
+		}
+
+		data := decodeJSONLine(t, line)
+		if data["pos"] != "(1,2)" || data["err"] != "boom" {
+			t.Errorf("%s: expected String() and Error() output, got pos=%v err=%v", name, data["pos"], data["err"])
+		}
+	}
+}
+
+// TestMaxFieldBytes 测试超长字段值被截断而短字段保持不变
+func TestMaxFieldBytes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app",
+		logger.WithFormat("json"),
+		logger.WithOutputPath(path),
+		logger.WithMaxFieldBytes(16),
+	)
+
+	log.Info("payload",
+		logger.Field{Key: "body", Value: strings.Repeat("x", 1000)},
+		logger.Field{Key: "short", Value: "ok"},
+		logger.Field{Key: "count", Value: 42},
+	)
+	log.Sync()
+
+	data := decodeJSONLine(t, readLines(t, path)[0])
+	if data["body"] != strings.Repeat("x", 16)+"...(984 bytes truncated)" {
+		t.Errorf("Expected truncated body, got %v", data["body"])
+	}
+	if data["short"] != "ok" || data["count"] != float64(42) {
+		t.Errorf("Expected small fields untouched, got %v", data)
+	}
+}
+
+// TestMaxFields 测试合并后的字段数超过上限时保留前n个字段并添加fields_truncated字段
+func TestMaxFields(t *testing.T) {
+	rec := &recorder{}
+	log := logger.NewFuncLogger("app", logger.DebugLevel, rec.emit,
+		logger.WithConstFields(logger.Field{Key: "service", Value: "api"}),
+		logger.WithMaxFields(3),
+	)
+
+	log.WithField("request_id", "r-1").Info("many",
+		logger.Field{Key: "a", Value: 1},
+		logger.Field{Key: "b", Value: 2},
+		logger.Field{Key: "c", Value: 3},
+	)
+	log.Info("few", logger.Field{Key: "a", Value: 1})
+
+	fields := rec.calls[0].fields
+	if len(fields) != 4 {
+		t.Fatalf("Expected 3 fields plus the marker, got %+v", fields)
+	}
+	for i, key := range []string{"service", "request_id", "a", "fields_truncated"} {
+		if fields[i].Key != key {
+			t.Errorf("Expected field %d to be %q, got %q", i, key, fields[i].Key)
+		}
+	}
+	if fieldValue(fields, "fields_truncated") != 2 {
+		t.Errorf("Expected fields_truncated=2, got %v", fieldValue(fields, "fields_truncated"))
+	}
+
+	if fields := rec.calls[1].fields; len(fields) != 2 || fieldValue(fields, "fields_truncated") != nil {
+		t.Errorf("Expected entries within the cap to be untouched, got %+v", fields)
+	}
+
+	// 各适配器共用同一合并路径
+	path := filepath.Join(t.TempDir(), "zap.log")
+	zl := logger.NewZapLogger("app", logger.WithOutputPath(path), logger.WithMaxFields(1))
+	zl.Info("many", logger.Field{Key: "a", Value: 1}, logger.Field{Key: "b", Value: 2})
+	zl.Sync()
+	data := decodeJSONLine(t, readLines(t, path)[0])
+	if data["a"] != float64(1) || data["b"] != nil || data["fields_truncated"] != float64(1) {
+		t.Errorf("Expected zap output to be capped, got %v", data)
+	}
+}
+
+// TestJoinedErrors 测试errors.Join组合的错误在JSON中输出为数组
+func TestJoinedErrors(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	joined := errors.Join(errors.New("db down"), errors.New("cache down"))
+	for name, log := range loggers {
+		log.WithError(joined).Error("health check failed")
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		errs, ok := data["error"].([]interface{})
+		if !ok || len(errs) != 2 || errs[0] != "db down" || errs[1] != "cache down" {
+			t.Errorf("%s: expected error array, got %v", name, data["error"])
+		}
+	}
+}
+
+// TestDurationUnit 测试time.Duration字段按配置的单位输出数值
+func TestDurationUnit(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithDurationUnit(time.Millisecond)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		log.Info("request done", logger.Field{Key: "latency", Value: 1500 * time.Millisecond})
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		if data["latency_ms"] != float64(1500) {
+			t.Errorf("%s: expected latency_ms=1500, got %v", name, data)
+		}
+		if _, ok := data["latency"]; ok {
+			t.Errorf("%s: unexpected unsuffixed key in %v", name, data)
+		}
+	}
+}
+
+// TestRedactPattern 测试按正则对消息和字段值脱敏
+func TestRedactPattern(t *testing.T) {
+	dir := t.TempDir()
+	email := regexp.MustCompile(`[\w.+-]+@[\w-]+\.[\w.]+`)
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithRedactPattern(email, "[email]")}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		log.Info("password reset for alice@example.com", logger.Field{Key: "note", Value: "cc bob@example.org"})
+		log.Infof("welcome %s", "carol@example.net")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 2 {
+			t.Fatalf("%s: expected 2 lines, got %d", name, len(lines))
+		}
+		first := decodeJSONLine(t, lines[0])
+		if first["msg"] != "password reset for [email]" || first["note"] != "cc [email]" {
+			t.Errorf("%s: expected redacted message and field, got %v", name, first)
+		}
+		if second := decodeJSONLine(t, lines[1]); second["msg"] != "welcome [email]" {
+			t.Errorf("%s: expected redacted formatted message, got %v", name, second)
+		}
+	}
+}
+
+// TestProcessInfo 测试host和pid字段
+func TestProcessInfo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app",
+		logger.WithFormat("json"),
+		logger.WithOutputPath(path),
+		logger.WithHostname(true),
+		logger.WithPID(true),
+	)
+	log.Info("started")
+	log.Sync()
+
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("os.Hostname failed: %v", err)
+	}
+
+	data := decodeJSONLine(t, readLines(t, path)[0])
+	if data["host"] != host {
+		t.Errorf("Expected host %q, got %v", host, data["host"])
+	}
+	if data["pid"] != float64(os.Getpid()) {
+		t.Errorf("Expected pid %d, got %v", os.Getpid(), data["pid"])
+	}
+}
+
+// TestBuildInfoFields 测试设置构建信息后新创建的日志实例附加version、commit和build_time字段
+func TestBuildInfoFields(t *testing.T) {
+	previous := logger.GetBuildInfo()
+	defer logger.SetBuildInfo(previous.Version, previous.Commit, previous.BuildTime)
+	logger.SetBuildInfo("v1.2.0", "abc1234", "2024-01-02T03:04:05Z")
+
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithBuildInfoFields(true), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithBuildInfoFields(true), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+	}
+
+	for name, log := range loggers {
+		log.Info("started")
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		if data["version"] != "v1.2.0" || data["commit"] != "abc1234" || data["build_time"] != "2024-01-02T03:04:05Z" {
+			t.Errorf("%s: expected build info fields, got %v", name, data)
+		}
+	}
+
+	path := filepath.Join(dir, "disabled.log")
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(path))
+	log.Info("started")
+	log.Sync()
+
+	if data := decodeJSONLine(t, readLines(t, path)[0]); data["version"] != nil {
+		t.Errorf("Expected no build info fields without the option, got %v", data)
+	}
+}
+
+// TestCallerFields 测试调用位置以独立字段输出
+func TestCallerFields(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithCallerFields(true)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		_, file, line, _ := runtime.Caller(0)
+		log.WithField("k", "v").Info("here")
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		if data["caller.file"] != "tests/field_test.go" || !strings.HasSuffix(file, "tests/field_test.go") {
+			t.Errorf("%s: unexpected caller.file %v", name, data["caller.file"])
+		}
+		if data["caller.line"] != float64(line+1) {
+			t.Errorf("%s: expected caller.line %d, got %v", name, line+1, data["caller.line"])
+		}
+		if data["caller.func"] != "github.com/LandcLi/LandcLogFace/tests.TestCallerFields" {
+			t.Errorf("%s: unexpected caller.func %v", name, data["caller.func"])
+		}
+	}
+}
+
+// TestUptimeField 测试uptime字段随时间递增，StartTimer后重新计时
+func TestUptimeField(t *testing.T) {
+	dir := t.TempDir()
+	clock := &manualClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	opts := []logger.Option{
+		logger.WithFormat("json"),
+		logger.WithClock(clock),
+		logger.WithDurationUnit(time.Millisecond),
+		logger.WithUptimeField(true),
+	}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+	start := clock.t
+
+	for name, log := range loggers {
+		clock.t = start
+		log.(logger.TimerStarter).StartTimer()
+		for i := 0; i < 3; i++ {
+			clock.t = clock.t.Add(250 * time.Millisecond)
+			log.Info("step")
+		}
+		log.(logger.TimerStarter).StartTimer()
+		log.WithField("k", "v").Info("restarted")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		expected := []float64{250, 500, 750, 0}
+		if len(lines) != len(expected) {
+			t.Fatalf("%s: expected %d lines, got %q", name, len(expected), lines)
+		}
+		for i, line := range lines {
+			if data := decodeJSONLine(t, line); data["uptime_ms"] != expected[i] {
+				t.Errorf("%s: expected uptime_ms=%v on line %d, got %v", name, expected[i], i, data)
+			}
+		}
+	}
+}
+
+// TestFieldTrace 测试开发模式下为多次设置的键输出各个来源的调用位置
+func TestFieldTrace(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithDevelopment(true), logger.WithFieldTrace(true)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		_, _, line, _ := runtime.Caller(0)
+		handler := log.WithField("status", 200).WithField("path", "/users")
+		handler = handler.WithFields(logger.Field{Key: "status", Value: 500})
+		handler.Info("request done", logger.Field{Key: "status", Value: 503})
+		handler.Info("no conflict")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 2 {
+			t.Fatalf("%s: expected 2 lines, got %q", name, lines)
+		}
+		trace, ok := decodeJSONLine(t, lines[0])["field_trace"].(map[string]interface{})
+		if !ok {
+			t.Fatalf("%s: expected a field_trace object, got %q", name, lines[0])
+		}
+		expected := fmt.Sprintf("[tests/field_test.go:%d tests/field_test.go:%d tests/field_test.go:%d]", line+1, line+2, line+3)
+		if got := fmt.Sprint(trace["status"]); got != expected || len(trace) != 1 {
+			t.Errorf("%s: expected status sources %s, got %v", name, expected, trace)
+		}
+		if _, ok := decodeJSONLine(t, lines[1])["field_trace"].(map[string]interface{}); !ok {
+			t.Errorf("%s: expected the inherited conflict to be traced, got %q", name, lines[1])
+		}
+	}
+
+	// 非开发模式下不输出诊断字段
+	path := filepath.Join(dir, "prod.log")
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithFieldTrace(true), logger.WithOutputPath(path))
+	log.WithField("status", 200).Info("done", logger.Field{Key: "status", Value: 500})
+	log.Sync()
+	if _, ok := decodeJSONLine(t, readLines(t, path)[0])["field_trace"]; ok {
+		t.Error("expected no field_trace outside development mode")
+	}
+}
+
+// TestWarnStackDepth 测试警告级日志包含指定数量的栈帧，其他级别不包含
+func TestWarnStackDepth(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithWarnStackDepth(2)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		_, _, line, _ := runtime.Caller(0)
+		log.Warn("deprecated path")
+		log.Warnf("deprecated %s", "path")
+		log.Info("normal")
+		log.Error("failed")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 4 {
+			t.Fatalf("%s: expected 4 lines, got %q", name, lines)
+		}
+		for i, offset := range []int{1, 2} {
+			stack, ok := decodeJSONLine(t, lines[i])["stack"].([]interface{})
+			if !ok || len(stack) != 2 {
+				t.Fatalf("%s: expected 2 frames on line %d, got %q", name, i, lines[i])
+			}
+			expected := fmt.Sprintf("github.com/LandcLi/LandcLogFace/tests.TestWarnStackDepth (tests/field_test.go:%d)", line+offset)
+			if stack[0] != expected || !strings.HasPrefix(stack[1].(string), "testing.tRunner") {
+				t.Errorf("%s: unexpected frames %v", name, stack)
+			}
+		}
+		for _, l := range lines[2:] {
+			if _, ok := decodeJSONLine(t, l)["stack"]; ok {
+				t.Errorf("%s: unexpected stack on %q", name, l)
+			}
+		}
+	}
+}
+
+// TestSingleLineStacktrace 测试开启单行堆栈后stack和stacktrace字段不包含换行符，未开启时保持原样
+func TestSingleLineStacktrace(t *testing.T) {
+	dir := t.TempDir()
+	stack := string(debug.Stack())
+	opts := []logger.Option{logger.WithWarnStackDepth(3), logger.WithSingleLineStacktrace(true)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+		"text":    logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "text.log")))...),
+	}
+
+	for name, log := range loggers {
+		log.Warn("recovered", logger.Field{Key: "stacktrace", Value: stack})
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 1 {
+			t.Fatalf("%s: expected 1 line, got %q", name, lines)
+		}
+		if name == "text" {
+			if strings.Contains(lines[0], `\n`) || !strings.Contains(lines[0], " | ") {
+				t.Errorf("%s: expected a single-line stack, got %q", name, lines[0])
+			}
+			continue
+		}
+
+		entry := decodeJSONLine(t, lines[0])
+		for _, key := range []string{"stack", "stacktrace"} {
+			value, ok := entry[key].(string)
+			if !ok || strings.Contains(value, "\n") || !strings.Contains(value, " | ") {
+				t.Errorf("%s: expected a single-line %s, got %#v", name, key, entry[key])
+			}
+		}
+		if frames := strings.Split(entry["stack"].(string), " | "); len(frames) != 3 {
+			t.Errorf("%s: expected 3 frames, got %q", name, frames)
+		}
+		if !strings.Contains(entry["stacktrace"].(string), "runtime/debug.Stack()\t") {
+			t.Errorf("%s: expected function and location joined by a tab, got %q", name, entry["stacktrace"])
+		}
+	}
+
+	var buf bytes.Buffer
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithWriter(&buf))
+	log.Warn("recovered", logger.Field{Key: "stacktrace", Value: stack})
+	if value, _ := decodeJSONLine(t, strings.TrimSpace(buf.String()))["stacktrace"].(string); value != stack {
+		t.Errorf("Expected the stack to be unchanged by default, got %q", value)
+	}
+}
+
+// TestRuntimeStats 测试警告及以上级别的日志包含运行时统计字段，低级别日志不包含
+func TestRuntimeStats(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithRuntimeStats(true)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	keys := []string{"goroutines", "heap_alloc", "num_gc"}
+	for name, log := range loggers {
+		log.Info("normal")
+		log.Warn("pool exhausted")
+		log.WithField("k", "v").Error("failed")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 3 {
+			t.Fatalf("%s: expected 3 lines, got %q", name, lines)
+		}
+		info := decodeJSONLine(t, lines[0])
+		for _, key := range keys {
+			if _, ok := info[key]; ok {
+				t.Errorf("%s: unexpected %s on info line %q", name, key, lines[0])
+			}
+		}
+		for _, line := range lines[1:] {
+			data := decodeJSONLine(t, line)
+			if goroutines, ok := data["goroutines"].(float64); !ok || goroutines < 1 {
+				t.Errorf("%s: expected positive goroutines, got %q", name, line)
+			}
+			if heap, ok := data["heap_alloc"].(float64); !ok || heap <= 0 {
+				t.Errorf("%s: expected positive heap_alloc, got %q", name, line)
+			}
+			if _, ok := data["num_gc"].(float64); !ok {
+				t.Errorf("%s: expected num_gc, got %q", name, line)
+			}
+		}
+	}
+}
+
+// TestOmitNilFields 测试开启后值为nil的字段被去除，WithError(nil)不添加字段
+func TestOmitNilFields(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithOmitNilFields(true)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	var user *struct{ Name string }
+	for name, log := range loggers {
+		log.WithField("x", nil).Info("done",
+			logger.Field{Key: "user", Value: user},
+			logger.Field{Key: "kept", Value: 0},
+			logger.Group("http", logger.Field{Key: "status", Value: nil}, logger.Field{Key: "method", Value: "GET"}),
+		)
+		log.Sync()
+
+		data := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])
+		for _, key := range []string{"x", "user", "http.status"} {
+			if _, ok := data[key]; ok {
+				t.Errorf("%s: expected %s to be omitted, got %v", name, key, data)
+			}
+		}
+		if http, _ := data["http"].(map[string]interface{}); data["kept"] != float64(0) || http["method"] != "GET" || len(http) != 1 {
+			t.Errorf("%s: expected non-nil fields to be kept, got %v", name, data)
+		}
+	}
+}
+
+// TestFieldAllowlist 测试键不在允许列表中的字段在文本和JSON格式中被丢弃，开发模式下每个键只警告一次
+func TestFieldAllowlist(t *testing.T) {
+	var warnings bytes.Buffer
+	logger.SetErrorOutput(&warnings)
+	defer logger.SetErrorOutput(os.Stderr)
+
+	var text, jsonBuf bytes.Buffer
+	allow := logger.WithFieldAllowlist("request_id", "user_id", "http")
+	textLogger := logger.NewConsoleLogger("app", logger.WithWriter(&text), logger.WithDevelopment(true), allow)
+	jsonLogger := logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithWriter(&jsonBuf), allow)
+
+	for _, log := range []logger.Logger{textLogger, jsonLogger} {
+		log.WithFields(logger.Field{Key: "request_id", Value: "r-1"}, logger.Field{Key: "password", Value: "secret"}).
+			Info("login", logger.Field{Key: "user_id", Value: 7}, logger.Field{Key: "debug_blob", Value: "x"},
+				logger.Group("http", logger.Field{Key: "status", Value: 200}))
+		log.Info("again", logger.Field{Key: "password", Value: "secret"})
+		log.Sync()
+	}
+
+	line := strings.Split(text.String(), "\n")[0]
+	if !strings.Contains(line, "request_id=r-1") || !strings.Contains(line, "user_id=7") || !strings.Contains(line, "http.status=200") {
+		t.Errorf("Expected allowed fields in text output, got %q", line)
+	}
+	if strings.Contains(text.String(), "password") || strings.Contains(text.String(), "debug_blob") {
+		t.Errorf("Expected disallowed fields to be dropped from text output, got %q", text.String())
+	}
+
+	data := decodeJSONLine(t, strings.Split(jsonBuf.String(), "\n")[0])
+	if http, _ := data["http"].(map[string]interface{}); data["request_id"] != "r-1" || data["user_id"] != float64(7) || http["status"] != float64(200) {
+		t.Errorf("Expected allowed fields in JSON output, got %v", data)
+	}
+	if strings.Contains(jsonBuf.String(), "password") || strings.Contains(jsonBuf.String(), "debug_blob") {
+		t.Errorf("Expected disallowed fields to be dropped from JSON output, got %q", jsonBuf.String())
+	}
+
+	// 只有开发模式的日志实例警告，每个键一次
+	if strings.Count(warnings.String(), `"password"`) != 1 || strings.Count(warnings.String(), `"debug_blob"`) != 1 {
+		t.Errorf("Expected one warning per dropped key, got %q", warnings.String())
+	}
+}
+
+// TestWithErrorNil 测试WithError(nil)不添加error字段
+func TestWithErrorNil(t *testing.T) {
+	dir := t.TempDir()
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "console.log"))),
+		"std":     logger.NewStdLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "std.log"))),
+		"zap":     logger.NewZapLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "zap.log"))),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithFormat("json"), logger.WithOutputPath(filepath.Join(dir, "logrus.log"))),
+	}
+
+	for name, log := range loggers {
+		if log.WithError(nil) != log {
+			t.Errorf("%s: expected WithError(nil) to return the same logger", name)
+		}
+		log.WithError(nil).Info("done")
+		log.Sync()
+
+		if _, ok := decodeJSONLine(t, readLines(t, filepath.Join(dir, name+".log"))[0])["error"]; ok {
+			t.Errorf("%s: unexpected error field", name)
+		}
+	}
+}
+
+// TestDisableStacktrace 测试禁止zap附加stacktrace字段后错误和恐慌级日志没有stacktrace键，门面的stack字段不受影响
+func TestDisableStacktrace(t *testing.T) {
+	var buf bytes.Buffer
+	log := logger.NewZapLogger("app", logger.WithWriter(&buf), logger.WithDisableStacktrace(true), logger.WithWarnStackDepth(2))
+
+	log.Warn("slow")
+	log.Error("failed")
+	func() {
+		defer func() { recover() }()
+		log.Panic("crashed")
+	}()
+	log.Sync()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", lines)
+	}
+	for _, line := range lines {
+		if _, ok := decodeJSONLine(t, line)["stacktrace"]; ok {
+			t.Errorf("Expected no stacktrace key, got %s", line)
+		}
+	}
+	if stack, ok := decodeJSONLine(t, lines[0])["stack"].([]interface{}); !ok || len(stack) != 2 {
+		t.Errorf("Expected the facade stack field to be kept, got %s", lines[0])
+	}
+}
+
+// TestHashed 测试Hashed字段输出稳定的短哈希而不是原值，相同的值哈希相同，不同的值哈希不同
+func TestHashed(t *testing.T) {
+	rec := &recorder{}
+	log := logger.NewFuncLogger("app", logger.InfoLevel, rec.emit)
+
+	log.Info("login", logger.Hashed("email", "alice@example.com"))
+	log.Info("login", logger.Hashed("email", "alice@example.com"))
+	log.Info("login", logger.Hashed("email", "bob@example.com"))
+
+	hashes := make([]string, 0, 3)
+	for _, call := range rec.calls[:3] {
+		hash, ok := fieldValue(call.fields, "email").(string)
+		if !ok || len(hash) != 8 || strings.Contains(hash, "@") {
+			t.Fatalf("Expected an 8 character hash, got %#v", fieldValue(call.fields, "email"))
+		}
+		hashes = append(hashes, hash)
+	}
+	sum := sha256.Sum256([]byte("alice@example.com"))
+	if expected := hex.EncodeToString(sum[:])[:8]; hashes[0] != expected {
+		t.Errorf("Expected sha256 prefix %s, got %s", expected, hashes[0])
+	}
+	if hashes[0] != hashes[1] {
+		t.Errorf("Expected the same input to yield the same hash, got %s and %s", hashes[0], hashes[1])
+	}
+	if hashes[0] == hashes[2] {
+		t.Errorf("Expected different inputs to yield different hashes, got %s", hashes[0])
+	}
+
+	var buf bytes.Buffer
+	jsonLog := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithWriter(&buf))
+	jsonLog.Info("request", logger.Group("client", logger.Hashed("ip", net.ParseIP("10.0.0.1"))))
+	client, _ := decodeJSONLine(t, strings.TrimSpace(buf.String()))["client"].(map[string]interface{})
+	if ip, _ := client["ip"].(string); len(ip) != 8 || strings.Contains(ip, "10.0.0.1") {
+		t.Errorf("Expected a hashed nested ip, got %v", client)
+	}
+}
+
+// TestConditionalStacktrace 测试只有判断函数返回true的日志带有stacktrace字段
+func TestConditionalStacktrace(t *testing.T) {
+	dir := t.TempDir()
+	var calls int
+	critical := func(level logger.LogLevel, msg string, fields []logger.Field) bool {
+		calls++
+		if strings.Contains(msg, "deadlock") {
+			return true
+		}
+		for _, field := range fields {
+			if field.Key == "critical" && field.Value == true {
+				return true
+			}
+		}
+		return false
+	}
+	opts := []logger.Option{logger.WithFormat("json"), logger.WithConditionalStacktrace(critical)}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		log.Error("db timeout")
+		log.Errorf("%s detected", "deadlock")
+		log.WithField("critical", true).Warn("quota exceeded")
+		log.InfoCtx(context.Background(), "ok", logger.Field{Key: "critical", Value: false})
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 4 {
+			t.Fatalf("%s: expected 4 lines, got %q", name, lines)
+		}
+		for i, expected := range []bool{false, true, true, false} {
+			data := decodeJSONLine(t, lines[i])
+			stack, ok := data["stacktrace"].([]interface{})
+			if ok != expected {
+				t.Errorf("%s: expected stacktrace=%v on line %d, got %v", name, expected, i, data)
+				continue
+			}
+			if ok && (len(stack) == 0 || !strings.Contains(stack[0].(string), "TestConditionalStacktrace")) {
+				t.Errorf("%s: expected the stack to start at the caller, got %v", name, stack)
+			}
+		}
+	}
+	if calls != 16 {
+		t.Errorf("Expected the predicate to run once per emitted entry, got %d calls", calls)
+	}
+}
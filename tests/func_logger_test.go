@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// emitted 回调函数收到的一条日志
+type emitted struct {
+	level  logger.LogLevel
+	msg    string
+	fields []logger.Field
+}
+
+// recorder 记录回调函数收到的日志
+type recorder struct {
+	calls []emitted
+}
+
+func (r *recorder) emit(level logger.LogLevel, msg string, fields []logger.Field) {
+	r.calls = append(r.calls, emitted{level: level, msg: msg, fields: fields})
+}
+
+// TestFuncLogger 测试各级别的日志分发给回调函数，并按级别过滤
+func TestFuncLogger(t *testing.T) {
+	rec := &recorder{}
+	exitCode := -1
+	log := logger.NewFuncLogger("bridge", logger.DebugLevel, rec.emit, logger.WithExitFunc(func(code int) { exitCode = code }))
+
+	log.Debug("debug")
+	log.Infof("info %d", 1)
+	log.WarnCtx(context.Background(), "warn")
+	log.Error("error")
+	log.Fatal("fatal")
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected Panic to panic")
+			}
+		}()
+		log.Panic("panic")
+	}()
+
+	expected := []emitted{
+		{level: logger.DebugLevel, msg: "debug"},
+		{level: logger.InfoLevel, msg: "info 1"},
+		{level: logger.WarnLevel, msg: "warn"},
+		{level: logger.ErrorLevel, msg: "error"},
+		{level: logger.FatalLevel, msg: "fatal"},
+		{level: logger.PanicLevel, msg: "panic"},
+	}
+	if len(rec.calls) != len(expected) {
+		t.Fatalf("Expected %d calls, got %d", len(expected), len(rec.calls))
+	}
+	for i, call := range rec.calls {
+		if call.level != expected[i].level || call.msg != expected[i].msg {
+			t.Errorf("Call %d: expected %v %q, got %v %q", i, expected[i].level, expected[i].msg, call.level, call.msg)
+		}
+	}
+	if exitCode != 1 {
+		t.Errorf("Expected Fatal to exit with code 1, got %d", exitCode)
+	}
+
+	rec.calls = nil
+	log.SetLevel(logger.WarnLevel)
+	log.Debug("filtered")
+	log.Info("filtered")
+	log.WithField("user", "alice").Warn("kept", logger.Field{Key: "count", Value: 3})
+
+	if len(rec.calls) != 1 || rec.calls[0].msg != "kept" {
+		t.Fatalf("Expected only the warning to pass the level filter, got %+v", rec.calls)
+	}
+	if fields := rec.calls[0].fields; len(fields) != 2 || fields[0].Key != "user" || fields[1].Key != "count" {
+		t.Errorf("Expected logger and call fields, got %v", fields)
+	}
+	if log.IsInfoEnabled() || !log.IsWarnEnabled() {
+		t.Error("Expected IsXEnabled to follow the level")
+	}
+}
+
+// TestFuncLoggerIgnoresOutputOptions 测试输出相关的选项对回调日志实例不生效，不会创建输出目录和文件
+func TestFuncLoggerIgnoresOutputOptions(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	rec := &recorder{}
+	log := logger.NewFuncLogger("bridge", logger.InfoLevel, rec.emit,
+		logger.WithOutputPath(filepath.Join(dir, "app.log")),
+		logger.WithCompressedOutput(true))
+	log.Info("hello")
+
+	if len(rec.calls) != 1 || rec.calls[0].msg != "hello" {
+		t.Fatalf("Expected the entry to reach the callback, got %+v", rec.calls)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("Expected no output directory to be created, got %v", err)
+	}
+}
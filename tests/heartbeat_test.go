@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestHeartbeat 测试心跳日志按间隔输出且序号递增，Close后不再输出
+func TestHeartbeat(t *testing.T) {
+	dir := t.TempDir()
+	constructors := map[string]func(name string, opts ...logger.Option) logger.Logger{
+		"console": func(name string, opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger(name, opts...) },
+		"std":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewStdLogger(name, opts...) },
+		"zap":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewZapLogger(name, opts...) },
+		"logrus":  func(name string, opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger(name, opts...) },
+	}
+
+	for name, newLogger := range constructors {
+		path := filepath.Join(dir, name+".log")
+		log := newLogger("worker", logger.WithFormat("json"), logger.WithOutputPath(path), logger.WithHeartbeat(10*time.Millisecond, "worker alive"))
+
+		deadline := time.Now().Add(2 * time.Second)
+		for len(readLines(t, path)) < 2 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if err := log.(interface{ Close() error }).Close(); err != nil {
+			t.Fatalf("%s: Close failed: %v", name, err)
+		}
+
+		lines := readLines(t, path)
+		if len(lines) < 2 {
+			t.Fatalf("%s: expected at least two heartbeats, got %q", name, lines)
+		}
+		for i, line := range lines {
+			entry := decodeJSONLine(t, line)
+			if entry["msg"] != "worker alive" || entry["heartbeat"] != float64(i+1) {
+				t.Errorf("%s: unexpected heartbeat %d: %q", name, i+1, line)
+			}
+		}
+
+		time.Sleep(30 * time.Millisecond)
+		if after := readLines(t, path); len(after) != len(lines) {
+			t.Errorf("%s: expected no heartbeats after Close, got %d more", name, len(after)-len(lines))
+		}
+	}
+}
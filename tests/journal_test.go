@@ -0,0 +1,92 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestJournalRoundTrip 测试日志记录写入后可以完整读回
+func TestJournalRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "app.journal")
+	base := logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "app.log")))
+
+	journal, err := logger.NewJournalLogger(base, journalPath)
+	if err != nil {
+		t.Fatalf("NewJournalLogger failed: %v", err)
+	}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	journal.WithTime(at).Info("started", logger.Field{Key: "port", Value: 8080})
+	journal.WithField("user", "alice").Warn("slow", logger.Field{Key: "latency", Value: 1500 * time.Millisecond})
+	journal.Errorf("failed after %d retries", 3)
+	journal.Debug("filtered by level")
+	journal.Sync()
+	journal.Close()
+
+	entries, err := logger.ReadJournal(journalPath)
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+
+	expected := []struct {
+		level  logger.LogLevel
+		msg    string
+		fields []logger.Field
+	}{
+		{logger.InfoLevel, "started", []logger.Field{{Key: "port", Value: 8080}}},
+		{logger.WarnLevel, "slow", []logger.Field{{Key: "user", Value: "alice"}, {Key: "latency", Value: 1500 * time.Millisecond}}},
+		{logger.ErrorLevel, "failed after 3 retries", nil},
+	}
+	for i, want := range expected {
+		got := entries[i]
+		if got.Level != want.level || got.Message != want.msg || got.Name != "app" {
+			t.Errorf("entry %d: unexpected %+v", i, got)
+		}
+		if len(want.fields) > 0 && !reflect.DeepEqual(got.Fields, want.fields) {
+			t.Errorf("entry %d: expected fields %v, got %v", i, want.fields, got.Fields)
+		}
+	}
+	if !entries[0].Time.Equal(at) {
+		t.Errorf("Expected time %v, got %v", at, entries[0].Time)
+	}
+}
+
+// TestJournalTruncatedRecord 测试末尾不完整的记录被忽略
+func TestJournalTruncatedRecord(t *testing.T) {
+	dir := t.TempDir()
+	journalPath := filepath.Join(dir, "app.journal")
+	base := logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "app.log")))
+
+	journal, err := logger.NewJournalLogger(base, journalPath)
+	if err != nil {
+		t.Fatalf("NewJournalLogger failed: %v", err)
+	}
+	journal.Info("first")
+	journal.Info("second")
+	journal.Close()
+
+	info, err := os.Stat(journalPath)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if err := os.Truncate(journalPath, info.Size()-3); err != nil {
+		t.Fatalf("Truncate failed: %v", err)
+	}
+
+	entries, err := logger.ReadJournal(journalPath)
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Message != "first" {
+		t.Errorf("Expected only the first entry, got %+v", entries)
+	}
+}
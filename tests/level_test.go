@@ -0,0 +1,205 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestParseLevelOrDefault 测试级别名称不区分大小写，无法解析时返回默认级别
+func TestParseLevelOrDefault(t *testing.T) {
+	cases := map[string]logger.LogLevel{
+		"debug":   logger.DebugLevel,
+		"INFO":    logger.InfoLevel,
+		"Warn":    logger.WarnLevel,
+		"warning": logger.WarnLevel,
+		" error ": logger.ErrorLevel,
+		"fatal":   logger.FatalLevel,
+		"panic":   logger.PanicLevel,
+		"":        logger.ErrorLevel,
+		"verbose": logger.ErrorLevel,
+	}
+	for s, expected := range cases {
+		if got := logger.ParseLevelOrDefault(s, logger.ErrorLevel); got != expected {
+			t.Errorf("ParseLevelOrDefault(%q): expected %s, got %s", s, expected, got)
+		}
+	}
+
+	if _, err := logger.ParseLevel("verbose"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
+
+// TestLevelValue 测试LevelValue通过flag.Var解析命令行参数，未传入时保持默认值，无法解析时报错
+func TestLevelValue(t *testing.T) {
+	newFlagSet := func() (*flag.FlagSet, *logger.LevelValue) {
+		fs := flag.NewFlagSet("app", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		level := logger.LevelValue(logger.InfoLevel)
+		fs.Var(&level, "log-level", "log level")
+		return fs, &level
+	}
+
+	fs, level := newFlagSet()
+	if err := fs.Parse([]string{"-log-level=debug"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if level.Level() != logger.DebugLevel || fs.Lookup("log-level").Value.(flag.Getter).Get() != logger.DebugLevel {
+		t.Errorf("Expected debug level, got %s", level.Level())
+	}
+
+	fs, level = newFlagSet()
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if level.Level() != logger.InfoLevel || fs.Lookup("log-level").DefValue != "info" {
+		t.Errorf("Expected default info level, got %s (default %q)", level.Level(), fs.Lookup("log-level").DefValue)
+	}
+
+	fs, level = newFlagSet()
+	if err := fs.Parse([]string{"-log-level", "verbose"}); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+	if level.Level() != logger.InfoLevel {
+		t.Errorf("Expected level to be unchanged, got %s", level.Level())
+	}
+
+	parsed := logger.LevelFlag("test-log-level", logger.WarnLevel, "log level")
+	if *parsed != logger.WarnLevel {
+		t.Errorf("Expected default warn level, got %s", *parsed)
+	}
+	if err := flag.CommandLine.Set("test-log-level", "ERROR"); err != nil || *parsed != logger.ErrorLevel {
+		t.Errorf("Expected error level, got %s (%v)", *parsed, err)
+	}
+}
+
+// TestWithTemporaryLevel 测试闭包内输出调试日志，闭包返回后以及原日志实例不输出调试日志
+func TestWithTemporaryLevel(t *testing.T) {
+	newLogger := map[string]func(opts ...logger.Option) logger.Logger{
+		"console": func(opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger("app", opts...) },
+		"std":     func(opts ...logger.Option) logger.Logger { return logger.NewStdLogger("app", opts...) },
+		"zap":     func(opts ...logger.Option) logger.Logger { return logger.NewZapLogger("app", opts...) },
+		"logrus":  func(opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger("app", opts...) },
+	}
+
+	for name, create := range newLogger {
+		var buf bytes.Buffer
+		log := create(logger.WithWriter(&buf), logger.WithLevel(logger.InfoLevel))
+
+		logger.WithTemporaryLevel(log, logger.DebugLevel, func(l logger.Logger) {
+			l.Debug("inside closure")
+			log.Debug("original logger")
+			if !l.IsDebugEnabled() || log.IsDebugEnabled() {
+				t.Errorf("%s: expected only the derived logger to enable debug", name)
+			}
+		})
+		log.Debug("after closure")
+		log.Info("info message")
+
+		out := buf.String()
+		if !strings.Contains(out, "inside closure") || !strings.Contains(out, "info message") {
+			t.Errorf("%s: expected debug inside the closure, got %q", name, out)
+		}
+		if strings.Contains(out, "original logger") || strings.Contains(out, "after closure") {
+			t.Errorf("%s: unexpected debug outside the closure: %q", name, out)
+		}
+		if log.GetLevel() != logger.InfoLevel {
+			t.Errorf("%s: expected level to be unchanged, got %s", name, log.GetLevel())
+		}
+	}
+}
+
+// TestWithTemporaryLevelConcurrent 测试并发的临时级别互不影响
+func TestWithTemporaryLevelConcurrent(t *testing.T) {
+	log := logger.NewZapLogger("app", logger.WithWriter(io.Discard), logger.WithLevel(logger.WarnLevel))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			logger.WithTemporaryLevel(log, logger.DebugLevel, func(l logger.Logger) {
+				l.Debug("verbose")
+				if l.GetLevel() != logger.DebugLevel {
+					t.Errorf("Expected debug level, got %s", l.GetLevel())
+				}
+			})
+			if log.IsInfoEnabled() {
+				t.Error("Expected the shared logger to stay at warn level")
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// countingStringer 记录被格式化的次数
+type countingStringer struct {
+	calls *int
+}
+
+func (s countingStringer) String() string {
+	*s.calls++
+	return "value"
+}
+
+// eagerLogger 不检查级别就格式化消息的日志实例，模拟未实现LevelEnabler的自定义日志
+type eagerLogger struct {
+	logger.Logger
+}
+
+func (e eagerLogger) Debugf(format string, args ...interface{}) {
+	e.Logger.Debug(fmt.Sprintf(format, args...))
+}
+
+func (e eagerLogger) Infof(format string, args ...interface{}) {
+	e.Logger.Info(fmt.Sprintf(format, args...))
+}
+
+// TestLogfIfEnabled 测试全局格式化日志函数在级别未启用时不格式化消息，并考虑上下文中的级别覆盖
+func TestLogfIfEnabled(t *testing.T) {
+	original := logger.GetLogger()
+	defer logger.SetGlobalLogger(original)
+
+	rec := &recorder{}
+	log := logger.NewFuncLogger("global", logger.WarnLevel, rec.emit)
+	logger.SetGlobalLogger(eagerLogger{log})
+
+	formatted := 0
+	arg := countingStringer{calls: &formatted}
+	logger.Debugf("debug %s", arg)
+	logger.Infof("info %s", arg)
+	logger.LogfIfEnabled(logger.InfoLevel, "info %s", arg)
+	if formatted != 0 {
+		t.Errorf("Expected disabled levels to skip formatting, formatted %d times", formatted)
+	}
+
+	logger.Warnf("warn %s", arg)
+	logger.LogfIfEnabled(logger.ErrorLevel, "error %s", arg)
+	if formatted != 2 || len(rec.calls) != 2 {
+		t.Fatalf("Expected 2 formatted entries, got %d formatted and %d entries", formatted, len(rec.calls))
+	}
+	if rec.calls[0].level != logger.WarnLevel || rec.calls[0].msg != "warn value" {
+		t.Errorf("Unexpected entry: %v %q", rec.calls[0].level, rec.calls[0].msg)
+	}
+	if rec.calls[1].level != logger.ErrorLevel || rec.calls[1].msg != "error value" {
+		t.Errorf("Unexpected entry: %v %q", rec.calls[1].level, rec.calls[1].msg)
+	}
+
+	if !log.IsEnabled(logger.WarnLevel) || log.IsEnabled(logger.DebugLevel) {
+		t.Error("Expected IsEnabled to follow the logger level")
+	}
+
+	// 上下文中的级别覆盖同样适用于全局函数
+	logger.SetGlobalLogger(log.WithContext(logger.ContextWithLevel(context.Background(), logger.DebugLevel)))
+	logger.Debugf("debug %s", arg)
+	if len(rec.calls) != 3 || rec.calls[2].level != logger.DebugLevel {
+		t.Errorf("Expected context level override to enable Debugf, got %d entries", len(rec.calls))
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"io"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// benchConstructors 基准测试使用的各日志适配器，输出丢弃，只衡量门面和编码的开销
+var benchConstructors = []struct {
+	name string
+	new  func(opts ...logger.Option) logger.Logger
+}{
+	{"console", func(opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger("bench", opts...) }},
+	{"std", func(opts ...logger.Option) logger.Logger { return logger.NewStdLogger("bench", opts...) }},
+	{"zap", func(opts ...logger.Option) logger.Logger { return logger.NewZapLogger("bench", opts...) }},
+	{"logrus", func(opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger("bench", opts...) }},
+}
+
+// runAdapterBenchmarks 对每个适配器运行基准测试
+func runAdapterBenchmarks(b *testing.B, level logger.LogLevel, fn func(b *testing.B, log logger.Logger)) {
+	for _, c := range benchConstructors {
+		b.Run(c.name, func(b *testing.B) {
+			log := c.new(logger.WithWriter(io.Discard), logger.WithFormat("json"), logger.WithLevel(level))
+			b.ReportAllocs()
+			b.ResetTimer()
+			fn(b, log)
+		})
+	}
+}
+
+// BenchmarkInfo 输出带字段的信息级日志
+func BenchmarkInfo(b *testing.B) {
+	runAdapterBenchmarks(b, logger.InfoLevel, func(b *testing.B, log logger.Logger) {
+		for i := 0; i < b.N; i++ {
+			log.Info("request handled", logger.Field{Key: "status", Value: 200}, logger.Field{Key: "path", Value: "/orders"})
+		}
+	})
+}
+
+// BenchmarkInfof 输出格式化的信息级日志
+func BenchmarkInfof(b *testing.B) {
+	runAdapterBenchmarks(b, logger.InfoLevel, func(b *testing.B, log logger.Logger) {
+		for i := 0; i < b.N; i++ {
+			log.Infof("request handled in %d ms", i)
+		}
+	})
+}
+
+// BenchmarkWithFieldsInfo 每次派生带字段的日志实例后输出
+func BenchmarkWithFieldsInfo(b *testing.B) {
+	runAdapterBenchmarks(b, logger.InfoLevel, func(b *testing.B, log logger.Logger) {
+		for i := 0; i < b.N; i++ {
+			log.WithFields(logger.Field{Key: "request_id", Value: "req-1"}).Info("request handled", logger.Field{Key: "status", Value: 200})
+		}
+	})
+}
+
+// BenchmarkDisabledLevel 级别未启用的日志应当在分配任何内存之前返回
+func BenchmarkDisabledLevel(b *testing.B) {
+	runAdapterBenchmarks(b, logger.WarnLevel, func(b *testing.B, log logger.Logger) {
+		for i := 0; i < b.N; i++ {
+			log.Debug("verbose", logger.Field{Key: "status", Value: 200})
+		}
+	})
+}
+
+// BenchmarkGlobalDisabledf 全局格式化日志函数在级别未启用时应当在格式化消息之前返回
+func BenchmarkGlobalDisabledf(b *testing.B) {
+	original := logger.GetLogger()
+	defer logger.SetGlobalLogger(original)
+
+	runAdapterBenchmarks(b, logger.WarnLevel, func(b *testing.B, log logger.Logger) {
+		logger.SetGlobalLogger(log)
+		for i := 0; i < b.N; i++ {
+			logger.Debugf("verbose %s %d", "request", 200)
+		}
+	})
+}
+
+// TestAllocations 测试输出一条日志的内存分配次数不超过上限，防止池化的缓冲区失效后分配次数回退。
+// 未池化时控制台和标准库日志输出一条带两个字段的JSON日志需要41次分配，zap需要5次
+func TestAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts are not stable under the race detector")
+	}
+
+	limits := map[string]struct{ info, disabled float64 }{
+		"console": {info: 12, disabled: 1},
+		"std":     {info: 12, disabled: 1},
+		"zap":     {info: 4, disabled: 1},
+		"logrus":  {info: 40, disabled: 1},
+	}
+
+	original := logger.GetLogger()
+	defer logger.SetGlobalLogger(original)
+
+	for _, c := range benchConstructors {
+		limit := limits[c.name]
+		log := c.new(logger.WithWriter(io.Discard), logger.WithFormat("json"), logger.WithLevel(logger.InfoLevel))
+
+		info := testing.AllocsPerRun(200, func() {
+			log.Info("request handled", logger.Field{Key: "status", Value: 200}, logger.Field{Key: "path", Value: "/orders"})
+		})
+		if info > limit.info {
+			t.Errorf("%s: expected at most %v allocations per Info, got %v", c.name, limit.info, info)
+		}
+
+		disabled := testing.AllocsPerRun(200, func() {
+			log.Debug("verbose", logger.Field{Key: "status", Value: 200})
+		})
+		if disabled > limit.disabled {
+			t.Errorf("%s: expected at most %v allocations for a disabled level, got %v", c.name, limit.disabled, disabled)
+		}
+
+		// 唯一的分配是传给接口方法的可变参数切片，消息不会被格式化
+		logger.SetGlobalLogger(log)
+		disabledf := testing.AllocsPerRun(200, func() {
+			logger.Debugf("verbose %s %d", "request", 200)
+		})
+		if disabledf > limit.disabled {
+			t.Errorf("%s: expected at most %v allocations for a disabled global Debugf, got %v", c.name, limit.disabled, disabledf)
+		}
+	}
+}
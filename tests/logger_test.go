@@ -3,7 +3,10 @@ package tests
 import (
 	"github.com/LandcLi/LandcLogFace"
 	"context"
+	"encoding/json"
 	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -401,3 +404,148 @@ func TestIsEnabledMethods(t *testing.T) {
 		t.Error("Error level should be enabled")
 	}
 }
+
+// TestGlobalIsEnabled 测试全局级别检查函数反映全局日志的级别
+func TestGlobalIsEnabled(t *testing.T) {
+	original := LandcLogFace.GetLogger()
+	defer LandcLogFace.SetGlobalLogger(original)
+
+	logger := LandcLogFace.GetLoggerWithProvider("test-global-level", "console")
+	logger.SetLevel(LandcLogFace.WarnLevel)
+	LandcLogFace.SetGlobalLogger(logger)
+
+	if LandcLogFace.IsDebugEnabled() {
+		t.Error("Debug level should not be enabled")
+	}
+	if LandcLogFace.IsInfoEnabled() {
+		t.Error("Info level should not be enabled")
+	}
+	if !LandcLogFace.IsWarnEnabled() {
+		t.Error("Warn level should be enabled")
+	}
+	if !LandcLogFace.IsErrorEnabled() || !LandcLogFace.IsFatalEnabled() || !LandcLogFace.IsPanicEnabled() {
+		t.Error("Error, Fatal and Panic levels should be enabled")
+	}
+
+	logger.SetLevel(LandcLogFace.DebugLevel)
+	if !LandcLogFace.IsDebugEnabled() {
+		t.Error("Debug level should be enabled after SetLevel")
+	}
+}
+
+// TestDescribe 测试Describe返回的信息与创建时的配置一致
+func TestDescribe(t *testing.T) {
+	providers := []string{"console", "zap", "logrus", "std"}
+
+	for _, provider := range providers {
+		logger := LandcLogFace.GetLoggerWithConfig("test-describe-"+provider, map[string]interface{}{
+			"provider":   provider,
+			"level":      LandcLogFace.WarnLevel,
+			"format":     "json",
+			"outputPath": "stdout",
+		})
+
+		info := logger.Describe()
+		expected := LandcLogFace.LoggerInfo{
+			Name:       "test-describe-" + provider,
+			Provider:   provider,
+			Level:      LandcLogFace.WarnLevel,
+			Format:     "json",
+			OutputPath: "stdout",
+		}
+		if info != expected {
+			t.Errorf("%s: expected %+v, got %+v", provider, expected, info)
+		}
+
+		logger.SetLevel(LandcLogFace.ErrorLevel)
+		if logger.Describe().Level != LandcLogFace.ErrorLevel {
+			t.Errorf("%s: Describe should report the current level", provider)
+		}
+	}
+}
+
+// TestAutoProvider 测试auto提供者在开发环境输出控制台日志，在生产环境输出JSON
+func TestAutoProvider(t *testing.T) {
+	dir := t.TempDir()
+
+	devPath := filepath.Join(dir, "dev.log")
+	dev := LandcLogFace.GetLoggerWithConfig("test-auto-dev", map[string]interface{}{
+		"provider":    "auto",
+		"environment": "dev",
+		"outputPath":  devPath,
+	})
+	dev.Info("dev message")
+	dev.Sync()
+
+	if dev.Describe().Provider != "console" {
+		t.Errorf("Expected console backend in dev, got %s", dev.Describe().Provider)
+	}
+	devLines := readLines(t, devPath)
+	if len(devLines) != 1 || !strings.Contains(devLines[0], "[INFO]") || strings.Contains(devLines[0], "\x1b[") || !strings.Contains(devLines[0], "dev message") {
+		t.Errorf("Expected plain console output in a log file, got %q", devLines)
+	}
+
+	t.Setenv("LANDCLOGFACE_ENV", "prod")
+	prodPath := filepath.Join(dir, "prod.log")
+	prod := LandcLogFace.GetLoggerWithConfig("test-auto-prod", map[string]interface{}{
+		"provider":   "auto",
+		"outputPath": prodPath,
+	})
+	prod.Info("prod message")
+	prod.Sync()
+
+	if prod.Describe().Provider != "zap" {
+		t.Errorf("Expected zap backend in prod, got %s", prod.Describe().Provider)
+	}
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(readLines(t, prodPath)[0]), &data); err != nil {
+		t.Fatalf("Expected JSON output in prod: %v", err)
+	}
+	if data["msg"] != "prod message" {
+		t.Errorf("Unexpected prod output %v", data)
+	}
+}
+
+// TestClone 测试Clone保留级别、格式和字段并使用新的名称
+func TestClone(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, provider := range []string{"console", "std", "zap", "logrus"} {
+		path := filepath.Join(dir, provider+".log")
+		original := LandcLogFace.GetLoggerWithConfig("api", map[string]interface{}{
+			"provider":   provider,
+			"level":      LandcLogFace.WarnLevel,
+			"format":     "json",
+			"outputPath": path,
+		}).WithField("service", "shop")
+
+		clone := original.(LandcLogFace.Cloner).Clone("worker")
+		clone.Info("suppressed")
+		clone.Warn("cloned")
+		clone.Sync()
+
+		info := clone.Describe()
+		if info.Name != "worker" || info.Level != LandcLogFace.WarnLevel || info.Format != "json" {
+			t.Errorf("%s: unexpected clone info %+v", provider, info)
+		}
+		if original.Describe().Name != "api" {
+			t.Errorf("%s: original name changed to %s", provider, original.Describe().Name)
+		}
+
+		lines := readLines(t, path)
+		if len(lines) != 1 {
+			t.Fatalf("%s: expected 1 line, got %d", provider, len(lines))
+		}
+		var data map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[0]), &data); err != nil {
+			t.Fatalf("%s: invalid JSON %q: %v", provider, lines[0], err)
+		}
+		if data["service"] != "shop" {
+			t.Errorf("%s: expected cloned fields, got %v", provider, data)
+		}
+		// logrus不输出日志名称
+		if provider != "logrus" && data["logger"] != "worker" {
+			t.Errorf("%s: expected new name in output, got %v", provider, data["logger"])
+		}
+	}
+}
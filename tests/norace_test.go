@@ -0,0 +1,6 @@
+//go:build !race
+
+package tests
+
+// raceEnabled 是否启用了竞态检测，竞态检测下sync.Pool会随机丢弃缓冲区，分配次数不稳定
+const raceEnabled = false
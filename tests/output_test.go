@@ -0,0 +1,585 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace"
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"go.uber.org/zap/zaptest"
+)
+
+// TestBufferedWriterSync 测试缓冲输出在Sync时写入文件
+func TestBufferedWriterSync(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "buffered.log")
+	log := logger.NewConsoleLogger("buffered",
+		LandcLogFace.WithOutputPath(path),
+		LandcLogFace.WithBufferedWriterSize(64*1024),
+	)
+
+	log.Info("buffered line")
+
+	// Sync之前数据仍在缓冲区中
+	if data, _ := os.ReadFile(path); len(data) != 0 {
+		t.Fatalf("Expected no data before Sync, got %q", string(data))
+	}
+
+	if err := log.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	lines := readLines(t, path)
+	if len(lines) != 1 {
+		t.Fatalf("Expected 1 line after Sync, got %d", len(lines))
+	}
+}
+
+// readGzipLines 解压gzip文件并按行读取
+func readGzipLines(t *testing.T, path string) []string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		t.Fatalf("Invalid gzip file: %v", err)
+	}
+	defer reader.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("Gunzip failed: %v", err)
+	}
+	return lines
+}
+
+// TestCompressedOutput 测试压缩输出在关闭后可以完整解压
+func TestCompressedOutput(t *testing.T) {
+	dir := t.TempDir()
+	type closer interface {
+		logger.Logger
+		Close() error
+	}
+	loggers := map[string]closer{
+		"console": logger.NewConsoleLogger("app", logger.WithOutputPath(filepath.Join(dir, "console.log")), logger.WithCompressedOutput(true)),
+		"std":     logger.NewStdLogger("app", logger.WithOutputPath(filepath.Join(dir, "std.log")), logger.WithCompressedOutput(true)),
+		"zap":     logger.NewZapLogger("app", logger.WithOutputPath(filepath.Join(dir, "zap.log")), logger.WithCompressedOutput(true)),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithOutputPath(filepath.Join(dir, "logrus.log")), logger.WithCompressedOutput(true), logger.WithBufferedWriterSize(4096)),
+	}
+
+	for name, log := range loggers {
+		for i := 0; i < 100; i++ {
+			log.Info(fmt.Sprintf("line %d", i))
+		}
+		if err := log.Close(); err != nil {
+			t.Fatalf("%s: Close failed: %v", name, err)
+		}
+
+		lines := readGzipLines(t, filepath.Join(dir, name+".log.gz"))
+		if len(lines) != 100 {
+			t.Fatalf("%s: expected 100 lines, got %d", name, len(lines))
+		}
+		for i, line := range lines {
+			if !strings.Contains(line, fmt.Sprintf("line %d", i)) {
+				t.Errorf("%s: line %d mismatch: %q", name, i, line)
+			}
+		}
+	}
+}
+
+// TestOutputPaths 测试同一条日志同时写入文件和自定义输出
+func TestOutputPaths(t *testing.T) {
+	dir := t.TempDir()
+	buffers := map[string]*bytes.Buffer{}
+	opts := func(name string) []logger.Option {
+		buffers[name] = &bytes.Buffer{}
+		return []logger.Option{
+			logger.WithOutputPaths(filepath.Join(dir, name+".log")),
+			logger.WithWriter(buffers[name]),
+		}
+	}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", opts("console")...),
+		"std":     logger.NewStdLogger("app", opts("std")...),
+		"zap":     logger.NewZapLogger("app", opts("zap")...),
+		"logrus":  logger.NewLogrusLogger("app", opts("logrus")...),
+	}
+
+	for name, log := range loggers {
+		log.Info("fan out")
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 1 || !strings.Contains(lines[0], "fan out") {
+			t.Errorf("%s: expected line in file, got %q", name, lines)
+		}
+		if buf := buffers[name].String(); strings.TrimSpace(buf) != lines[0] {
+			t.Errorf("%s: expected same line in buffer, got %q", name, buf)
+		}
+	}
+}
+
+// TestSetOutput 测试运行时替换输出目标后日志写入新的输出
+func TestSetOutput(t *testing.T) {
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app"),
+		"std":     logger.NewStdLogger("app"),
+		"zap":     logger.NewZapLogger("app"),
+		"logrus":  logger.NewLogrusLogger("app"),
+	}
+
+	for name, log := range loggers {
+		var first, second bytes.Buffer
+		derived := log.WithField("k", "v")
+
+		log.(logger.OutputSettable).SetOutput(&first)
+		log.Info("before switch")
+		log.(logger.OutputSettable).SetOutput(&second)
+		log.Info("after switch")
+		derived.Info("derived after switch")
+
+		if !strings.Contains(first.String(), "before switch") || strings.Contains(first.String(), "after switch") {
+			t.Errorf("%s: unexpected first output %q", name, first.String())
+		}
+		if !strings.Contains(second.String(), "after switch") || !strings.Contains(second.String(), "derived after switch") {
+			t.Errorf("%s: unexpected second output %q", name, second.String())
+		}
+	}
+}
+
+// flakyWriter 包含"fail"的日志写入失败的输出
+type flakyWriter struct{}
+
+func (flakyWriter) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte("fail")) {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+// TestStats 测试写入成功和失败的日志条数统计
+func TestStats(t *testing.T) {
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithWriter(flakyWriter{})),
+		"std":     logger.NewStdLogger("app", logger.WithWriter(flakyWriter{})),
+		"zap":     logger.NewZapLogger("app", logger.WithWriter(flakyWriter{})),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithWriter(flakyWriter{})),
+	}
+
+	for name, log := range loggers {
+		log.Info("ok 1")
+		log.Info("fail 1")
+		log.WithField("k", "v").Warn("ok 2")
+		log.Error("fail 2")
+		log.Error("fail 3")
+
+		stats := log.(logger.StatsReporter).Stats()
+		if stats.Emitted != 2 || stats.Dropped != 3 {
+			t.Errorf("%s: expected 2 emitted and 3 dropped, got %+v", name, stats)
+		}
+	}
+}
+
+// benchmarkFileOutput 向文件输出日志的基准测试
+func benchmarkFileOutput(b *testing.B, opts ...logger.Option) {
+	path := filepath.Join(b.TempDir(), "bench.log")
+	log := logger.NewStdLogger("bench", append([]logger.Option{logger.WithOutputPath(path)}, opts...)...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Info("benchmark line", logger.Field{Key: "i", Value: i})
+	}
+	b.StopTimer()
+	log.Sync()
+}
+
+// BenchmarkUnbufferedOutput 无缓冲输出
+func BenchmarkUnbufferedOutput(b *testing.B) {
+	benchmarkFileOutput(b)
+}
+
+// BenchmarkBufferedOutput 带缓冲输出
+func BenchmarkBufferedOutput(b *testing.B) {
+	benchmarkFileOutput(b, logger.WithBufferedWriterSize(256*1024))
+}
+
+// TestInvalidOutputPathFallback 测试无法打开的输出路径回退到标准输出并保留日志级别
+func TestInvalidOutputPathFallback(t *testing.T) {
+	// 以普通文件作为目录，使日志文件无法创建
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var warnings bytes.Buffer
+	logger.SetErrorOutput(&warnings)
+	defer logger.SetErrorOutput(os.Stderr)
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	log := logger.NewZapLogger("app",
+		logger.WithLevel(logger.DebugLevel),
+		logger.WithOutputPath(filepath.Join(blocker, "app.log")),
+	)
+	// 同一个错误路径再次创建日志实例时不重复警告
+	logger.NewStdLogger("other", logger.WithOutputPath(filepath.Join(blocker, "app.log")))
+	os.Stdout = stdout
+
+	log.Debug("fallback debug")
+	writer.Close()
+	output, _ := io.ReadAll(reader)
+
+	if count := strings.Count(warnings.String(), "falling back to stdout"); count != 1 {
+		t.Errorf("Expected exactly one fallback warning, got %q", warnings.String())
+	}
+	if !strings.Contains(string(output), `"level":"debug"`) || !strings.Contains(string(output), "fallback debug") {
+		t.Errorf("Expected debug line on stdout, got %q", string(output))
+	}
+}
+
+// syncErrorWriter Sync时返回指定错误的输出
+type syncErrorWriter struct {
+	err error
+}
+
+func (w syncErrorWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w syncErrorWriter) Sync() error {
+	return w.err
+}
+
+// TestSyncBenignErrors 测试各适配器的Sync忽略终端输出不支持fsync的错误，其他I/O错误正常返回
+func TestSyncBenignErrors(t *testing.T) {
+	constructors := map[string]func(name string, opts ...logger.Option) logger.Logger{
+		"console": func(name string, opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger(name, opts...) },
+		"std":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewStdLogger(name, opts...) },
+		"zap":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewZapLogger(name, opts...) },
+		"logrus":  func(name string, opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger(name, opts...) },
+	}
+
+	benign := []error{
+		&os.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.ENOTTY},
+		&os.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.EINVAL},
+	}
+	real := &os.PathError{Op: "sync", Path: "/var/log/app.log", Err: syscall.EIO}
+
+	for name, create := range constructors {
+		for _, err := range benign {
+			log := create("app", logger.WithWriter(syncErrorWriter{err: err}))
+			log.Info("hello")
+			if syncErr := log.Sync(); syncErr != nil {
+				t.Errorf("%s: expected benign error %v to be ignored, got %v", name, err, syncErr)
+			}
+		}
+
+		log := create("app", logger.WithWriter(syncErrorWriter{err: real}))
+		log.Info("hello")
+		if err := log.Sync(); !errors.Is(err, syscall.EIO) {
+			t.Errorf("%s: expected real I/O error to be returned, got %v", name, err)
+		}
+
+		mixed := create("app", logger.WithOutputPaths("stdout"), logger.WithWriter(syncErrorWriter{err: real}))
+		if err := mixed.Sync(); !errors.Is(err, syscall.EIO) || errors.Is(err, syscall.ENOTTY) || errors.Is(err, syscall.EINVAL) {
+			t.Errorf("%s: expected only the real error from multiple outputs, got %v", name, err)
+		}
+	}
+}
+
+// TestZapWriteSyncer 测试zap日志直接写入提供的WriteSyncer，并忽略OutputPath
+func TestZapWriteSyncer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ignored.log")
+	buf := &zaptest.Buffer{}
+	log := logger.NewZapLogger("app", logger.WithOutputPath(path), logger.WithZapWriteSyncer(buf))
+
+	log.Info("first", logger.Field{Key: "k", Value: "v"})
+	log.Warn("second")
+	if err := log.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	lines := buf.Lines()
+	if len(lines) != 2 || !strings.Contains(lines[0], `"msg":"first"`) || !strings.Contains(lines[0], `"k":"v"`) {
+		t.Fatalf("Unexpected records %q", lines)
+	}
+	if !buf.Called() {
+		t.Error("Expected Sync to reach the write syncer")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected OutputPath to be ignored, got %v", err)
+	}
+}
+
+// TestZapMultiWriteSyncer 测试zap日志同时写入文件和WriteSyncer，Sync到达每个输出目标并返回其错误
+func TestZapMultiWriteSyncer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	buf := &zaptest.Buffer{}
+	log := logger.NewZapLogger("app", logger.WithOutputPaths(path), logger.WithWriter(buf))
+
+	log.Info("first", logger.Field{Key: "k", Value: "v"})
+	log.WithField("attempt", 2).Warn("second")
+	if err := log.Sync(); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	lines := buf.Lines()
+	if len(lines) != 2 || !strings.Contains(lines[0], `"msg":"first"`) || !strings.Contains(lines[1], `"attempt":2`) {
+		t.Fatalf("Unexpected buffer records %q", lines)
+	}
+	if !buf.Called() {
+		t.Error("Expected Sync to reach the write syncer")
+	}
+	fileLines := readLines(t, path)
+	if len(fileLines) != 2 || fileLines[0] != lines[0] || fileLines[1] != lines[1] {
+		t.Errorf("Expected the file to match the buffer, got %q", fileLines)
+	}
+
+	buf.SetError(errors.New("remote unavailable"))
+	if err := log.Sync(); err == nil || !strings.Contains(err.Error(), "remote unavailable") {
+		t.Errorf("Expected the syncer error, got %v", err)
+	}
+	if stats := log.Stats(); stats.Emitted != 2 || stats.Dropped != 0 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	log.Close()
+}
+
+// failingWriter 前failures次写入失败的输出
+type failingWriter struct {
+	failures int
+	attempts int
+	buf      bytes.Buffer
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	w.attempts++
+	if w.attempts <= w.failures {
+		return 0, errors.New("connection refused")
+	}
+	return w.buf.Write(p)
+}
+
+// TestOutputCircuitBreaker 测试连续写入失败后熔断并写入备用输出，冷却结束后探测恢复
+func TestOutputCircuitBreaker(t *testing.T) {
+	clock := &manualClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	primary := &failingWriter{failures: 4}
+	var fallback bytes.Buffer
+	log := logger.NewStdLogger("app",
+		logger.WithWriter(primary),
+		logger.WithClock(clock),
+		logger.WithOutputCircuitBreaker(3, time.Minute),
+		logger.WithCircuitBreakerFallback(&fallback),
+	)
+
+	// 连续3次失败后熔断，失败的日志转写到备用输出
+	for i := 1; i <= 5; i++ {
+		log.Info(fmt.Sprintf("line %d", i))
+	}
+	if primary.attempts != 3 {
+		t.Errorf("Expected 3 attempts before the breaker opened, got %d", primary.attempts)
+	}
+	if got := strings.Count(fallback.String(), "line"); got != 5 {
+		t.Errorf("Expected 5 lines in fallback, got %d: %q", got, fallback.String())
+	}
+	if stats := log.Stats(); stats.Dropped != 0 {
+		t.Errorf("Expected no dropped lines, got %+v", stats)
+	}
+
+	// 冷却结束后探测失败，重新熔断
+	clock.t = clock.t.Add(time.Minute)
+	log.Info("probe 1")
+	log.Info("line 6")
+	if primary.attempts != 4 {
+		t.Errorf("Expected a single failed probe, got %d attempts", primary.attempts)
+	}
+
+	// 再次冷却结束后探测成功，恢复写入主输出
+	clock.t = clock.t.Add(time.Minute)
+	log.Info("probe 2")
+	log.Info("line 7")
+	if primary.attempts != 6 {
+		t.Errorf("Expected writes to resume on the primary, got %d attempts", primary.attempts)
+	}
+	if !strings.Contains(primary.buf.String(), "probe 2") || !strings.Contains(primary.buf.String(), "line 7") {
+		t.Errorf("Unexpected primary output %q", primary.buf.String())
+	}
+	if strings.Contains(fallback.String(), "probe 2") || !strings.Contains(fallback.String(), "line 6") {
+		t.Errorf("Unexpected fallback output %q", fallback.String())
+	}
+}
+
+// chunkedWriter 非并发安全的输出，每次写入拆成多个小块并在块之间让出调度，并发写入时不加锁会使日志行互相穿插
+type chunkedWriter struct {
+	buf bytes.Buffer
+}
+
+func (w *chunkedWriter) Write(p []byte) (int, error) {
+	for i := 0; i < len(p); i += 8 {
+		end := i + 8
+		if end > len(p) {
+			end = len(p)
+		}
+		w.buf.Write(p[i:end])
+		runtime.Gosched()
+	}
+	return len(p), nil
+}
+
+// TestSynchronizedWriter 测试自定义输出和SetOutput替换的输出被串行化，多个goroutine并发输出时每行日志完整
+func TestSynchronizedWriter(t *testing.T) {
+	const goroutines, perGoroutine = 16, 50
+	constructors := map[string]func(name string, opts ...logger.Option) logger.Logger{
+		"console": func(name string, opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger(name, opts...) },
+		"std":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewStdLogger(name, opts...) },
+		"zap":     func(name string, opts ...logger.Option) logger.Logger { return logger.NewZapLogger(name, opts...) },
+		"logrus":  func(name string, opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger(name, opts...) },
+	}
+
+	for name, create := range constructors {
+		for _, setOutput := range []bool{false, true} {
+			out := &chunkedWriter{}
+			var log logger.Logger
+			if setOutput {
+				log = create("app", logger.WithFormat("json"), logger.WithWriter(io.Discard))
+				log.(logger.OutputSettable).SetOutput(out)
+			} else {
+				log = create("app", logger.WithFormat("json"), logger.WithWriter(out))
+			}
+
+			var wg sync.WaitGroup
+			for g := 0; g < goroutines; g++ {
+				wg.Add(1)
+				go func(g int) {
+					defer wg.Done()
+					for i := 0; i < perGoroutine; i++ {
+						log.Info("concurrent write", logger.Field{Key: "goroutine", Value: g}, logger.Field{Key: "i", Value: i})
+					}
+				}(g)
+			}
+			wg.Wait()
+			log.Sync()
+
+			lines := strings.Split(strings.TrimSpace(out.buf.String()), "\n")
+			if len(lines) != goroutines*perGoroutine {
+				t.Fatalf("%s (SetOutput=%v): expected %d lines, got %d", name, setOutput, goroutines*perGoroutine, len(lines))
+			}
+			for _, line := range lines {
+				if data := decodeJSONLine(t, line); data["msg"] != "concurrent write" {
+					t.Fatalf("%s (SetOutput=%v): garbled line %q", name, setOutput, line)
+				}
+			}
+		}
+	}
+
+	if w := logger.NewSynchronizedWriter(io.Discard); logger.NewSynchronizedWriter(w) != w {
+		t.Error("Expected an already synchronized writer not to be wrapped again")
+	}
+}
+
+// TestCloneIndependentState 测试复制的实例拥有独立的序号、统计和限流状态，输出目标与原实例共享
+func TestCloneIndependentState(t *testing.T) {
+	constructors := map[string]func(opts ...logger.Option) logger.Logger{
+		"console": func(opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger("api", opts...) },
+		"std":     func(opts ...logger.Option) logger.Logger { return logger.NewStdLogger("api", opts...) },
+		"zap":     func(opts ...logger.Option) logger.Logger { return logger.NewZapLogger("api", opts...) },
+		"logrus":  func(opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger("api", opts...) },
+	}
+
+	for name, create := range constructors {
+		var buf bytes.Buffer
+		original := create(logger.WithFormat("json"), logger.WithWriter(&buf), logger.WithSequence(true), logger.WithFieldRateLimit("code", 2))
+		for i := 0; i < 3; i++ {
+			original.Warn("original", logger.Field{Key: "code", Value: "TIMEOUT"})
+		}
+
+		clone := original.(logger.Cloner).Clone("worker")
+		clone.Warn("clone", logger.Field{Key: "code", Value: "TIMEOUT"})
+
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("%s: expected 2 original lines and 1 clone line in the shared output, got %q", name, lines)
+		}
+		data := decodeJSONLine(t, lines[2])
+		if data["seq"] != float64(1) {
+			t.Errorf("%s: expected the clone to start its own sequence, got seq=%v", name, data["seq"])
+		}
+
+		originalStats := original.(logger.StatsReporter).Stats()
+		cloneStats := clone.(logger.StatsReporter).Stats()
+		if originalStats.Emitted != 2 || originalStats.Suppressed != 1 {
+			t.Errorf("%s: unexpected original stats %+v", name, originalStats)
+		}
+		if cloneStats.Emitted != 1 || cloneStats.Suppressed != 0 {
+			t.Errorf("%s: unexpected clone stats %+v", name, cloneStats)
+		}
+	}
+}
+
+// TestCloneSharesOutput 测试复制的实例与原实例共享输出目标，关闭复制的实例不会关闭原实例的输出文件，
+// 原实例替换输出目标后复制的实例同样写入新的输出目标
+func TestCloneSharesOutput(t *testing.T) {
+	constructors := map[string]func(opts ...logger.Option) logger.Logger{
+		"console": func(opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger("api", opts...) },
+		"std":     func(opts ...logger.Option) logger.Logger { return logger.NewStdLogger("api", opts...) },
+		"zap":     func(opts ...logger.Option) logger.Logger { return logger.NewZapLogger("api", opts...) },
+		"logrus":  func(opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger("api", opts...) },
+	}
+
+	dir := t.TempDir()
+	for name, create := range constructors {
+		path := filepath.Join(dir, name+".log")
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			t.Fatalf("open %s failed: %v", path, err)
+		}
+		original := create(logger.WithFormat("json"), logger.WithWriter(file))
+		clone := original.(logger.Cloner).Clone("worker")
+
+		clone.Info("from clone")
+		if err := clone.(io.Closer).Close(); err != nil {
+			t.Errorf("%s: closing the clone failed: %v", name, err)
+		}
+		original.Info("from original")
+		if stats := original.(logger.StatsReporter).Stats(); stats.Dropped != 0 {
+			t.Errorf("%s: expected the original to keep writing after the clone was closed, got %+v", name, stats)
+		}
+		if lines := readLines(t, path); len(lines) != 2 || !strings.Contains(lines[1], "from original") {
+			t.Errorf("%s: expected both records in the file, got %q", name, lines)
+		}
+
+		var buf bytes.Buffer
+		original.(logger.OutputSettable).SetOutput(&buf)
+		clone.Info("after SetOutput")
+		if !strings.Contains(buf.String(), "after SetOutput") {
+			t.Errorf("%s: expected the clone to follow the original's SetOutput, got %q", name, buf.String())
+		}
+
+		original.(io.Closer).Close()
+		file.Close()
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestEntryJSONRoundTrip 测试日志记录编码为JSON后可以完整读回
+func TestEntryJSONRoundTrip(t *testing.T) {
+	entry := logger.Entry{
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC),
+		Level:   logger.WarnLevel,
+		Name:    "worker",
+		Message: "slow job",
+		Fields: []logger.Field{
+			{Key: "job_id", Value: 42},
+			{Key: "ratio", Value: 0.5},
+			{Key: "ok", Value: true},
+			{Key: "error", Value: errors.New("timeout")},
+			logger.Group("http", logger.Field{Key: "status", Value: 503}),
+		},
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var decoded logger.Entry
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	expected := []logger.Field{
+		{Key: "job_id", Value: int64(42)},
+		{Key: "ratio", Value: 0.5},
+		{Key: "ok", Value: true},
+		{Key: "error", Value: "timeout"},
+		{Key: "http.status", Value: int64(503)},
+	}
+	if !decoded.Time.Equal(entry.Time) || decoded.Level != entry.Level || decoded.Name != entry.Name || decoded.Message != entry.Message {
+		t.Errorf("Unexpected entry %+v", decoded)
+	}
+	if !reflect.DeepEqual(decoded.Fields, expected) {
+		t.Errorf("Expected fields %v, got %v", expected, decoded.Fields)
+	}
+}
+
+// TestPipeRoundTrip 测试工作进程的日志经过管道后由中心日志实例重新输出
+func TestPipeRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "central.log")
+	central := logger.NewConsoleLogger("central", logger.WithFormat("json"), logger.WithOutputPath(path))
+
+	pr, pw := io.Pipe()
+	worker := logger.NewPipeWriter(pw).Logger("worker", logger.InfoLevel, logger.WithConstFields(logger.Field{Key: "pid", Value: 7}))
+	go func() {
+		worker.Info("job started", logger.Field{Key: "job_id", Value: 42})
+		worker.WithField("attempt", 2).Warn("retrying")
+		worker.Debug("filtered by level")
+		worker.Errorf("job %d failed", 42)
+		pw.Write([]byte("not json\n"))
+		pw.Close()
+	}()
+
+	if err := logger.NewPipeReader(pr, central).Run(); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	central.Sync()
+
+	lines := readLines(t, path)
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %q", lines)
+	}
+
+	first := decodeJSONLine(t, lines[0])
+	if first["level"] != "INFO" || first["msg"] != "job started" || first["job_id"] != float64(42) ||
+		first["pid"] != float64(7) || first["source"] != "worker" || first["logger"] != "central" {
+		t.Errorf("Unexpected first record: %v", first)
+	}
+	second := decodeJSONLine(t, lines[1])
+	if second["level"] != "WARN" || second["attempt"] != float64(2) {
+		t.Errorf("Unexpected second record: %v", second)
+	}
+	third := decodeJSONLine(t, lines[2])
+	if third["level"] != "ERROR" || third["msg"] != "job 42 failed" {
+		t.Errorf("Unexpected third record: %v", third)
+	}
+}
@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// emitDebug 输出n条调试日志并返回统计
+func emitDebug(log logger.Logger, n int) logger.LoggerStats {
+	for i := 0; i < n; i++ {
+		log.Debug("trace", logger.Field{Key: "i", Value: i})
+	}
+	return log.(logger.StatsReporter).Stats()
+}
+
+// TestProbabilisticLevel 测试按概率采样调试日志
+func TestProbabilisticLevel(t *testing.T) {
+	newLogger := func(probability float64) logger.Logger {
+		return logger.NewZapLogger("app",
+			logger.WithLevel(logger.DebugLevel),
+			logger.WithWriter(io.Discard),
+			logger.WithProbabilisticLevel(logger.DebugLevel, probability),
+		)
+	}
+
+	if stats := emitDebug(newLogger(0), 100); stats.Emitted != 0 || stats.Sampled != 100 {
+		t.Errorf("probability 0: expected none emitted, got %+v", stats)
+	}
+	if stats := emitDebug(newLogger(1), 100); stats.Emitted != 100 || stats.Sampled != 0 {
+		t.Errorf("probability 1: expected all emitted, got %+v", stats)
+	}
+
+	const n = 10000
+	stats := emitDebug(newLogger(0.5), n)
+	if stats.Emitted < n*45/100 || stats.Emitted > n*55/100 {
+		t.Errorf("probability 0.5: expected about %d emitted, got %+v", n/2, stats)
+	}
+	if stats.Emitted+stats.Sampled != n {
+		t.Errorf("probability 0.5: emitted and sampled should add up to %d, got %+v", n, stats)
+	}
+}
+
+// TestProbabilisticLevelAlwaysLog 测试达到WithAlwaysLogAbove阈值的日志不被采样丢弃
+func TestProbabilisticLevelAlwaysLog(t *testing.T) {
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", logger.WithWriter(io.Discard), logger.WithProbabilisticLevel(logger.ErrorLevel, 0)),
+		"std":     logger.NewStdLogger("app", logger.WithWriter(io.Discard), logger.WithProbabilisticLevel(logger.ErrorLevel, 0)),
+		"zap":     logger.NewZapLogger("app", logger.WithWriter(io.Discard), logger.WithProbabilisticLevel(logger.ErrorLevel, 0)),
+		"logrus":  logger.NewLogrusLogger("app", logger.WithWriter(io.Discard), logger.WithProbabilisticLevel(logger.ErrorLevel, 0)),
+	}
+
+	for name, log := range loggers {
+		for i := 0; i < 10; i++ {
+			log.Info("sampled away")
+			log.Error("always kept")
+		}
+
+		stats := log.(logger.StatsReporter).Stats()
+		if stats.Emitted != 10 || stats.Sampled != 10 {
+			t.Errorf("%s: expected 10 errors emitted and 10 infos sampled, got %+v", name, stats)
+		}
+	}
+}
+
+// TestAlwaysLogAbove 测试按字段值限流下突发的调试和错误日志中，每条错误日志都被保留，
+// 提高WithAlwaysLogAbove阈值后错误日志同样受限流影响
+func TestAlwaysLogAbove(t *testing.T) {
+	clock := &manualClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	const burst = 100
+
+	log := logger.NewConsoleLogger("app", logger.WithLevel(logger.DebugLevel), logger.WithWriter(io.Discard),
+		logger.WithClock(clock), logger.WithFieldRateLimit("error_code", 1))
+	for i := 0; i < burst; i++ {
+		log.Debug("retrying", logger.Field{Key: "error_code", Value: "TIMEOUT"})
+		log.Error("request failed", logger.Field{Key: "error_code", Value: "TIMEOUT"})
+	}
+	// 所有错误日志和限流允许的1条调试日志
+	if stats := log.Stats(); stats.Emitted != burst+1 || stats.Suppressed != burst-1 {
+		t.Errorf("Expected all %d errors and 1 debug entry to be emitted, got %+v", burst, stats)
+	}
+
+	log = logger.NewConsoleLogger("app", logger.WithWriter(io.Discard), logger.WithClock(clock),
+		logger.WithFieldRateLimit("error_code", 1), logger.WithAlwaysLogAbove(logger.FatalLevel))
+	for i := 0; i < burst; i++ {
+		log.Error("request failed", logger.Field{Key: "error_code", Value: "TIMEOUT"})
+	}
+	if stats := log.Stats(); stats.Emitted != 1 || stats.Suppressed != burst-1 {
+		t.Errorf("Expected errors below the threshold to be limited to 1, got %+v", stats)
+	}
+}
+
+// manualClock 手动推进的时钟
+type manualClock struct {
+	t time.Time
+}
+
+func (c *manualClock) Now() time.Time { return c.t }
+
+// TestFieldRateLimit 测试携带相同字段值的日志被独立限流，并按值汇总抑制条数
+func TestFieldRateLimit(t *testing.T) {
+	clock := &manualClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	newLogger := map[string]func(opts ...logger.Option) logger.Logger{
+		"console": func(opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger("app", opts...) },
+		"std":     func(opts ...logger.Option) logger.Logger { return logger.NewStdLogger("app", opts...) },
+		"zap":     func(opts ...logger.Option) logger.Logger { return logger.NewZapLogger("app", opts...) },
+		"logrus":  func(opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger("app", opts...) },
+	}
+
+	for name, create := range newLogger {
+		log := create(logger.WithWriter(io.Discard), logger.WithClock(clock), logger.WithFieldRateLimit("error_code", 3))
+
+		for i := 0; i < 10; i++ {
+			log.Warn("request failed", logger.Field{Key: "error_code", Value: "TIMEOUT"})
+			log.WithField("error_code", "REFUSED").Warn("request failed")
+			log.Warn("unrelated")
+		}
+
+		stats := log.(logger.StatsReporter).Stats()
+		if stats.Emitted != 16 || stats.Suppressed != 14 {
+			t.Errorf("%s: expected 16 emitted and 14 suppressed, got %+v", name, stats)
+		}
+		if stats.FieldSuppressed["error_code=TIMEOUT"] != 7 || stats.FieldSuppressed["error_code=REFUSED"] != 7 {
+			t.Errorf("%s: expected 7 suppressed per value, got %v", name, stats.FieldSuppressed)
+		}
+
+		// 进入下一秒后重新计数，错误级日志不受限流影响
+		clock.t = clock.t.Add(time.Second)
+		log.Warn("request failed", logger.Field{Key: "error_code", Value: "TIMEOUT"})
+		log.Error("request failed", logger.Field{Key: "error_code", Value: "REFUSED"})
+		if stats := log.(logger.StatsReporter).Stats(); stats.Emitted != 18 {
+			t.Errorf("%s: expected the limit to reset in the next second, got %+v", name, stats)
+		}
+	}
+}
+
+// TestResetSampling 测试ResetSampling清空限流计数后，被抑制的相同消息立即再次输出
+func TestResetSampling(t *testing.T) {
+	clock := &manualClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	newLogger := map[string]func(opts ...logger.Option) logger.Logger{
+		"console": func(opts ...logger.Option) logger.Logger { return logger.NewConsoleLogger("app", opts...) },
+		"std":     func(opts ...logger.Option) logger.Logger { return logger.NewStdLogger("app", opts...) },
+		"zap":     func(opts ...logger.Option) logger.Logger { return logger.NewZapLogger("app", opts...) },
+		"logrus":  func(opts ...logger.Option) logger.Logger { return logger.NewLogrusLogger("app", opts...) },
+		"func": func(opts ...logger.Option) logger.Logger {
+			return logger.NewFuncLogger("app", logger.InfoLevel, func(logger.LogLevel, string, []logger.Field) {}, opts...)
+		},
+	}
+
+	for name, create := range newLogger {
+		log := create(logger.WithWriter(io.Discard), logger.WithClock(clock), logger.WithFieldRateLimit("error_code", 2))
+		derived := log.WithField("error_code", "TIMEOUT")
+
+		for i := 0; i < 5; i++ {
+			derived.Warn("request failed")
+		}
+		if stats := log.(logger.StatsReporter).Stats(); stats.Emitted != 2 || stats.Suppressed != 3 {
+			t.Fatalf("%s: expected the limit to be reached, got %+v", name, stats)
+		}
+
+		log.(logger.SamplingResetter).ResetSampling()
+		derived.Warn("request failed")
+		derived.Warn("request failed")
+		derived.Warn("request failed")
+
+		stats := log.(logger.StatsReporter).Stats()
+		if stats.Emitted != 4 || stats.Suppressed != 4 {
+			t.Errorf("%s: expected 2 more entries after the reset in the same second, got %+v", name, stats)
+		}
+		if stats.FieldSuppressed["error_code=TIMEOUT"] != 4 {
+			t.Errorf("%s: expected the suppressed totals to be kept, got %v", name, stats.FieldSuppressed)
+		}
+	}
+}
+
+// TestSamplingSummary 测试Sync时输出自上次汇总以来被丢弃的条数，没有新的丢弃时不输出
+func TestSamplingSummary(t *testing.T) {
+	dir := t.TempDir()
+	opts := []logger.Option{
+		logger.WithFormat("json"),
+		logger.WithProbabilisticLevel(logger.DebugLevel, 0),
+		logger.WithFieldRateLimit("error_code", 1),
+		logger.WithSamplingSummary(logger.WarnLevel),
+	}
+	loggers := map[string]logger.Logger{
+		"console": logger.NewConsoleLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "console.log")))...),
+		"std":     logger.NewStdLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "std.log")))...),
+		"zap":     logger.NewZapLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "zap.log")))...),
+		"logrus":  logger.NewLogrusLogger("app", append(opts, logger.WithOutputPath(filepath.Join(dir, "logrus.log")))...),
+	}
+
+	for name, log := range loggers {
+		log.SetLevel(logger.DebugLevel)
+		for i := 0; i < 5; i++ {
+			log.Debug("trace")
+			log.Info("request failed", logger.Field{Key: "error_code", Value: "TIMEOUT"})
+		}
+		log.Sync()
+		log.Sync()
+
+		lines := readLines(t, filepath.Join(dir, name+".log"))
+		if len(lines) != 2 {
+			t.Fatalf("%s: expected 1 log and 1 summary, got %q", name, lines)
+		}
+		summary := decodeJSONLine(t, lines[1])
+		if summary["msg"] != "sampling summary" || summary["sampled"] != float64(5) || summary["suppressed"] != float64(4) {
+			t.Errorf("%s: unexpected summary %v", name, summary)
+		}
+
+		log.Debug("trace")
+		log.Sync()
+		lines = readLines(t, filepath.Join(dir, name+".log"))
+		if summary := decodeJSONLine(t, lines[len(lines)-1]); summary["sampled"] != float64(1) || summary["suppressed"] != float64(0) {
+			t.Errorf("%s: expected counts since the last summary, got %v", name, summary)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestStdlibWriter 测试通过io.Writer和*log.Logger写入的内容转换为日志
+func TestStdlibWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithOutputPath(path))
+
+	fmt.Fprintln(logger.StdlibWriter(log, logger.WarnLevel), "connection reset")
+	logger.StdlibLogger(log.WithField("component", "http"), logger.ErrorLevel).Printf("TLS handshake error from %s", "10.0.0.1")
+	log.Sync()
+
+	lines := readLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	first := decodeJSONLine(t, lines[0])
+	if first["level"] != "WARN" || first["msg"] != "connection reset" {
+		t.Errorf("Unexpected writer record: %v", first)
+	}
+
+	second := decodeJSONLine(t, lines[1])
+	if second["level"] != "ERROR" || second["msg"] != "TLS handshake error from 10.0.0.1" || second["component"] != "http" {
+		t.Errorf("Unexpected log.Logger record: %v", second)
+	}
+}
+
+// TestLeveledWriter 测试按行首的严重级别标记选择日志级别
+func TestLeveledWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	log := logger.NewConsoleLogger("app", logger.WithFormat("json"), logger.WithLevel(logger.DebugLevel), logger.WithOutputPath(path))
+	w := logger.LeveledWriter(log)
+
+	fmt.Fprint(w, "ERROR: disk full\nwarning: low memory\n\n")
+	fmt.Fprintln(w, "[DEBUG] cache miss")
+	fmt.Fprintln(w, "INFO  started")
+	fmt.Fprintln(w, "FATAL: cannot recover")
+	fmt.Fprintln(w, "Error connecting to upstream")
+	fmt.Fprintln(w, "plain line")
+	log.Sync()
+
+	expected := []struct {
+		level string
+		msg   string
+	}{
+		{"ERROR", "disk full"},
+		{"WARN", "low memory"},
+		{"DEBUG", "cache miss"},
+		{"INFO", "started"},
+		{"ERROR", "cannot recover"},
+		{"INFO", "Error connecting to upstream"},
+		{"INFO", "plain line"},
+	}
+	lines := readLines(t, path)
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected %d lines, got %q", len(expected), lines)
+	}
+	for i, e := range expected {
+		data := decodeJSONLine(t, lines[i])
+		if data["level"] != e.level || data["msg"] != e.msg {
+			t.Errorf("line %d: expected %s %q, got %v", i, e.level, e.msg, data)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+)
+
+// TestStopwatch 测试各检查点的耗时字段按名称命名，且随时间单调递增
+func TestStopwatch(t *testing.T) {
+	clock := &manualClock{t: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	sw := logger.NewStopwatch(clock)
+
+	clock.t = clock.t.Add(5 * time.Millisecond)
+	sw.Checkpoint("db")
+	clock.t = clock.t.Add(30 * time.Millisecond)
+	db := sw.SinceCheckpoint("db")
+	sw.Checkpoint("render")
+	clock.t = clock.t.Add(12 * time.Millisecond)
+	render := sw.SinceCheckpoint("render")
+	dbLater := sw.SinceCheckpoint("db")
+
+	if db.Key != "db_ms" || db.Value != int64(30) {
+		t.Errorf("Expected db_ms=30, got %v=%v", db.Key, db.Value)
+	}
+	if render.Key != "render_ms" || render.Value != int64(12) {
+		t.Errorf("Expected render_ms=12, got %v=%v", render.Key, render.Value)
+	}
+	if dbLater.Value.(int64) <= db.Value.(int64) {
+		t.Errorf("Expected db_ms to grow, got %v then %v", db.Value, dbLater.Value)
+	}
+	if elapsed := sw.Elapsed(); elapsed.Key != "elapsed_ms" || elapsed.Value != int64(47) {
+		t.Errorf("Expected elapsed_ms=47, got %v=%v", elapsed.Key, elapsed.Value)
+	}
+	if missing := sw.SinceCheckpoint("cache"); missing.Key != "cache_ms" || missing.Value != int64(47) {
+		t.Errorf("Expected an unknown checkpoint to measure from the start, got %v=%v", missing.Key, missing.Value)
+	}
+
+	var buf bytes.Buffer
+	log := logger.NewConsoleLogger("app", logger.WithWriter(&buf))
+	log.Info("request done", sw.SinceCheckpoint("db"), sw.SinceCheckpoint("render"))
+	if line := buf.String(); !strings.Contains(line, "db_ms=42 render_ms=12") {
+		t.Errorf("Unexpected output %q", line)
+	}
+}
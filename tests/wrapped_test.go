@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/LandcLi/LandcLogFace/pkg/logger"
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestNewLoggerFromZap 测试包装已有的zap实例时日志写入该实例，并由门面过滤级别和处理字段
+func TestNewLoggerFromZap(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := logger.NewLoggerFromZap("app", zap.New(core, zap.AddCaller()), logger.InfoLevel, logger.WithFieldAllowlist("request_id"))
+
+	log.Debug("filtered")
+	log.WithField("request_id", "r-1").Info("hello", logger.Field{Key: "password", Value: "secret"})
+	log.Warnf("retry %d", 2)
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.Message != "hello" || entry.Level != zapcore.InfoLevel || entry.LoggerName != "app" {
+		t.Errorf("Unexpected entry: %s %s %s", entry.Level, entry.LoggerName, entry.Message)
+	}
+	fields := entry.ContextMap()
+	if fields["request_id"] != "r-1" {
+		t.Errorf("Expected request_id=r-1, got %v", fields["request_id"])
+	}
+	if _, ok := fields["password"]; ok {
+		t.Error("Expected password to be dropped by the allowlist")
+	}
+	if file := filepath.Base(entry.Caller.File); file != "wrapped_test.go" {
+		t.Errorf("Expected caller in wrapped_test.go, got %s", entry.Caller.File)
+	}
+
+	if entries[1].Message != "retry 2" || entries[1].Level != zapcore.WarnLevel {
+		t.Errorf("Unexpected entry: %s %s", entries[1].Level, entries[1].Message)
+	}
+
+	log.SetLevel(logger.DebugLevel)
+	log.Debug("debug")
+	if logs.Len() != 3 {
+		t.Errorf("Expected SetLevel to enable debug, got %d entries", logs.Len())
+	}
+
+	if err := log.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+}
+
+// TestNewLoggerFromLogrus 测试包装已有的logrus实例时日志写入该实例，门面级别取自该实例
+func TestNewLoggerFromLogrus(t *testing.T) {
+	base, hook := logrustest.NewNullLogger()
+	base.SetLevel(logrus.WarnLevel)
+	log := logger.NewLoggerFromLogrus("app", base)
+
+	if log.GetLevel() != logger.WarnLevel {
+		t.Errorf("Expected level warn, got %s", log.GetLevel())
+	}
+
+	log.Info("filtered")
+	log.WithField("user_id", 42).Warn("slow", logger.Field{Key: "elapsed_ms", Value: 1500})
+
+	entries := hook.AllEntries()
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Message != "slow" || entry.Level != logrus.WarnLevel {
+		t.Errorf("Unexpected entry: %s %s", entry.Level, entry.Message)
+	}
+	if entry.Data["user_id"] == nil || entry.Data["elapsed_ms"] == nil {
+		t.Errorf("Expected fields to flow through, got %v", entry.Data)
+	}
+
+	base.SetFormatter(&logrus.JSONFormatter{})
+	jsonLog := logger.NewLoggerFromLogrus("app", base)
+	if info := jsonLog.Describe(); info.Format != "json" {
+		t.Errorf("Expected json format from JSONFormatter, got %q", info.Format)
+	}
+}